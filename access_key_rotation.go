@@ -0,0 +1,127 @@
+package modzy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+// APIKeySource holds an API key that can be replaced while clients created WithAPIKeySource are using it
+type APIKeySource struct {
+	sync.RWMutex
+	apiKey string
+}
+
+func NewAPIKeySource(apiKey string) *APIKeySource {
+	return &APIKeySource{apiKey: apiKey}
+}
+
+// APIKey returns the current key
+func (s *APIKeySource) APIKey() string {
+	s.RLock()
+	defer s.RUnlock()
+	return s.apiKey
+}
+
+// SetAPIKey replaces the key used by every following request
+func (s *APIKeySource) SetAPIKey(apiKey string) {
+	s.Lock()
+	defer s.Unlock()
+	s.apiKey = apiKey
+}
+
+// Switch can be used as the RotateAccessKeyInput.Switch to move the source over to the new key
+func (s *APIKeySource) Switch(ctx context.Context, apiKey string) error {
+	s.SetAPIKey(apiKey)
+	return nil
+}
+
+type RotateAccessKeyInput struct {
+	ProjectID string
+	// Prefix is the access key being replaced
+	Prefix string
+	// Name and Description are used for the new key; they default to those of the replaced key
+	Name        string
+	Description string
+	// Switch moves whatever uses the old key over to the new one, such as APIKeySource.Switch or writing a secret store
+	Switch func(ctx context.Context, apiKey string) error
+	// GracePeriod is how long the old key keeps working after the switch, so that requests already using it can finish
+	GracePeriod time.Duration
+}
+
+type RotateAccessKeyOutput struct {
+	AccessKey model.AccessKey
+	// APIKey is the full new key
+	APIKey string
+	// Revoked is true once the old key has been revoked
+	Revoked bool
+}
+
+// RotateAccessKey creates a new access key with the same priority as the old one, switches over to it, waits for the
+// grace period and then revokes the old key.  If the switch fails the new key is revoked and the old one is left alone.
+// If the context is done during the grace period, the output is returned along with the context error and the old key
+// is still active.
+func RotateAccessKey(ctx context.Context, client AccountingClient, input *RotateAccessKeyInput) (*RotateAccessKeyOutput, error) {
+	if input.Switch == nil {
+		return nil, errors.New("a Switch function is required")
+	}
+	project, err := client.GetProjectDetails(ctx, &GetProjectDetailsInput{ProjectID: input.ProjectID})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read the project")
+	}
+	var old *model.AccessKey
+	for i := range project.Project.AccessKeys {
+		if project.Project.AccessKeys[i].Prefix == input.Prefix {
+			old = &project.Project.AccessKeys[i]
+		}
+	}
+	if old == nil {
+		return nil, errors.WithMessagef(ErrNotFound, "access key %s is not part of project %s", input.Prefix, input.ProjectID)
+	}
+
+	name, description := input.Name, input.Description
+	if name == "" {
+		name = old.Name
+	}
+	if description == "" {
+		description = old.Description
+	}
+	created, err := client.CreateAccessKey(ctx, &CreateAccessKeyInput{
+		ProjectID:      input.ProjectID,
+		Name:           name,
+		Description:    description,
+		IsHighPriority: old.IsHighPriority,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create the new access key")
+	}
+
+	if err := input.Switch(ctx, created.APIKey); err != nil {
+		if _, revokeErr := client.RevokeAccessKey(ctx, &RevokeAccessKeyInput{ProjectID: input.ProjectID, Prefix: created.AccessKey.Prefix}); revokeErr != nil {
+			return nil, errors.WithMessagef(err, "failed to switch to the new access key, which could not be revoked (%v)", revokeErr)
+		}
+		return nil, errors.WithMessage(err, "failed to switch to the new access key")
+	}
+
+	out := &RotateAccessKeyOutput{
+		AccessKey: created.AccessKey,
+		APIKey:    created.APIKey,
+	}
+	if input.GracePeriod > 0 {
+		timer := time.NewTimer(input.GracePeriod)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return out, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if _, err := client.RevokeAccessKey(ctx, &RevokeAccessKeyInput{ProjectID: input.ProjectID, Prefix: input.Prefix}); err != nil {
+		return out, errors.WithMessage(err, "failed to revoke the old access key")
+	}
+	out.Revoked = true
+	return out, nil
+}
@@ -0,0 +1,145 @@
+package modzy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+func TestRotateAccessKey(t *testing.T) {
+	revoked := []string{}
+	accounting := &AccountingClientFake{
+		GetProjectDetailsFunc: func(ctx context.Context, input *GetProjectDetailsInput) (*GetProjectDetailsOutput, error) {
+			return &GetProjectDetailsOutput{Project: model.AccountingProject{
+				Identifier: input.ProjectID,
+				AccessKeys: []model.AccessKey{{Prefix: "old", Name: "ci", Description: "pipeline", IsHighPriority: true}},
+			}}, nil
+		},
+		CreateAccessKeyFunc: func(ctx context.Context, input *CreateAccessKeyInput) (*CreateAccessKeyOutput, error) {
+			if input.Name != "ci" || input.Description != "pipeline" || !input.IsHighPriority {
+				return nil, fmt.Errorf("new key does not match the old one: %+v", input)
+			}
+			return &CreateAccessKeyOutput{AccessKey: model.AccessKey{Prefix: "new"}, APIKey: "new.secret"}, nil
+		},
+		RevokeAccessKeyFunc: func(ctx context.Context, input *RevokeAccessKeyInput) (*RevokeAccessKeyOutput, error) {
+			revoked = append(revoked, input.Prefix)
+			return &RevokeAccessKeyOutput{}, nil
+		},
+	}
+	source := NewAPIKeySource("old.secret")
+	switched := time.Time{}
+	out, err := RotateAccessKey(context.TODO(), accounting, &RotateAccessKeyInput{
+		ProjectID: "proj",
+		Prefix:    "old",
+		Switch: func(ctx context.Context, apiKey string) error {
+			switched = time.Now()
+			return source.Switch(ctx, apiKey)
+		},
+		GracePeriod: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !out.Revoked || out.APIKey != "new.secret" || source.APIKey() != "new.secret" {
+		t.Errorf("Expected the key to be rotated, got %+v", out)
+	}
+	if len(revoked) != 1 || revoked[0] != "old" {
+		t.Errorf("Expected only the old key to be revoked, got %v", revoked)
+	}
+	if time.Since(switched) < 10*time.Millisecond {
+		t.Errorf("Expected the grace period before revoking")
+	}
+}
+
+func TestRotateAccessKeySwitchFails(t *testing.T) {
+	revoked := []string{}
+	accounting := &AccountingClientFake{
+		GetProjectDetailsFunc: func(ctx context.Context, input *GetProjectDetailsInput) (*GetProjectDetailsOutput, error) {
+			return &GetProjectDetailsOutput{Project: model.AccountingProject{
+				Identifier: input.ProjectID,
+				AccessKeys: []model.AccessKey{{Prefix: "old", Name: "ci", Description: "pipeline", IsHighPriority: true}},
+			}}, nil
+		},
+		CreateAccessKeyFunc: func(ctx context.Context, input *CreateAccessKeyInput) (*CreateAccessKeyOutput, error) {
+			return &CreateAccessKeyOutput{AccessKey: model.AccessKey{Prefix: "new"}, APIKey: "new.secret"}, nil
+		},
+		RevokeAccessKeyFunc: func(ctx context.Context, input *RevokeAccessKeyInput) (*RevokeAccessKeyOutput, error) {
+			revoked = append(revoked, input.Prefix)
+			return &RevokeAccessKeyOutput{}, nil
+		},
+	}
+	_, err := RotateAccessKey(context.TODO(), accounting, &RotateAccessKeyInput{
+		ProjectID: "proj",
+		Prefix:    "old",
+		Switch: func(ctx context.Context, apiKey string) error {
+			return fmt.Errorf("secret store unavailable")
+		},
+	})
+	if err == nil {
+		t.Fatalf("Expected an error")
+	}
+	if len(revoked) != 1 || revoked[0] != "new" {
+		t.Errorf("Expected the new key to be revoked, got %v", revoked)
+	}
+}
+
+func TestRotateAccessKeyCanceledDuringGracePeriod(t *testing.T) {
+	revoked := []string{}
+	accounting := &AccountingClientFake{
+		GetProjectDetailsFunc: func(ctx context.Context, input *GetProjectDetailsInput) (*GetProjectDetailsOutput, error) {
+			return &GetProjectDetailsOutput{Project: model.AccountingProject{
+				Identifier: input.ProjectID,
+				AccessKeys: []model.AccessKey{{Prefix: "old", Name: "ci", Description: "pipeline", IsHighPriority: true}},
+			}}, nil
+		},
+		CreateAccessKeyFunc: func(ctx context.Context, input *CreateAccessKeyInput) (*CreateAccessKeyOutput, error) {
+			return &CreateAccessKeyOutput{AccessKey: model.AccessKey{Prefix: "new"}, APIKey: "new.secret"}, nil
+		},
+		RevokeAccessKeyFunc: func(ctx context.Context, input *RevokeAccessKeyInput) (*RevokeAccessKeyOutput, error) {
+			revoked = append(revoked, input.Prefix)
+			return &RevokeAccessKeyOutput{}, nil
+		},
+	}
+	ctx, cancel := context.WithCancel(context.TODO())
+	out, err := RotateAccessKey(ctx, accounting, &RotateAccessKeyInput{
+		ProjectID: "proj",
+		Prefix:    "old",
+		Switch: func(ctx context.Context, apiKey string) error {
+			cancel()
+			return nil
+		},
+		GracePeriod: time.Hour,
+	})
+	if err != context.Canceled {
+		t.Errorf("Expected context canceled, got %v", err)
+	}
+	if out == nil || out.Revoked || out.APIKey != "new.secret" || len(revoked) != 0 {
+		t.Errorf("Expected the new key without revoking the old one, got %+v %v", out, revoked)
+	}
+}
+
+func TestRotateAccessKeyUnknownKey(t *testing.T) {
+	accounting := &AccountingClientFake{
+		GetProjectDetailsFunc: func(ctx context.Context, input *GetProjectDetailsInput) (*GetProjectDetailsOutput, error) {
+			return &GetProjectDetailsOutput{Project: model.AccountingProject{
+				Identifier: input.ProjectID,
+				AccessKeys: []model.AccessKey{{Prefix: "old"}},
+			}}, nil
+		},
+	}
+	_, err := RotateAccessKey(context.TODO(), accounting, &RotateAccessKeyInput{
+		ProjectID: "proj",
+		Prefix:    "missing",
+		Switch:    NewAPIKeySource("").Switch,
+	})
+	if errors.Cause(err) != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if _, err := RotateAccessKey(context.TODO(), accounting, &RotateAccessKeyInput{}); err == nil {
+		t.Errorf("Expected an error without a switch")
+	}
+}
@@ -3,16 +3,21 @@ package modzy
 import (
 	"context"
 	"fmt"
-	"sync"
+	"io"
 
 	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
 )
 
 type AccountingClient interface {
 	// GetEntitlements will get all of your entitlements
 	GetEntitlements(ctx context.Context) (*GetEntitlementsOutput, error)
-	// HasEntitlement will return true if you have the provided entitlement is
+	// HasEntitlement will return true if you have the provided entitlement.  Entitlements are cached, see WithEntitlementCacheTTL.
 	HasEntitlement(ctx context.Context, entitlement string) (bool, error)
+	// RequireEntitlements returns a *MissingEntitlementsError listing any of the provided entitlements that you do not have
+	RequireEntitlements(ctx context.Context, entitlements ...string) error
+	// InvalidateEntitlements drops the cached entitlements so that they are read again when next needed
+	InvalidateEntitlements()
 	// GetLicense returns a truncated view of your license information
 	GetLicense(ctx context.Context) (*GetLicenseOutput, error)
 	// ListAccountingUsers returns account user information
@@ -21,47 +26,111 @@ type AccountingClient interface {
 	ListProjects(ctx context.Context, input *ListProjectsInput) (*ListProjectsOutput, error)
 	// GetProjectDetails will read the details about a project
 	GetProjectDetails(ctx context.Context, input *GetProjectDetailsInput) (*GetProjectDetailsOutput, error)
+	// CreateProject creates a new project
+	CreateProject(ctx context.Context, input *CreateProjectInput) (*CreateProjectOutput, error)
+	// UpdateProject changes the name, description or visibility of a project
+	UpdateProject(ctx context.Context, input *UpdateProjectInput) (*UpdateProjectOutput, error)
+	// ArchiveProject archives a project, which stops its access keys from being used
+	ArchiveProject(ctx context.Context, input *ArchiveProjectInput) (*ArchiveProjectOutput, error)
+	// CreateAccessKey creates a new access key within a project.  The full key is only returned here.
+	CreateAccessKey(ctx context.Context, input *CreateAccessKeyInput) (*CreateAccessKeyOutput, error)
+	// UpdateAccessKey changes an access key, such as making it high priority
+	UpdateAccessKey(ctx context.Context, input *UpdateAccessKeyInput) (*UpdateAccessKeyOutput, error)
+	// RevokeAccessKey revokes an access key so that it can no longer be used
+	RevokeAccessKey(ctx context.Context, input *RevokeAccessKeyInput) (*RevokeAccessKeyOutput, error)
+	// GetAccountingUserDetails reads a user along with their roles, entitlements, teams and access keys
+	GetAccountingUserDetails(ctx context.Context, input *GetAccountingUserDetailsInput) (*GetAccountingUserDetailsOutput, error)
+	// InviteAccountingUser sends an invitation to join the account
+	InviteAccountingUser(ctx context.Context, input *InviteAccountingUserInput) (*InviteAccountingUserOutput, error)
+	// DeactivateAccountingUser stops a user from signing in or using their access keys
+	DeactivateAccountingUser(ctx context.Context, input *DeactivateAccountingUserInput) (*DeactivateAccountingUserOutput, error)
+	// ReactivateAccountingUser restores a deactivated user
+	ReactivateAccountingUser(ctx context.Context, input *ReactivateAccountingUserInput) (*ReactivateAccountingUserOutput, error)
+	// ListRoles lists the roles that can be assigned to users
+	ListRoles(ctx context.Context) (*ListRolesOutput, error)
+	// SetAccountingUserRoles replaces the roles of a user
+	SetAccountingUserRoles(ctx context.Context, input *SetAccountingUserRolesInput) (*SetAccountingUserRolesOutput, error)
+	// SetAccountingUserEntitlements replaces the entitlements of a user
+	SetAccountingUserEntitlements(ctx context.Context, input *SetAccountingUserEntitlementsInput) (*SetAccountingUserEntitlementsOutput, error)
+	// ListTeams will list your teams
+	ListTeams(ctx context.Context, input *ListTeamsInput) (*ListTeamsOutput, error)
+	// GetTeamDetails reads a team along with its members
+	GetTeamDetails(ctx context.Context, input *GetTeamDetailsInput) (*GetTeamDetailsOutput, error)
+	// AddTeamMembers adds users to a team
+	AddTeamMembers(ctx context.Context, input *AddTeamMembersInput) (*AddTeamMembersOutput, error)
+	// RemoveTeamMember removes a user from a team
+	RemoveTeamMember(ctx context.Context, input *RemoveTeamMemberInput) (*RemoveTeamMemberOutput, error)
 }
 
 type standardAccountingClient struct {
-	sync.Mutex
-	baseClient       *standardClient
-	entitlementCache []model.Entitlement
+	baseClient   *standardClient
+	entitlements *entitlementCache
 }
 
 var _ AccountingClient = &standardAccountingClient{}
 
 func (c *standardAccountingClient) GetEntitlements(ctx context.Context) (*GetEntitlementsOutput, error) {
-	var out []model.Entitlement
-	url := "/api/accounting/entitlements"
-	_, err := c.baseClient.requestor.Get(ctx, url, &out)
+	out, err := c.readEntitlements(ctx)
 	if err != nil {
 		return nil, err
 	}
+	c.entitlements.set(out)
 
 	return &GetEntitlementsOutput{
 		Entitlements: out,
 	}, nil
 }
 
+func (c *standardAccountingClient) readEntitlements(ctx context.Context) ([]model.Entitlement, error) {
+	var out []model.Entitlement
+	url := "/api/accounting/entitlements"
+	_, err := c.baseClient.requestor.Get(ctx, url, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *standardAccountingClient) HasEntitlement(ctx context.Context, entitlement string) (bool, error) {
-	c.Lock()
-	defer c.Unlock()
-	if c.entitlementCache == nil {
-		out, err := c.GetEntitlements(ctx)
-		if err != nil {
-			return false, err
-		}
-		c.entitlementCache = out.Entitlements
+	entitlements, err := c.entitlements.get(ctx, c.readEntitlements)
+	if err != nil {
+		return false, err
 	}
 
-	for _, e := range c.entitlementCache {
+	for _, e := range entitlements {
 		if e.Identifier == entitlement {
 			return true, nil
 		}
 	}
 	return false, nil
 }
+
+func (c *standardAccountingClient) RequireEntitlements(ctx context.Context, entitlements ...string) error {
+	have, err := c.entitlements.get(ctx, c.readEntitlements)
+	if err != nil {
+		return err
+	}
+
+	haveByID := map[string]bool{}
+	for _, e := range have {
+		haveByID[e.Identifier] = true
+	}
+	var missing []string
+	for _, entitlement := range entitlements {
+		if !haveByID[entitlement] {
+			missing = append(missing, entitlement)
+		}
+	}
+	if len(missing) > 0 {
+		return &MissingEntitlementsError{Missing: missing}
+	}
+	return nil
+}
+
+func (c *standardAccountingClient) InvalidateEntitlements() {
+	c.entitlements.invalidate()
+}
+
 func (c *standardAccountingClient) GetLicense(ctx context.Context) (*GetLicenseOutput, error) {
 	var out model.License
 	url := "/api/license"
@@ -135,3 +204,247 @@ func (c *standardAccountingClient) GetProjectDetails(ctx context.Context, input
 		Project: out,
 	}, nil
 }
+
+func (c *standardAccountingClient) CreateProject(ctx context.Context, input *CreateProjectInput) (*CreateProjectOutput, error) {
+	var out model.AccountingProject
+	url := "/api/accounting/projects"
+	_, err := c.baseClient.requestor.Post(ctx, url, input, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateProjectOutput{
+		Project: out,
+	}, nil
+}
+
+func (c *standardAccountingClient) UpdateProject(ctx context.Context, input *UpdateProjectInput) (*UpdateProjectOutput, error) {
+	var out model.AccountingProject
+	url := fmt.Sprintf("/api/accounting/projects/%s", input.ProjectID)
+	_, err := c.baseClient.requestor.Patch(ctx, url, input, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdateProjectOutput{
+		Project: out,
+	}, nil
+}
+
+func (c *standardAccountingClient) ArchiveProject(ctx context.Context, input *ArchiveProjectInput) (*ArchiveProjectOutput, error) {
+	var out model.AccountingProject
+	url := fmt.Sprintf("/api/accounting/projects/%s/archive", input.ProjectID)
+	_, err := c.baseClient.requestor.Post(ctx, url, nil, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArchiveProjectOutput{
+		Project: out,
+	}, nil
+}
+
+func (c *standardAccountingClient) CreateAccessKey(ctx context.Context, input *CreateAccessKeyInput) (*CreateAccessKeyOutput, error) {
+	var out struct {
+		model.AccessKey
+		SecretKey string `json:"secretKey"`
+	}
+	url := fmt.Sprintf("/api/accounting/projects/%s/access-keys", input.ProjectID)
+	_, err := c.baseClient.requestor.Post(ctx, url, input, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateAccessKeyOutput{
+		AccessKey: out.AccessKey,
+		APIKey:    fmt.Sprintf("%s.%s", out.Prefix, out.SecretKey),
+	}, nil
+}
+
+func (c *standardAccountingClient) UpdateAccessKey(ctx context.Context, input *UpdateAccessKeyInput) (*UpdateAccessKeyOutput, error) {
+	var out model.AccessKey
+	url := fmt.Sprintf("/api/accounting/projects/%s/access-keys/%s", input.ProjectID, input.Prefix)
+	_, err := c.baseClient.requestor.Patch(ctx, url, input, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdateAccessKeyOutput{
+		AccessKey: out,
+	}, nil
+}
+
+func (c *standardAccountingClient) RevokeAccessKey(ctx context.Context, input *RevokeAccessKeyInput) (*RevokeAccessKeyOutput, error) {
+	out := model.AccessKey{Prefix: input.Prefix}
+	url := fmt.Sprintf("/api/accounting/projects/%s/access-keys/%s", input.ProjectID, input.Prefix)
+	// the revoked key may not be returned
+	_, err := c.baseClient.requestor.Delete(ctx, url, &out)
+	if err != nil && errors.Cause(err) != io.EOF {
+		return nil, err
+	}
+
+	return &RevokeAccessKeyOutput{
+		AccessKey: out,
+	}, nil
+}
+
+func (c *standardAccountingClient) GetAccountingUserDetails(ctx context.Context, input *GetAccountingUserDetailsInput) (*GetAccountingUserDetailsOutput, error) {
+	var out model.AccountingUserDetails
+	url := fmt.Sprintf("/api/accounting/users/%s", input.UserID)
+	_, err := c.baseClient.requestor.Get(ctx, url, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetAccountingUserDetailsOutput{
+		User: out,
+	}, nil
+}
+
+func (c *standardAccountingClient) InviteAccountingUser(ctx context.Context, input *InviteAccountingUserInput) (*InviteAccountingUserOutput, error) {
+	var out model.AccountingUser
+	url := "/api/accounting/users"
+	_, err := c.baseClient.requestor.Post(ctx, url, input, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InviteAccountingUserOutput{
+		User: out,
+	}, nil
+}
+
+func (c *standardAccountingClient) DeactivateAccountingUser(ctx context.Context, input *DeactivateAccountingUserInput) (*DeactivateAccountingUserOutput, error) {
+	var out model.AccountingUser
+	url := fmt.Sprintf("/api/accounting/users/%s/deactivate", input.UserID)
+	_, err := c.baseClient.requestor.Post(ctx, url, nil, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeactivateAccountingUserOutput{
+		User: out,
+	}, nil
+}
+
+func (c *standardAccountingClient) ReactivateAccountingUser(ctx context.Context, input *ReactivateAccountingUserInput) (*ReactivateAccountingUserOutput, error) {
+	var out model.AccountingUser
+	url := fmt.Sprintf("/api/accounting/users/%s/reactivate", input.UserID)
+	_, err := c.baseClient.requestor.Post(ctx, url, nil, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReactivateAccountingUserOutput{
+		User: out,
+	}, nil
+}
+
+func (c *standardAccountingClient) ListRoles(ctx context.Context) (*ListRolesOutput, error) {
+	var out []model.Role
+	url := "/api/accounting/roles"
+	_, err := c.baseClient.requestor.Get(ctx, url, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListRolesOutput{
+		Roles: out,
+	}, nil
+}
+
+func (c *standardAccountingClient) SetAccountingUserRoles(ctx context.Context, input *SetAccountingUserRolesInput) (*SetAccountingUserRolesOutput, error) {
+	var out model.AccountingUserDetails
+	url := fmt.Sprintf("/api/accounting/users/%s/roles", input.UserID)
+	_, err := c.baseClient.requestor.Put(ctx, url, input, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	// the change may have been to the entitlements of our own key
+	c.InvalidateEntitlements()
+
+	return &SetAccountingUserRolesOutput{
+		User: out,
+	}, nil
+}
+
+func (c *standardAccountingClient) SetAccountingUserEntitlements(ctx context.Context, input *SetAccountingUserEntitlementsInput) (*SetAccountingUserEntitlementsOutput, error) {
+	var out model.AccountingUserDetails
+	url := fmt.Sprintf("/api/accounting/users/%s/entitlements", input.UserID)
+	_, err := c.baseClient.requestor.Put(ctx, url, input, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	// the change may have been to the entitlements of our own key
+	c.InvalidateEntitlements()
+
+	return &SetAccountingUserEntitlementsOutput{
+		User: out,
+	}, nil
+}
+
+func (c *standardAccountingClient) ListTeams(ctx context.Context, input *ListTeamsInput) (*ListTeamsOutput, error) {
+	input.Paging = input.Paging.withDefaults()
+
+	var items []model.AccountingTeam
+	url := "/api/accounting/teams"
+	_, links, err := c.baseClient.requestor.List(ctx, url, input.Paging, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	// decide if we have a next page (the next link is not always accurate?)
+	var nextPage *ListTeamsInput
+	if _, hasNextLink := links["next"]; len(items) == input.Paging.PerPage && hasNextLink {
+		nextPage = &ListTeamsInput{
+			Paging: input.Paging.Next(),
+		}
+	}
+
+	return &ListTeamsOutput{
+		Teams:    items,
+		NextPage: nextPage,
+	}, nil
+}
+
+func (c *standardAccountingClient) GetTeamDetails(ctx context.Context, input *GetTeamDetailsInput) (*GetTeamDetailsOutput, error) {
+	var out model.AccountingTeam
+	url := fmt.Sprintf("/api/accounting/teams/%s", input.TeamID)
+	_, err := c.baseClient.requestor.Get(ctx, url, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetTeamDetailsOutput{
+		Team: out,
+	}, nil
+}
+
+func (c *standardAccountingClient) AddTeamMembers(ctx context.Context, input *AddTeamMembersInput) (*AddTeamMembersOutput, error) {
+	var out model.AccountingTeam
+	url := fmt.Sprintf("/api/accounting/teams/%s/members", input.TeamID)
+	_, err := c.baseClient.requestor.Post(ctx, url, input, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AddTeamMembersOutput{
+		Team: out,
+	}, nil
+}
+
+func (c *standardAccountingClient) RemoveTeamMember(ctx context.Context, input *RemoveTeamMemberInput) (*RemoveTeamMemberOutput, error) {
+	out := model.AccountingTeam{Identifier: input.TeamID}
+	url := fmt.Sprintf("/api/accounting/teams/%s/members/%s", input.TeamID, input.UserID)
+	// the updated team may not be returned
+	_, err := c.baseClient.requestor.Delete(ctx, url, &out)
+	if err != nil && errors.Cause(err) != io.EOF {
+		return nil, err
+	}
+
+	return &RemoveTeamMemberOutput{
+		Team: out,
+	}, nil
+}
@@ -6,12 +6,31 @@ import (
 
 // AccountingClientFake is meant to help in mocking the AccountingClient interface easily for unit testing.
 type AccountingClientFake struct {
-	GetEntitlementsFunc     func(ctx context.Context) (*GetEntitlementsOutput, error)
-	HasEntitlementFunc      func(ctx context.Context, entitlement string) (bool, error)
-	GetLicenseFunc          func(ctx context.Context) (*GetLicenseOutput, error)
-	ListAccountingUsersFunc func(ctx context.Context, input *ListAccountingUsersInput) (*ListAccountingUsersOutput, error)
-	ListProjectsFunc        func(ctx context.Context, input *ListProjectsInput) (*ListProjectsOutput, error)
-	GetProjectDetailsFunc   func(ctx context.Context, input *GetProjectDetailsInput) (*GetProjectDetailsOutput, error)
+	GetEntitlementsFunc               func(ctx context.Context) (*GetEntitlementsOutput, error)
+	RequireEntitlementsFunc           func(ctx context.Context, entitlements ...string) error
+	InvalidateEntitlementsFunc        func()
+	HasEntitlementFunc                func(ctx context.Context, entitlement string) (bool, error)
+	GetLicenseFunc                    func(ctx context.Context) (*GetLicenseOutput, error)
+	ListAccountingUsersFunc           func(ctx context.Context, input *ListAccountingUsersInput) (*ListAccountingUsersOutput, error)
+	ListProjectsFunc                  func(ctx context.Context, input *ListProjectsInput) (*ListProjectsOutput, error)
+	GetProjectDetailsFunc             func(ctx context.Context, input *GetProjectDetailsInput) (*GetProjectDetailsOutput, error)
+	CreateProjectFunc                 func(ctx context.Context, input *CreateProjectInput) (*CreateProjectOutput, error)
+	UpdateProjectFunc                 func(ctx context.Context, input *UpdateProjectInput) (*UpdateProjectOutput, error)
+	ArchiveProjectFunc                func(ctx context.Context, input *ArchiveProjectInput) (*ArchiveProjectOutput, error)
+	CreateAccessKeyFunc               func(ctx context.Context, input *CreateAccessKeyInput) (*CreateAccessKeyOutput, error)
+	UpdateAccessKeyFunc               func(ctx context.Context, input *UpdateAccessKeyInput) (*UpdateAccessKeyOutput, error)
+	RevokeAccessKeyFunc               func(ctx context.Context, input *RevokeAccessKeyInput) (*RevokeAccessKeyOutput, error)
+	GetAccountingUserDetailsFunc      func(ctx context.Context, input *GetAccountingUserDetailsInput) (*GetAccountingUserDetailsOutput, error)
+	InviteAccountingUserFunc          func(ctx context.Context, input *InviteAccountingUserInput) (*InviteAccountingUserOutput, error)
+	DeactivateAccountingUserFunc      func(ctx context.Context, input *DeactivateAccountingUserInput) (*DeactivateAccountingUserOutput, error)
+	ReactivateAccountingUserFunc      func(ctx context.Context, input *ReactivateAccountingUserInput) (*ReactivateAccountingUserOutput, error)
+	ListRolesFunc                     func(ctx context.Context) (*ListRolesOutput, error)
+	SetAccountingUserRolesFunc        func(ctx context.Context, input *SetAccountingUserRolesInput) (*SetAccountingUserRolesOutput, error)
+	SetAccountingUserEntitlementsFunc func(ctx context.Context, input *SetAccountingUserEntitlementsInput) (*SetAccountingUserEntitlementsOutput, error)
+	ListTeamsFunc                     func(ctx context.Context, input *ListTeamsInput) (*ListTeamsOutput, error)
+	GetTeamDetailsFunc                func(ctx context.Context, input *GetTeamDetailsInput) (*GetTeamDetailsOutput, error)
+	AddTeamMembersFunc                func(ctx context.Context, input *AddTeamMembersInput) (*AddTeamMembersOutput, error)
+	RemoveTeamMemberFunc              func(ctx context.Context, input *RemoveTeamMemberInput) (*RemoveTeamMemberOutput, error)
 }
 
 var _ AccountingClient = &AccountingClientFake{}
@@ -24,6 +43,14 @@ func (c *AccountingClientFake) HasEntitlement(ctx context.Context, entitlement s
 	return c.HasEntitlementFunc(ctx, entitlement)
 }
 
+func (c *AccountingClientFake) RequireEntitlements(ctx context.Context, entitlements ...string) error {
+	return c.RequireEntitlementsFunc(ctx, entitlements...)
+}
+
+func (c *AccountingClientFake) InvalidateEntitlements() {
+	c.InvalidateEntitlementsFunc()
+}
+
 func (c *AccountingClientFake) GetLicense(ctx context.Context) (*GetLicenseOutput, error) {
 	return c.GetLicenseFunc(ctx)
 }
@@ -39,3 +66,71 @@ func (c *AccountingClientFake) ListProjects(ctx context.Context, input *ListProj
 func (c *AccountingClientFake) GetProjectDetails(ctx context.Context, input *GetProjectDetailsInput) (*GetProjectDetailsOutput, error) {
 	return c.GetProjectDetailsFunc(ctx, input)
 }
+
+func (c *AccountingClientFake) CreateProject(ctx context.Context, input *CreateProjectInput) (*CreateProjectOutput, error) {
+	return c.CreateProjectFunc(ctx, input)
+}
+
+func (c *AccountingClientFake) UpdateProject(ctx context.Context, input *UpdateProjectInput) (*UpdateProjectOutput, error) {
+	return c.UpdateProjectFunc(ctx, input)
+}
+
+func (c *AccountingClientFake) ArchiveProject(ctx context.Context, input *ArchiveProjectInput) (*ArchiveProjectOutput, error) {
+	return c.ArchiveProjectFunc(ctx, input)
+}
+
+func (c *AccountingClientFake) CreateAccessKey(ctx context.Context, input *CreateAccessKeyInput) (*CreateAccessKeyOutput, error) {
+	return c.CreateAccessKeyFunc(ctx, input)
+}
+
+func (c *AccountingClientFake) UpdateAccessKey(ctx context.Context, input *UpdateAccessKeyInput) (*UpdateAccessKeyOutput, error) {
+	return c.UpdateAccessKeyFunc(ctx, input)
+}
+
+func (c *AccountingClientFake) RevokeAccessKey(ctx context.Context, input *RevokeAccessKeyInput) (*RevokeAccessKeyOutput, error) {
+	return c.RevokeAccessKeyFunc(ctx, input)
+}
+
+func (c *AccountingClientFake) GetAccountingUserDetails(ctx context.Context, input *GetAccountingUserDetailsInput) (*GetAccountingUserDetailsOutput, error) {
+	return c.GetAccountingUserDetailsFunc(ctx, input)
+}
+
+func (c *AccountingClientFake) InviteAccountingUser(ctx context.Context, input *InviteAccountingUserInput) (*InviteAccountingUserOutput, error) {
+	return c.InviteAccountingUserFunc(ctx, input)
+}
+
+func (c *AccountingClientFake) DeactivateAccountingUser(ctx context.Context, input *DeactivateAccountingUserInput) (*DeactivateAccountingUserOutput, error) {
+	return c.DeactivateAccountingUserFunc(ctx, input)
+}
+
+func (c *AccountingClientFake) ReactivateAccountingUser(ctx context.Context, input *ReactivateAccountingUserInput) (*ReactivateAccountingUserOutput, error) {
+	return c.ReactivateAccountingUserFunc(ctx, input)
+}
+
+func (c *AccountingClientFake) ListRoles(ctx context.Context) (*ListRolesOutput, error) {
+	return c.ListRolesFunc(ctx)
+}
+
+func (c *AccountingClientFake) SetAccountingUserRoles(ctx context.Context, input *SetAccountingUserRolesInput) (*SetAccountingUserRolesOutput, error) {
+	return c.SetAccountingUserRolesFunc(ctx, input)
+}
+
+func (c *AccountingClientFake) SetAccountingUserEntitlements(ctx context.Context, input *SetAccountingUserEntitlementsInput) (*SetAccountingUserEntitlementsOutput, error) {
+	return c.SetAccountingUserEntitlementsFunc(ctx, input)
+}
+
+func (c *AccountingClientFake) ListTeams(ctx context.Context, input *ListTeamsInput) (*ListTeamsOutput, error) {
+	return c.ListTeamsFunc(ctx, input)
+}
+
+func (c *AccountingClientFake) GetTeamDetails(ctx context.Context, input *GetTeamDetailsInput) (*GetTeamDetailsOutput, error) {
+	return c.GetTeamDetailsFunc(ctx, input)
+}
+
+func (c *AccountingClientFake) AddTeamMembers(ctx context.Context, input *AddTeamMembersInput) (*AddTeamMembersOutput, error) {
+	return c.AddTeamMembersFunc(ctx, input)
+}
+
+func (c *AccountingClientFake) RemoveTeamMember(ctx context.Context, input *RemoveTeamMemberInput) (*RemoveTeamMemberOutput, error) {
+	return c.RemoveTeamMemberFunc(ctx, input)
+}
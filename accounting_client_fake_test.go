@@ -28,6 +28,19 @@ func TestAccountingClientFake(t *testing.T) {
 			}
 			return false, nil
 		},
+		RequireEntitlementsFunc: func(ctx context.Context, entitlements ...string) error {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if len(entitlements) != 2 {
+				t.Errorf("entitlements were not passed through")
+			}
+			return nil
+		},
+		InvalidateEntitlementsFunc: func() {
+			calls++
+		},
 		GetLicenseFunc: func(ctx context.Context) (*GetLicenseOutput, error) {
 			calls++
 			if ctx != expectedCtx {
@@ -65,16 +78,202 @@ func TestAccountingClientFake(t *testing.T) {
 			}
 			return nil, nil
 		},
+		CreateProjectFunc: func(ctx context.Context, input *CreateProjectInput) (*CreateProjectOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		UpdateProjectFunc: func(ctx context.Context, input *UpdateProjectInput) (*UpdateProjectOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		ArchiveProjectFunc: func(ctx context.Context, input *ArchiveProjectInput) (*ArchiveProjectOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		CreateAccessKeyFunc: func(ctx context.Context, input *CreateAccessKeyInput) (*CreateAccessKeyOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		UpdateAccessKeyFunc: func(ctx context.Context, input *UpdateAccessKeyInput) (*UpdateAccessKeyOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		RevokeAccessKeyFunc: func(ctx context.Context, input *RevokeAccessKeyInput) (*RevokeAccessKeyOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		GetAccountingUserDetailsFunc: func(ctx context.Context, input *GetAccountingUserDetailsInput) (*GetAccountingUserDetailsOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		InviteAccountingUserFunc: func(ctx context.Context, input *InviteAccountingUserInput) (*InviteAccountingUserOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		DeactivateAccountingUserFunc: func(ctx context.Context, input *DeactivateAccountingUserInput) (*DeactivateAccountingUserOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		ReactivateAccountingUserFunc: func(ctx context.Context, input *ReactivateAccountingUserInput) (*ReactivateAccountingUserOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		ListRolesFunc: func(ctx context.Context) (*ListRolesOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			return nil, nil
+		},
+		SetAccountingUserRolesFunc: func(ctx context.Context, input *SetAccountingUserRolesInput) (*SetAccountingUserRolesOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		SetAccountingUserEntitlementsFunc: func(ctx context.Context, input *SetAccountingUserEntitlementsInput) (*SetAccountingUserEntitlementsOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		ListTeamsFunc: func(ctx context.Context, input *ListTeamsInput) (*ListTeamsOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		GetTeamDetailsFunc: func(ctx context.Context, input *GetTeamDetailsInput) (*GetTeamDetailsOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		AddTeamMembersFunc: func(ctx context.Context, input *AddTeamMembersInput) (*AddTeamMembersOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		RemoveTeamMemberFunc: func(ctx context.Context, input *RemoveTeamMemberInput) (*RemoveTeamMemberOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
 	}
 
 	fake.GetEntitlements(expectedCtx)
 	fake.HasEntitlement(expectedCtx, "entitlement")
+	fake.RequireEntitlements(expectedCtx, "a", "b")
+	fake.InvalidateEntitlements()
 	fake.GetLicense(expectedCtx)
 	fake.ListAccountingUsers(expectedCtx, &ListAccountingUsersInput{})
 	fake.ListProjects(expectedCtx, &ListProjectsInput{})
 	fake.GetProjectDetails(expectedCtx, &GetProjectDetailsInput{})
+	fake.CreateProject(expectedCtx, &CreateProjectInput{})
+	fake.UpdateProject(expectedCtx, &UpdateProjectInput{})
+	fake.ArchiveProject(expectedCtx, &ArchiveProjectInput{})
+	fake.CreateAccessKey(expectedCtx, &CreateAccessKeyInput{})
+	fake.UpdateAccessKey(expectedCtx, &UpdateAccessKeyInput{})
+	fake.RevokeAccessKey(expectedCtx, &RevokeAccessKeyInput{})
+	fake.GetAccountingUserDetails(expectedCtx, &GetAccountingUserDetailsInput{})
+	fake.InviteAccountingUser(expectedCtx, &InviteAccountingUserInput{})
+	fake.DeactivateAccountingUser(expectedCtx, &DeactivateAccountingUserInput{})
+	fake.ReactivateAccountingUser(expectedCtx, &ReactivateAccountingUserInput{})
+	fake.ListRoles(expectedCtx)
+	fake.SetAccountingUserRoles(expectedCtx, &SetAccountingUserRolesInput{})
+	fake.SetAccountingUserEntitlements(expectedCtx, &SetAccountingUserEntitlementsInput{})
+	fake.ListTeams(expectedCtx, &ListTeamsInput{})
+	fake.GetTeamDetails(expectedCtx, &GetTeamDetailsInput{})
+	fake.AddTeamMembers(expectedCtx, &AddTeamMembersInput{})
+	fake.RemoveTeamMember(expectedCtx, &RemoveTeamMemberInput{})
 
-	if calls != 6 {
+	if calls != 25 {
 		t.Errorf("Did not call all of the funcs: %d", calls)
 	}
 }
@@ -3,9 +3,12 @@ package modzy
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/pkg/errors"
 )
 
 func TestGetEntitlementsHTTPError(t *testing.T) {
@@ -241,3 +244,526 @@ func TestGetProjectDetails(t *testing.T) {
 		t.Errorf("response not parsed")
 	}
 }
+
+func TestCreateProject(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("expected method to be POST, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/accounting/projects" {
+			t.Errorf("post url not expected: %s", r.RequestURI)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name":"proj"}` {
+			t.Errorf("body not expected: %s", body)
+		}
+		w.Write([]byte(`{"identifier": "projid", "name": "proj"}`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Accounting().CreateProject(context.TODO(), &CreateProjectInput{Name: "proj"})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.Project.Identifier != "projid" {
+		t.Errorf("response not parsed")
+	}
+}
+
+func TestUpdateProject(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" {
+			t.Errorf("expected method to be PATCH, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/accounting/projects/projid" {
+			t.Errorf("patch url not expected: %s", r.RequestURI)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"description":"new"}` {
+			t.Errorf("body not expected: %s", body)
+		}
+		w.Write([]byte(`{"identifier": "projid", "description": "new"}`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Accounting().UpdateProject(context.TODO(), &UpdateProjectInput{ProjectID: "projid", Description: "new"})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.Project.Description != "new" {
+		t.Errorf("response not parsed")
+	}
+}
+
+func TestArchiveProject(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("expected method to be POST, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/accounting/projects/projid/archive" {
+			t.Errorf("post url not expected: %s", r.RequestURI)
+		}
+		w.Write([]byte(`{"identifier": "projid", "status": "ARCHIVED"}`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Accounting().ArchiveProject(context.TODO(), &ArchiveProjectInput{ProjectID: "projid"})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.Project.Status != "ARCHIVED" {
+		t.Errorf("response not parsed")
+	}
+}
+
+func TestCreateAccessKey(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("expected method to be POST, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/accounting/projects/projid/access-keys" {
+			t.Errorf("post url not expected: %s", r.RequestURI)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name":"ci","isHighPriority":true}` {
+			t.Errorf("body not expected: %s", body)
+		}
+		w.Write([]byte(`{"prefix": "pre", "name": "ci", "isHighPriority": true, "secretKey": "secret"}`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Accounting().CreateAccessKey(context.TODO(), &CreateAccessKeyInput{ProjectID: "projid", Name: "ci", IsHighPriority: true})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.AccessKey.Prefix != "pre" || !out.AccessKey.IsHighPriority || out.APIKey != "pre.secret" {
+		t.Errorf("response not parsed: %+v", out)
+	}
+}
+
+func TestUpdateAccessKey(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" {
+			t.Errorf("expected method to be PATCH, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/accounting/projects/projid/access-keys/pre" {
+			t.Errorf("patch url not expected: %s", r.RequestURI)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"isHighPriority":false}` {
+			t.Errorf("body not expected: %s", body)
+		}
+		w.Write([]byte(`{"prefix": "pre"}`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	highPriority := false
+	out, err := client.Accounting().UpdateAccessKey(context.TODO(), &UpdateAccessKeyInput{ProjectID: "projid", Prefix: "pre", IsHighPriority: &highPriority})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.AccessKey.Prefix != "pre" {
+		t.Errorf("response not parsed")
+	}
+}
+
+func TestRevokeAccessKey(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("expected method to be DELETE, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/accounting/projects/projid/access-keys/pre" {
+			t.Errorf("delete url not expected: %s", r.RequestURI)
+		}
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Accounting().RevokeAccessKey(context.TODO(), &RevokeAccessKeyInput{ProjectID: "projid", Prefix: "pre"})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.AccessKey.Prefix != "pre" {
+		t.Errorf("expected the prefix without a response body")
+	}
+}
+
+func TestRevokeAccessKeyHTTPError(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	if _, err := client.Accounting().RevokeAccessKey(context.TODO(), &RevokeAccessKeyInput{ProjectID: "projid", Prefix: "pre"}); err == nil {
+		t.Errorf("Expected error")
+	}
+}
+
+func TestGetAccountingUserDetails(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("expected method to be GET, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/accounting/users/userid" {
+			t.Errorf("get url not expected: %s", r.RequestURI)
+		}
+		w.Write([]byte(`{
+			"identifier": "userid",
+			"email": "a@b.c",
+			"roles": [{"identifier": "admin"}],
+			"entitlements": [{"identifier": "CAN_PATCH_PROCESSING_MODEL_VERSION"}],
+			"teams": [{"identifier": "teamid", "name": "team"}],
+			"accessKeys": [{"prefix": "pre"}]
+		}`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Accounting().GetAccountingUserDetails(context.TODO(), &GetAccountingUserDetailsInput{UserID: "userid"})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.User.Email != "a@b.c" || out.User.Roles[0].Identifier != "admin" || out.User.Entitlements[0].Identifier != "CAN_PATCH_PROCESSING_MODEL_VERSION" ||
+		out.User.Teams[0].Name != "team" || out.User.AccessKeys[0].Prefix != "pre" {
+		t.Errorf("response not parsed: %+v", out.User)
+	}
+}
+
+func TestGetAccountingUserDetailsHTTPError(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	if _, err := client.Accounting().GetAccountingUserDetails(context.TODO(), &GetAccountingUserDetailsInput{UserID: "userid"}); err == nil {
+		t.Errorf("Expected error")
+	}
+}
+
+func TestInviteAccountingUser(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("expected method to be POST, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/accounting/users" {
+			t.Errorf("post url not expected: %s", r.RequestURI)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"email":"a@b.c","roles":["user"]}` {
+			t.Errorf("body not expected: %s", body)
+		}
+		w.Write([]byte(`{"identifier": "userid", "email": "a@b.c", "status": "INVITED"}`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Accounting().InviteAccountingUser(context.TODO(), &InviteAccountingUserInput{Email: "a@b.c", Roles: []string{"user"}})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.User.Identifier != "userid" {
+		t.Errorf("response not parsed")
+	}
+}
+
+func TestDeactivateAccountingUser(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("expected method to be POST, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/accounting/users/userid/deactivate" {
+			t.Errorf("post url not expected: %s", r.RequestURI)
+		}
+		w.Write([]byte(`{"identifier": "userid", "status": "INACTIVE"}`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Accounting().DeactivateAccountingUser(context.TODO(), &DeactivateAccountingUserInput{UserID: "userid"})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.User.Status != "INACTIVE" {
+		t.Errorf("response not parsed")
+	}
+}
+
+func TestReactivateAccountingUser(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("expected method to be POST, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/accounting/users/userid/reactivate" {
+			t.Errorf("post url not expected: %s", r.RequestURI)
+		}
+		w.Write([]byte(`{"identifier": "userid", "status": "ACTIVE"}`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Accounting().ReactivateAccountingUser(context.TODO(), &ReactivateAccountingUserInput{UserID: "userid"})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.User.Status != "ACTIVE" {
+		t.Errorf("response not parsed")
+	}
+}
+
+func TestListRoles(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("expected method to be GET, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/accounting/roles" {
+			t.Errorf("get url not expected: %s", r.RequestURI)
+		}
+		w.Write([]byte(`[{"identifier": "admin", "name": "Admin"}]`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Accounting().ListRoles(context.TODO())
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.Roles[0].Name != "Admin" {
+		t.Errorf("response not parsed")
+	}
+}
+
+func TestSetAccountingUserRoles(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Errorf("expected method to be PUT, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/accounting/users/userid/roles" {
+			t.Errorf("put url not expected: %s", r.RequestURI)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"roles":["admin","user"]}` {
+			t.Errorf("body not expected: %s", body)
+		}
+		w.Write([]byte(`{"identifier": "userid", "roles": [{"identifier": "admin"}, {"identifier": "user"}]}`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Accounting().SetAccountingUserRoles(context.TODO(), &SetAccountingUserRolesInput{UserID: "userid", Roles: []string{"admin", "user"}})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if len(out.User.Roles) != 2 {
+		t.Errorf("response not parsed")
+	}
+}
+
+func TestSetAccountingUserEntitlements(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Errorf("expected method to be PUT, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/accounting/users/userid/entitlements" {
+			t.Errorf("put url not expected: %s", r.RequestURI)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"entitlements":["a"]}` {
+			t.Errorf("body not expected: %s", body)
+		}
+		w.Write([]byte(`{"identifier": "userid", "entitlements": [{"identifier": "a"}]}`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Accounting().SetAccountingUserEntitlements(context.TODO(), &SetAccountingUserEntitlementsInput{UserID: "userid", Entitlements: []string{"a"}})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.User.Entitlements[0].Identifier != "a" {
+		t.Errorf("response not parsed")
+	}
+}
+
+func TestListTeams(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("expected method to be GET, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/accounting/teams?page=7&per-page=2&search=ops" {
+			t.Errorf("get url not expected: %s", r.RequestURI)
+		}
+		w.Header().Set("Link", `<https://example>; rel="next"`)
+		w.Write([]byte(`[{"name": "ops"},{"name": "ops2"}]`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Accounting().ListTeams(context.TODO(), (&ListTeamsInput{}).WithPaging(2, 7).WithFilter(ListTeamsFilterFieldSearch, "ops"))
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.Teams[0].Name != "ops" {
+		t.Errorf("response not parsed")
+	}
+	if out.NextPage == nil || out.NextPage.Paging.Page != 8 {
+		t.Errorf("expected NextPage to be next")
+	}
+}
+
+func TestListTeamsHTTPError(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	if _, err := client.Accounting().ListTeams(context.TODO(), &ListTeamsInput{}); err == nil {
+		t.Errorf("Expected error")
+	}
+}
+
+func TestGetTeamDetails(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("expected method to be GET, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/accounting/teams/teamid" {
+			t.Errorf("get url not expected: %s", r.RequestURI)
+		}
+		w.Write([]byte(`{"identifier": "teamid", "members": [{"identifier": "userid"}]}`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Accounting().GetTeamDetails(context.TODO(), &GetTeamDetailsInput{TeamID: "teamid"})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.Team.Members[0].Identifier != "userid" {
+		t.Errorf("response not parsed")
+	}
+}
+
+func TestAddTeamMembers(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("expected method to be POST, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/accounting/teams/teamid/members" {
+			t.Errorf("post url not expected: %s", r.RequestURI)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"users":["userid"]}` {
+			t.Errorf("body not expected: %s", body)
+		}
+		w.Write([]byte(`{"identifier": "teamid", "members": [{"identifier": "userid"}]}`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Accounting().AddTeamMembers(context.TODO(), &AddTeamMembersInput{TeamID: "teamid", UserIDs: []string{"userid"}})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if len(out.Team.Members) != 1 {
+		t.Errorf("response not parsed")
+	}
+}
+
+func TestRemoveTeamMember(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("expected method to be DELETE, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/accounting/teams/teamid/members/userid" {
+			t.Errorf("delete url not expected: %s", r.RequestURI)
+		}
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Accounting().RemoveTeamMember(context.TODO(), &RemoveTeamMemberInput{TeamID: "teamid", UserID: "userid"})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.Team.Identifier != "teamid" {
+		t.Errorf("expected the team without a response body")
+	}
+}
+
+func TestHasEntitlementCached(t *testing.T) {
+	calls := 0
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.RequestURI {
+		case "/api/accounting/entitlements":
+			w.Write([]byte(`[{"identifier": "one"}]`))
+		default:
+			w.WriteHeader(403)
+			w.Write([]byte(`{"statusCode":403,"message":"forbidden"}`))
+		}
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+
+	client.Accounting().HasEntitlement(context.TODO(), "one")
+	client.Accounting().HasEntitlement(context.TODO(), "two")
+	if calls != 1 {
+		t.Errorf("Expected the entitlements to be cached, got %d calls", calls)
+	}
+
+	// any forbidden response drops the cache
+	client.Accounting().GetLicense(context.TODO())
+	client.Accounting().HasEntitlement(context.TODO(), "one")
+	if calls != 3 {
+		t.Errorf("Expected the entitlements to be read again after a 403, got %d calls", calls)
+	}
+
+	client.Accounting().InvalidateEntitlements()
+	client.Accounting().HasEntitlement(context.TODO(), "one")
+	if calls != 4 {
+		t.Errorf("Expected the entitlements to be read again after invalidating, got %d calls", calls)
+	}
+}
+
+func TestRequireEntitlements(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"identifier": "one"}, {"identifier": "two"}]`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+
+	if err := client.Accounting().RequireEntitlements(context.TODO(), "one", "two"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	err := client.Accounting().RequireEntitlements(context.TODO(), "one", "three", "four")
+	missing, ok := err.(*MissingEntitlementsError)
+	if !ok {
+		t.Fatalf("Expected a MissingEntitlementsError, got %v", err)
+	}
+	if len(missing.Missing) != 2 || missing.Missing[0] != "three" || missing.Missing[1] != "four" {
+		t.Errorf("Expected three and four to be missing, got %v", missing.Missing)
+	}
+	if errors.Cause(err) != ErrForbidden {
+		t.Errorf("Expected the cause to be ErrForbidden")
+	}
+}
+
+func TestRequireEntitlementsHTTPError(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	if err := client.Accounting().RequireEntitlements(context.TODO(), "one"); err == nil {
+		t.Errorf("Expected error")
+	}
+}
+
+func TestSetAccountingUserEntitlementsInvalidatesCache(t *testing.T) {
+	reads := 0
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.RequestURI == "/api/accounting/entitlements" {
+			reads++
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+
+	client.Accounting().HasEntitlement(context.TODO(), "one")
+	client.Accounting().SetAccountingUserEntitlements(context.TODO(), &SetAccountingUserEntitlementsInput{UserID: "me"})
+	client.Accounting().HasEntitlement(context.TODO(), "one")
+	if reads != 2 {
+		t.Errorf("Expected the entitlements to be read again, got %d reads", reads)
+	}
+}
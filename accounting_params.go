@@ -99,3 +99,182 @@ type GetProjectDetailsInput struct {
 type GetProjectDetailsOutput struct {
 	Project model.AccountingProject `json:"project"`
 }
+
+type CreateProjectInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Visibility  string `json:"visibility,omitempty"`
+}
+
+type CreateProjectOutput struct {
+	Project model.AccountingProject `json:"project"`
+}
+
+// UpdateProjectInput only sends the fields that are set
+type UpdateProjectInput struct {
+	ProjectID   string `json:"-"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Visibility  string `json:"visibility,omitempty"`
+}
+
+type UpdateProjectOutput struct {
+	Project model.AccountingProject `json:"project"`
+}
+
+type ArchiveProjectInput struct {
+	ProjectID string
+}
+
+type ArchiveProjectOutput struct {
+	Project model.AccountingProject `json:"project"`
+}
+
+type CreateAccessKeyInput struct {
+	ProjectID      string `json:"-"`
+	Name           string `json:"name"`
+	Description    string `json:"description,omitempty"`
+	IsHighPriority bool   `json:"isHighPriority"`
+}
+
+type CreateAccessKeyOutput struct {
+	AccessKey model.AccessKey `json:"accessKey"`
+	// APIKey is the full key to authenticate with, such as with Client.WithAPIKey.  It can not be read again later.
+	APIKey string `json:"apiKey"`
+}
+
+// UpdateAccessKeyInput only sends the fields that are set
+type UpdateAccessKeyInput struct {
+	ProjectID      string `json:"-"`
+	Prefix         string `json:"-"`
+	Name           string `json:"name,omitempty"`
+	Description    string `json:"description,omitempty"`
+	IsHighPriority *bool  `json:"isHighPriority,omitempty"`
+	IsDefault      *bool  `json:"isDefault,omitempty"`
+}
+
+type UpdateAccessKeyOutput struct {
+	AccessKey model.AccessKey `json:"accessKey"`
+}
+
+type RevokeAccessKeyInput struct {
+	ProjectID string
+	Prefix    string
+}
+
+type RevokeAccessKeyOutput struct {
+	AccessKey model.AccessKey `json:"accessKey"`
+}
+
+type GetAccountingUserDetailsInput struct {
+	UserID string
+}
+
+type GetAccountingUserDetailsOutput struct {
+	User model.AccountingUserDetails `json:"user"`
+}
+
+type InviteAccountingUserInput struct {
+	Email     string `json:"email"`
+	FirstName string `json:"firstName,omitempty"`
+	LastName  string `json:"lastName,omitempty"`
+	// Roles and Teams are identifiers to assign to the user once they accept the invitation
+	Roles []string `json:"roles,omitempty"`
+	Teams []string `json:"teams,omitempty"`
+}
+
+type InviteAccountingUserOutput struct {
+	User model.AccountingUser `json:"user"`
+}
+
+type DeactivateAccountingUserInput struct {
+	UserID string
+}
+
+type DeactivateAccountingUserOutput struct {
+	User model.AccountingUser `json:"user"`
+}
+
+type ReactivateAccountingUserInput struct {
+	UserID string
+}
+
+type ReactivateAccountingUserOutput struct {
+	User model.AccountingUser `json:"user"`
+}
+
+type ListRolesOutput struct {
+	Roles []model.Role `json:"roles"`
+}
+
+// SetAccountingUserRolesInput replaces all of the roles of a user with the provided role identifiers
+type SetAccountingUserRolesInput struct {
+	UserID string   `json:"-"`
+	Roles  []string `json:"roles"`
+}
+
+type SetAccountingUserRolesOutput struct {
+	User model.AccountingUserDetails `json:"user"`
+}
+
+// SetAccountingUserEntitlementsInput replaces all of the entitlements of a user with the provided entitlement identifiers
+type SetAccountingUserEntitlementsInput struct {
+	UserID       string   `json:"-"`
+	Entitlements []string `json:"entitlements"`
+}
+
+type SetAccountingUserEntitlementsOutput struct {
+	User model.AccountingUserDetails `json:"user"`
+}
+
+type ListTeamsInput struct {
+	Paging PagingInput
+}
+
+// ListTeamsFilterField are known field names that can be used when filtering the teams
+type ListTeamsFilterField string
+
+const (
+	ListTeamsFilterFieldSearch ListTeamsFilterField = "search"
+)
+
+func (i *ListTeamsInput) WithPaging(perPage int, page int) *ListTeamsInput {
+	i.Paging = NewPaging(perPage, page)
+	return i
+}
+
+func (i *ListTeamsInput) WithFilter(field ListTeamsFilterField, value string) *ListTeamsInput {
+	i.Paging = i.Paging.WithFilterAnd(string(field), value)
+	return i
+}
+
+type ListTeamsOutput struct {
+	Teams    []model.AccountingTeam `json:"teams"`
+	NextPage *ListTeamsInput        `json:"nextPage"`
+}
+
+type GetTeamDetailsInput struct {
+	TeamID string
+}
+
+type GetTeamDetailsOutput struct {
+	Team model.AccountingTeam `json:"team"`
+}
+
+type AddTeamMembersInput struct {
+	TeamID  string   `json:"-"`
+	UserIDs []string `json:"users"`
+}
+
+type AddTeamMembersOutput struct {
+	Team model.AccountingTeam `json:"team"`
+}
+
+type RemoveTeamMemberInput struct {
+	TeamID string
+	UserID string
+}
+
+type RemoveTeamMemberOutput struct {
+	Team model.AccountingTeam `json:"team"`
+}
@@ -0,0 +1,235 @@
+package modzy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+const defaultAutoscaleInterval = 30 * time.Second
+
+// AutoscaleTarget is a model version managed by the Autoscaler
+type AutoscaleTarget struct {
+	ModelID string
+	Version string
+	// MinEngines is never scaled below, and may be zero so that the model only runs when inputs are queued
+	MinEngines int
+	// MaxEngines is never scaled above
+	MaxEngines int
+}
+
+type AutoscalerConfig struct {
+	Targets []AutoscaleTarget
+	// ScaleUpQueueDepth is the number of queued inputs per engine above which an engine is added
+	ScaleUpQueueDepth int
+	// ScaleDownQueueDepth is the number of queued inputs per engine that one less engine must be able to stay at or below
+	// before an engine is removed.  It must be lower than ScaleUpQueueDepth so that capacity does not flap.
+	ScaleDownQueueDepth int
+	// ScaleUpCooldown is the minimum time after a change to a target before it is scaled up again
+	ScaleUpCooldown time.Duration
+	// ScaleDownCooldown is the minimum time after a change to a target before it is scaled down again
+	ScaleDownCooldown time.Duration
+	// Interval is how often Run takes a step; defaults to 30 seconds
+	Interval time.Duration
+	// OnDecision, if provided, is called with every decision, including those that were held back, for auditing
+	OnDecision func(decision AutoscaleDecision)
+	// OnError, if provided, is called by Run when a step fails
+	OnError func(err error)
+}
+
+// AutoscaleDecision records a change the Autoscaler wanted to make to a target
+type AutoscaleDecision struct {
+	Target       AutoscaleTarget
+	At           time.Time
+	QueuedInputs int
+	QueuedJobs   int
+	ReadyEngines int
+	// From is the capacity before the decision and To is the capacity after; they are the same when the change was held back
+	From   int
+	To     int
+	Reason string
+	// Err is set if the capacity could not be updated
+	Err error
+}
+
+// Applied is true if the capacity was changed
+func (d AutoscaleDecision) Applied() bool {
+	return d.From != d.To && d.Err == nil
+}
+
+type autoscaleState struct {
+	capacity   int
+	lastChange time.Time
+}
+
+// Autoscaler adjusts the processing engines of model versions based on the depth of their input queues.  Capacity only
+// moves by one engine per step, stays within each target's bounds, and is never raised past the processing engines
+// allowed by the license.  A license whose engine limit is missing or is not a number is treated as unlimited.
+type Autoscaler struct {
+	sync.Mutex
+	client Client
+	config AutoscalerConfig
+	states map[string]*autoscaleState
+	now    func() time.Time
+}
+
+// NewAutoscaler validates the configuration and creates an Autoscaler.  Nothing is changed until Step or Run is called.
+func NewAutoscaler(client Client, config AutoscalerConfig) (*Autoscaler, error) {
+	if config.ScaleUpQueueDepth <= 0 {
+		return nil, errors.New("ScaleUpQueueDepth must be greater than zero")
+	}
+	if config.ScaleDownQueueDepth < 0 || config.ScaleDownQueueDepth >= config.ScaleUpQueueDepth {
+		return nil, errors.New("ScaleDownQueueDepth must be at least zero and lower than ScaleUpQueueDepth")
+	}
+	for _, target := range config.Targets {
+		if target.MinEngines < 0 || target.MaxEngines < 1 || target.MinEngines > target.MaxEngines {
+			return nil, errors.Errorf("%s@%s has invalid bounds %d-%d", target.ModelID, target.Version, target.MinEngines, target.MaxEngines)
+		}
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultAutoscaleInterval
+	}
+	return &Autoscaler{
+		client: client,
+		config: config,
+		states: map[string]*autoscaleState{},
+		now:    time.Now,
+	}, nil
+}
+
+// Step reads the processing models and the license once, and makes at most one change to each target.  Failures to
+// update a target are recorded in its decision rather than returned.
+func (a *Autoscaler) Step(ctx context.Context) ([]AutoscaleDecision, error) {
+	a.Lock()
+	defer a.Unlock()
+
+	license, err := a.client.Accounting().GetLicense(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read the license")
+	}
+	minimum, err := a.client.Models().GetMinimumEngines(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read the minimum engines")
+	}
+	processing, err := a.client.Resources().GetProcessingModels(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read processing models")
+	}
+	budget, err := NewCapacityBudget(license.License, minimum.Details, processing.Models)
+	if err != nil {
+		// a license without a readable engine limit does not hold back scaling
+		budget, _ = NewCapacityBudget(model.License{}, minimum.Details, processing.Models)
+	}
+
+	now := a.now()
+	decisions := []AutoscaleDecision{}
+	for _, target := range a.config.Targets {
+		pm := findProcessingModel(processing.Models, target.ModelID, target.Version)
+		key := target.ModelID + "@" + target.Version
+		state, ok := a.states[key]
+		if !ok {
+			// the first time a target is seen the running engines are taken as its capacity
+			state = &autoscaleState{capacity: len(pm.Engines)}
+			a.states[key] = state
+		}
+
+		// the capacity set by the autoscaler is the minimum the target reserves
+		budget.RecordReserved(target.ModelID, target.Version, state.capacity)
+
+		decision, cooldown := a.decide(target, state.capacity, pm)
+		if decision.From == decision.To {
+			continue
+		}
+		decision.At = now
+		switch {
+		case !state.lastChange.IsZero() && now.Sub(state.lastChange) < cooldown:
+			decision.To = decision.From
+			decision.Reason = decision.Reason + "; held back by the cooldown"
+		case decision.To > decision.From && budget.CheckCapacity(target.ModelID, target.Version, decision.To) != nil:
+			decision.To = decision.From
+			decision.Reason = decision.Reason + fmt.Sprintf("; held back by the license limit of %s engines", budget.Limit)
+		default:
+			_, decision.Err = a.client.Models().UpdateModelProcessingEngines(ctx, &UpdateModelProcessingEnginesInput{
+				ModelID:                 target.ModelID,
+				Version:                 target.Version,
+				MinimumParallelCapacity: decision.To,
+				MaximumParallelCapacity: max(decision.To, 1),
+			})
+			if decision.Err == nil {
+				budget.SetReserved(target.ModelID, target.Version, decision.To)
+				state.capacity = decision.To
+				state.lastChange = now
+			}
+		}
+		if a.config.OnDecision != nil {
+			a.config.OnDecision(decision)
+		}
+		decisions = append(decisions, decision)
+	}
+	return decisions, nil
+}
+
+// Run takes a step right away and then on every interval until the context is done.  Failed steps are passed to
+// OnError and do not stop the loop.
+func (a *Autoscaler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(a.config.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := a.Step(ctx); err != nil && a.config.OnError != nil {
+			a.config.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// decide returns the desired change for a target along with the cooldown that applies to it
+func (a *Autoscaler) decide(target AutoscaleTarget, capacity int, pm model.ResourcesProcessingModel) (AutoscaleDecision, time.Duration) {
+	decision := AutoscaleDecision{
+		Target:       target,
+		QueuedInputs: pm.Inputs.Queued,
+		QueuedJobs:   pm.Jobs.Queued,
+		ReadyEngines: readyEngines(pm.Engines),
+		From:         capacity,
+		To:           capacity,
+	}
+	queued := pm.Inputs.Queued
+	switch {
+	case capacity < target.MinEngines:
+		decision.To = target.MinEngines
+		decision.Reason = "below the minimum engines"
+		return decision, 0
+	case capacity > target.MaxEngines:
+		decision.To = target.MaxEngines
+		decision.Reason = "above the maximum engines"
+		return decision, 0
+	case capacity < target.MaxEngines && queued > a.config.ScaleUpQueueDepth*capacity:
+		decision.To = capacity + 1
+		decision.Reason = fmt.Sprintf("%d queued inputs is more than %d per engine", queued, a.config.ScaleUpQueueDepth)
+		return decision, a.config.ScaleUpCooldown
+	case capacity > target.MinEngines && queued <= a.config.ScaleDownQueueDepth*(capacity-1):
+		decision.To = capacity - 1
+		decision.Reason = fmt.Sprintf("%d queued inputs fits within %d per engine with one less engine", queued, a.config.ScaleDownQueueDepth)
+		return decision, a.config.ScaleDownCooldown
+	}
+	return decision, 0
+}
+
+func findProcessingModel(models []model.ResourcesProcessingModel, modelID string, version string) model.ResourcesProcessingModel {
+	for _, pm := range models {
+		if pm.Identifier == modelID && pm.Version == version {
+			return pm
+		}
+	}
+	return model.ResourcesProcessingModel{Identifier: modelID, Version: version}
+}
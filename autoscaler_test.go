@@ -0,0 +1,283 @@
+package modzy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+func TestNewAutoscalerValidation(t *testing.T) {
+	cases := []AutoscalerConfig{
+		{ScaleUpQueueDepth: 0},
+		{ScaleUpQueueDepth: 10, ScaleDownQueueDepth: 10},
+		{ScaleUpQueueDepth: 10, ScaleDownQueueDepth: 2, Targets: []AutoscaleTarget{{ModelID: "m", MinEngines: 3, MaxEngines: 2}}},
+	}
+	for i, config := range cases {
+		if _, err := NewAutoscaler(&ClientFake{}, config); err == nil {
+			t.Errorf("Expected case %d to be invalid", i)
+		}
+	}
+}
+
+func TestAutoscalerStepHysteresisAndCooldown(t *testing.T) {
+	processed := []model.ResourcesProcessingModel{{Identifier: "m", Version: "1.0.0", Engines: make([]model.ResourcesProcessingModelEngine, 1), Inputs: model.ResourcesProcessingModelInputs{Queued: 25}}}
+	updates := []UpdateModelProcessingEnginesInput{}
+	client := &ClientFake{
+		AccountingFunc: func() AccountingClient {
+			return &AccountingClientFake{GetLicenseFunc: func(ctx context.Context) (*GetLicenseOutput, error) {
+				return &GetLicenseOutput{License: model.License{ProcessingEngines: "10"}}, nil
+			}}
+		},
+		ResourcesFunc: func() ResourcesClient {
+			return &ResourcesClientFake{GetProcessingModelsFunc: func(ctx context.Context) (*GetProcessingModelsOutput, error) {
+				return &GetProcessingModelsOutput{Models: processed}, nil
+			}}
+		},
+		ModelsFunc: func() ModelsClient {
+			return &ModelsClientFake{
+				GetMinimumEnginesFunc: func(ctx context.Context) (*GetMinimumEnginesOutput, error) {
+					return &GetMinimumEnginesOutput{Details: model.MinimumEngines{MinimumProcessingEnginesSum: 0}}, nil
+				},
+				UpdateModelProcessingEnginesFunc: func(ctx context.Context, input *UpdateModelProcessingEnginesInput) (*UpdateModelProcessingEnginesOutput, error) {
+					updates = append(updates, *input)
+					return &UpdateModelProcessingEnginesOutput{}, nil
+				},
+			}
+		},
+	}
+	audited := 0
+	scaler, err := NewAutoscaler(client, AutoscalerConfig{
+		Targets:             []AutoscaleTarget{{ModelID: "m", Version: "1.0.0", MinEngines: 1, MaxEngines: 3}},
+		ScaleUpQueueDepth:   10,
+		ScaleDownQueueDepth: 2,
+		ScaleUpCooldown:     time.Minute,
+		ScaleDownCooldown:   5 * time.Minute,
+		OnDecision:          func(decision AutoscaleDecision) { audited++ },
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	scaler.now = func() time.Time { return now }
+
+	step := func(expectedFrom int, expectedTo int) AutoscaleDecision {
+		t.Helper()
+		decisions, err := scaler.Step(context.TODO())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(decisions) != 1 || decisions[0].From != expectedFrom || decisions[0].To != expectedTo {
+			t.Fatalf("Expected %d->%d, got %+v", expectedFrom, expectedTo, decisions)
+		}
+		return decisions[0]
+	}
+
+	step(1, 2)
+	// still queued, but within the cooldown
+	held := step(2, 2)
+	if held.Applied() || !strings.Contains(held.Reason, "cooldown") {
+		t.Errorf("Expected the cooldown to hold back the change, got %+v", held)
+	}
+	now = now.Add(time.Minute)
+	step(2, 3)
+
+	// between the thresholds nothing changes
+	processed = []model.ResourcesProcessingModel{{Identifier: "m", Version: "1.0.0", Engines: make([]model.ResourcesProcessingModelEngine, 3), Inputs: model.ResourcesProcessingModelInputs{Queued: 5}}}
+	now = now.Add(10 * time.Minute)
+	if decisions, _ := scaler.Step(context.TODO()); len(decisions) != 0 {
+		t.Errorf("Expected no decisions within the hysteresis band, got %+v", decisions)
+	}
+
+	processed = []model.ResourcesProcessingModel{{Identifier: "m", Version: "1.0.0", Engines: make([]model.ResourcesProcessingModelEngine, 3), Inputs: model.ResourcesProcessingModelInputs{Queued: 4}}}
+	step(3, 2)
+	if len(updates) != 3 || updates[2].MinimumParallelCapacity != 2 || audited != 4 {
+		t.Errorf("Expected 3 updates and 4 audited decisions, got %+v and %d", updates, audited)
+	}
+}
+
+func TestAutoscalerStepLicenseBudget(t *testing.T) {
+	license := "3"
+	processed := []model.ResourcesProcessingModel{
+		{Identifier: "m", Version: "1.0.0", Engines: make([]model.ResourcesProcessingModelEngine, 1), Inputs: model.ResourcesProcessingModelInputs{Queued: 100}},
+		{Identifier: "other", Version: "1.0.0", Engines: make([]model.ResourcesProcessingModelEngine, 2)},
+	}
+	updates := []UpdateModelProcessingEnginesInput{}
+	client := &ClientFake{
+		AccountingFunc: func() AccountingClient {
+			return &AccountingClientFake{GetLicenseFunc: func(ctx context.Context) (*GetLicenseOutput, error) {
+				return &GetLicenseOutput{License: model.License{ProcessingEngines: license}}, nil
+			}}
+		},
+		ResourcesFunc: func() ResourcesClient {
+			return &ResourcesClientFake{GetProcessingModelsFunc: func(ctx context.Context) (*GetProcessingModelsOutput, error) {
+				return &GetProcessingModelsOutput{Models: processed}, nil
+			}}
+		},
+		ModelsFunc: func() ModelsClient {
+			return &ModelsClientFake{
+				GetMinimumEnginesFunc: func(ctx context.Context) (*GetMinimumEnginesOutput, error) {
+					return &GetMinimumEnginesOutput{Details: model.MinimumEngines{MinimumProcessingEnginesSum: 0}}, nil
+				},
+				UpdateModelProcessingEnginesFunc: func(ctx context.Context, input *UpdateModelProcessingEnginesInput) (*UpdateModelProcessingEnginesOutput, error) {
+					updates = append(updates, *input)
+					return &UpdateModelProcessingEnginesOutput{}, nil
+				},
+			}
+		},
+	}
+	scaler, _ := NewAutoscaler(client, AutoscalerConfig{
+		Targets:             []AutoscaleTarget{{ModelID: "m", Version: "1.0.0", MinEngines: 0, MaxEngines: 5}},
+		ScaleUpQueueDepth:   10,
+		ScaleDownQueueDepth: 2,
+	})
+
+	decisions, err := scaler.Step(context.TODO())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(decisions) != 1 || decisions[0].Applied() || !strings.Contains(decisions[0].Reason, "license") {
+		t.Errorf("Expected the license to hold back the change, got %+v", decisions)
+	}
+
+	license = "unlimited"
+	decisions, _ = scaler.Step(context.TODO())
+	if len(decisions) != 1 || !decisions[0].Applied() {
+		t.Errorf("Expected an unlimited license to allow the change, got %+v", decisions)
+	}
+
+	license = "ten"
+	decisions, err = scaler.Step(context.TODO())
+	if err != nil {
+		t.Fatalf("Expected an invalid license to be treated as unlimited, got %v", err)
+	}
+	if len(decisions) != 1 || strings.Contains(decisions[0].Reason, "license") {
+		t.Errorf("Expected an invalid license to not hold back the change, got %+v", decisions)
+	}
+}
+
+func TestAutoscalerStepReservedEngines(t *testing.T) {
+	// the running engines fit the license, but the minimum engines reserved by other versions do not
+	processed := []model.ResourcesProcessingModel{
+		{Identifier: "m", Version: "1.0.0", Engines: make([]model.ResourcesProcessingModelEngine, 1), Inputs: model.ResourcesProcessingModelInputs{Queued: 100}},
+	}
+	client := &ClientFake{
+		AccountingFunc: func() AccountingClient {
+			return &AccountingClientFake{GetLicenseFunc: func(ctx context.Context) (*GetLicenseOutput, error) {
+				return &GetLicenseOutput{License: model.License{ProcessingEngines: "4"}}, nil
+			}}
+		},
+		ResourcesFunc: func() ResourcesClient {
+			return &ResourcesClientFake{GetProcessingModelsFunc: func(ctx context.Context) (*GetProcessingModelsOutput, error) {
+				return &GetProcessingModelsOutput{Models: processed}, nil
+			}}
+		},
+		ModelsFunc: func() ModelsClient {
+			return &ModelsClientFake{
+				GetMinimumEnginesFunc: func(ctx context.Context) (*GetMinimumEnginesOutput, error) {
+					return &GetMinimumEnginesOutput{Details: model.MinimumEngines{MinimumProcessingEnginesSum: 4}}, nil
+				},
+				UpdateModelProcessingEnginesFunc: func(ctx context.Context, input *UpdateModelProcessingEnginesInput) (*UpdateModelProcessingEnginesOutput, error) {
+					t.Errorf("Expected no change to be made")
+					return &UpdateModelProcessingEnginesOutput{}, nil
+				},
+			}
+		},
+	}
+	scaler, _ := NewAutoscaler(client, AutoscalerConfig{
+		Targets:             []AutoscaleTarget{{ModelID: "m", Version: "1.0.0", MinEngines: 0, MaxEngines: 5}},
+		ScaleUpQueueDepth:   10,
+		ScaleDownQueueDepth: 2,
+	})
+
+	decisions, err := scaler.Step(context.TODO())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(decisions) != 1 || decisions[0].Applied() || !strings.Contains(decisions[0].Reason, "license limit of 4") {
+		t.Errorf("Expected the reserved engines to hold back the change, got %+v", decisions)
+	}
+}
+
+func TestAutoscalerStepBoundsAndErrors(t *testing.T) {
+	processed := []model.ResourcesProcessingModel{}
+	updates := []UpdateModelProcessingEnginesInput{}
+	updateErr := fmt.Errorf("nope")
+	client := &ClientFake{
+		AccountingFunc: func() AccountingClient {
+			return &AccountingClientFake{GetLicenseFunc: func(ctx context.Context) (*GetLicenseOutput, error) {
+				return &GetLicenseOutput{License: model.License{ProcessingEngines: ""}}, nil
+			}}
+		},
+		ResourcesFunc: func() ResourcesClient {
+			return &ResourcesClientFake{GetProcessingModelsFunc: func(ctx context.Context) (*GetProcessingModelsOutput, error) {
+				return &GetProcessingModelsOutput{Models: processed}, nil
+			}}
+		},
+		ModelsFunc: func() ModelsClient {
+			return &ModelsClientFake{
+				GetMinimumEnginesFunc: func(ctx context.Context) (*GetMinimumEnginesOutput, error) {
+					return &GetMinimumEnginesOutput{Details: model.MinimumEngines{MinimumProcessingEnginesSum: 0}}, nil
+				},
+				UpdateModelProcessingEnginesFunc: func(ctx context.Context, input *UpdateModelProcessingEnginesInput) (*UpdateModelProcessingEnginesOutput, error) {
+					if updateErr != nil {
+						return nil, updateErr
+					}
+					updates = append(updates, *input)
+					return &UpdateModelProcessingEnginesOutput{}, nil
+				},
+			}
+		},
+	}
+	scaler, _ := NewAutoscaler(client, AutoscalerConfig{
+		Targets:             []AutoscaleTarget{{ModelID: "m", Version: "1.0.0", MinEngines: 2, MaxEngines: 5}},
+		ScaleUpQueueDepth:   10,
+		ScaleDownQueueDepth: 2,
+	})
+
+	decisions, err := scaler.Step(context.TODO())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(decisions) != 1 || decisions[0].To != 2 || decisions[0].Err == nil || decisions[0].Applied() {
+		t.Errorf("Expected a failed change to the minimum, got %+v", decisions)
+	}
+
+	updateErr = nil
+	decisions, _ = scaler.Step(context.TODO())
+	if len(decisions) != 1 || !decisions[0].Applied() || updates[0].MinimumParallelCapacity != 2 {
+		t.Errorf("Expected a retry of the change to the minimum, got %+v", decisions)
+	}
+}
+
+func TestAutoscalerRun(t *testing.T) {
+	client := &ClientFake{
+		AccountingFunc: func() AccountingClient {
+			return &AccountingClientFake{GetLicenseFunc: func(ctx context.Context) (*GetLicenseOutput, error) {
+				return nil, fmt.Errorf("nope")
+			}}
+		},
+	}
+	ctx, cancel := context.WithCancel(context.TODO())
+	failures := 0
+	scaler, _ := NewAutoscaler(client, AutoscalerConfig{
+		ScaleUpQueueDepth: 10,
+		Interval:          time.Millisecond,
+		OnError: func(err error) {
+			failures++
+			if failures == 3 {
+				cancel()
+			}
+		},
+	})
+
+	if err := scaler.Run(ctx); err != context.Canceled {
+		t.Errorf("Expected context canceled, got %v", err)
+	}
+	if failures != 3 {
+		t.Errorf("Expected 3 failed steps, got %d", failures)
+	}
+}
@@ -0,0 +1,141 @@
+package modzy
+
+import (
+	"context"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+// CapacityBudget compares the processing engines allowed by the license with those in use.  Engines in use are the
+// larger of the minimum engines reserved across all model versions and the engines each version is committed to, which
+// is the larger of the engines it is running and its reserved minimum.
+//
+// The license API only reports the sum of the reserved minimums, so the minimum of a single version is only known once
+// it is recorded with RecordReserved or changed with SetReserved.  A version whose minimum is not known is taken to
+// reserve nothing yet, so any minimum it is given counts in full.
+type CapacityBudget struct {
+	Limit model.ProcessingEngineLimit
+	// MinimumEngines is the sum of the minimum engines of all model versions, as read by GetMinimumEngines
+	MinimumEngines int
+	// capacities are the engines each version is running
+	capacities map[string]int
+	// reserved are the minimum engines of the versions whose minimum is known
+	reserved map[string]int
+}
+
+// NewCapacityBudget builds a budget from the license, the minimum engines and the processing models
+func NewCapacityBudget(license model.License, minimum model.MinimumEngines, processing []model.ResourcesProcessingModel) (*CapacityBudget, error) {
+	limit, err := license.EngineLimit()
+	if err != nil {
+		return nil, err
+	}
+	budget := &CapacityBudget{
+		Limit:          limit,
+		MinimumEngines: minimum.MinimumProcessingEnginesSum,
+		capacities:     map[string]int{},
+		reserved:       map[string]int{},
+	}
+	for _, pm := range processing {
+		budget.capacities[capacityKey(pm.Identifier, pm.Version)] += len(pm.Engines)
+	}
+	return budget, nil
+}
+
+// ReadCapacityBudget reads the license, the minimum engines and the processing models to build a budget
+func ReadCapacityBudget(ctx context.Context, client Client) (*CapacityBudget, error) {
+	license, err := client.Accounting().GetLicense(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read the license")
+	}
+	minimum, err := client.Models().GetMinimumEngines(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read the minimum engines")
+	}
+	processing, err := client.Resources().GetProcessingModels(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read processing models")
+	}
+	return NewCapacityBudget(license.License, minimum.Details, processing.Models)
+}
+
+// Capacity returns the engines a model version is running
+func (b *CapacityBudget) Capacity(modelID string, version string) int {
+	return b.capacities[capacityKey(modelID, version)]
+}
+
+// Reserved returns the minimum engines of a model version, or zero if its minimum is not known
+func (b *CapacityBudget) Reserved(modelID string, version string) int {
+	return b.reserved[capacityKey(modelID, version)]
+}
+
+// Running returns the engines running across all model versions
+func (b *CapacityBudget) Running() int {
+	running := 0
+	for _, engines := range b.capacities {
+		running += engines
+	}
+	return running
+}
+
+// InUse returns the engines counted against the license
+func (b *CapacityBudget) InUse() int {
+	return max(b.MinimumEngines, b.committed())
+}
+
+// committed sums the engines of each version, counting a version scaling up to its minimum at that minimum
+func (b *CapacityBudget) committed() int {
+	committed := b.Running()
+	for key, reserved := range b.reserved {
+		committed += max(reserved-b.capacities[key], 0)
+	}
+	return committed
+}
+
+// Available returns the engines that can still be added, or false if the license is unlimited
+func (b *CapacityBudget) Available() (int, bool) {
+	if b.Limit.Unlimited {
+		return 0, false
+	}
+	return max(b.Limit.Engines-b.InUse(), 0), true
+}
+
+// CheckCapacity returns an error wrapping ErrLicenseExceeded if giving the model version the provided minimum engines
+// would use more engines than the license allows.  Lowering the known minimum of a version is always allowed.
+func (b *CapacityBudget) CheckCapacity(modelID string, version string, minimum int) error {
+	key := capacityKey(modelID, version)
+	reserved, running := b.reserved[key], b.capacities[key]
+	if minimum <= reserved {
+		return nil
+	}
+	projected := max(
+		b.MinimumEngines-reserved+minimum,
+		b.committed()-max(running, reserved)+max(running, minimum),
+	)
+	if !b.Limit.Allows(projected) {
+		return errors.WithMessagef(ErrLicenseExceeded, "%s@%s with a minimum of %d engines would use %d of %s licensed engines", modelID, version, minimum, projected, b.Limit)
+	}
+	return nil
+}
+
+// RecordReserved records the current minimum engines of a model version, such as read from its details, without
+// changing the sum of the reserved minimums
+func (b *CapacityBudget) RecordReserved(modelID string, version string, minimum int) {
+	b.reserved[capacityKey(modelID, version)] = minimum
+}
+
+// SetReserved records a change to the minimum engines of a model version, so that following checks account for it
+func (b *CapacityBudget) SetReserved(modelID string, version string, minimum int) {
+	key := capacityKey(modelID, version)
+	b.MinimumEngines = max(b.MinimumEngines-b.reserved[key]+minimum, 0)
+	b.reserved[key] = minimum
+}
+
+// SetCapacity records a change to the engines a model version is running
+func (b *CapacityBudget) SetCapacity(modelID string, version string, engines int) {
+	b.capacities[capacityKey(modelID, version)] = engines
+}
+
+func capacityKey(modelID string, version string) string {
+	return modelID + "@" + version
+}
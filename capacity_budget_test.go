@@ -0,0 +1,138 @@
+package modzy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+func TestCapacityBudget(t *testing.T) {
+	budget, err := NewCapacityBudget(
+		model.License{ProcessingEngines: "6"},
+		model.MinimumEngines{MinimumProcessingEnginesSum: 2},
+		[]model.ResourcesProcessingModel{
+			{Identifier: "a", Version: "1.0.0", Engines: make([]model.ResourcesProcessingModelEngine, 2)},
+			{Identifier: "b", Version: "1.0.0", Engines: make([]model.ResourcesProcessingModelEngine, 1)},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	budget.RecordReserved("a", "1.0.0", 1)
+	budget.RecordReserved("b", "1.0.0", 1)
+	if budget.Capacity("a", "1.0.0") != 2 || budget.Reserved("a", "1.0.0") != 1 || budget.Running() != 3 || budget.InUse() != 3 {
+		t.Errorf("Expected the running engines to be in use, got %d of %d", budget.InUse(), budget.Running())
+	}
+	if available, limited := budget.Available(); available != 3 || !limited {
+		t.Errorf("Expected 3 engines available, got %d", available)
+	}
+
+	if err := budget.CheckCapacity("a", "1.0.0", 5); err != nil {
+		t.Errorf("Expected a minimum of 5 engines to fit, got %v", err)
+	}
+	if err := budget.CheckCapacity("a", "1.0.0", 6); errors.Cause(err) != ErrLicenseExceeded {
+		t.Errorf("Expected ErrLicenseExceeded, got %v", err)
+	}
+	if err := budget.CheckCapacity("new", "1.0.0", 3); err != nil {
+		t.Errorf("Expected a new version to fit, got %v", err)
+	}
+
+	budget.SetReserved("new", "1.0.0", 3)
+	if budget.MinimumEngines != 5 || budget.Running() != 3 {
+		t.Errorf("Expected only the reserved engines to change, got %d reserved and %d running", budget.MinimumEngines, budget.Running())
+	}
+	if available, _ := budget.Available(); available != 0 {
+		t.Errorf("Expected the budget to be used up, got %d available", available)
+	}
+	if err := budget.CheckCapacity("a", "1.0.0", 1); err != nil {
+		t.Errorf("Expected lowering a minimum to always be allowed, got %v", err)
+	}
+
+	budget.SetCapacity("new", "1.0.0", 3)
+	if budget.Running() != 6 || budget.InUse() != 6 {
+		t.Errorf("Expected the started engines to not be counted twice, got %d in use", budget.InUse())
+	}
+}
+
+func TestCapacityBudgetReservedEngines(t *testing.T) {
+	budget, _ := NewCapacityBudget(
+		model.License{ProcessingEngines: "6"},
+		model.MinimumEngines{MinimumProcessingEnginesSum: 5},
+		[]model.ResourcesProcessingModel{{Identifier: "a", Version: "1.0.0", Engines: make([]model.ResourcesProcessingModelEngine, 1)}},
+	)
+	if budget.InUse() != 5 {
+		t.Errorf("Expected the reserved engines to be in use, got %d", budget.InUse())
+	}
+	if err := budget.CheckCapacity("a", "1.0.0", 2); err == nil {
+		t.Errorf("Expected an unknown minimum to count in full against the license")
+	}
+
+	budget.RecordReserved("a", "1.0.0", 1)
+	if budget.MinimumEngines != 5 {
+		t.Errorf("Expected a recorded minimum to not change the sum, got %d", budget.MinimumEngines)
+	}
+	if err := budget.CheckCapacity("a", "1.0.0", 2); err != nil {
+		t.Errorf("Expected raising a known minimum by one to fit, got %v", err)
+	}
+	if err := budget.CheckCapacity("a", "1.0.0", 3); errors.Cause(err) != ErrLicenseExceeded {
+		t.Errorf("Expected the reserved engines to count against the license, got %v", err)
+	}
+	budget.SetReserved("a", "1.0.0", 2)
+	if budget.MinimumEngines != 6 || budget.InUse() != 6 || budget.Running() != 1 {
+		t.Errorf("Expected the reserved engines to follow the change, got %d", budget.MinimumEngines)
+	}
+}
+
+func TestCapacityBudgetUnlimited(t *testing.T) {
+	budget, _ := NewCapacityBudget(model.License{}, model.MinimumEngines{}, nil)
+	if _, limited := budget.Available(); limited {
+		t.Errorf("Expected an unlimited budget")
+	}
+	if err := budget.CheckCapacity("a", "1.0.0", 1000); err != nil {
+		t.Errorf("Expected anything to fit, got %v", err)
+	}
+	if _, err := NewCapacityBudget(model.License{ProcessingEngines: "lots"}, model.MinimumEngines{}, nil); err == nil {
+		t.Errorf("Expected an invalid license to fail")
+	}
+}
+
+func TestReadCapacityBudget(t *testing.T) {
+	var minimumErr error
+	client := &ClientFake{
+		AccountingFunc: func() AccountingClient {
+			return &AccountingClientFake{GetLicenseFunc: func(ctx context.Context) (*GetLicenseOutput, error) {
+				return &GetLicenseOutput{License: model.License{ProcessingEngines: "4"}}, nil
+			}}
+		},
+		ModelsFunc: func() ModelsClient {
+			return &ModelsClientFake{GetMinimumEnginesFunc: func(ctx context.Context) (*GetMinimumEnginesOutput, error) {
+				if minimumErr != nil {
+					return nil, minimumErr
+				}
+				return &GetMinimumEnginesOutput{Details: model.MinimumEngines{MinimumProcessingEnginesSum: 1}}, nil
+			}}
+		},
+		ResourcesFunc: func() ResourcesClient {
+			return &ResourcesClientFake{GetProcessingModelsFunc: func(ctx context.Context) (*GetProcessingModelsOutput, error) {
+				return &GetProcessingModelsOutput{Models: []model.ResourcesProcessingModel{
+					{Identifier: "a", Version: "1.0.0", Engines: make([]model.ResourcesProcessingModelEngine, 2)},
+				}}, nil
+			}}
+		},
+	}
+	budget, err := ReadCapacityBudget(context.TODO(), client)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if budget.Limit.Engines != 4 || budget.MinimumEngines != 1 || budget.Running() != 2 {
+		t.Errorf("Expected the budget to be read, got %+v", budget)
+	}
+
+	minimumErr = fmt.Errorf("nope")
+	if _, err := ReadCapacityBudget(context.TODO(), client); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
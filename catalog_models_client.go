@@ -0,0 +1,386 @@
+package modzy
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+// CatalogModelsClient is a read-only ModelsClient backed by a CatalogSnapshot.  Methods that change the catalog, or
+// that read data which is not part of a snapshot, return ErrNotImplemented.
+type CatalogModelsClient struct {
+	snapshot *CatalogSnapshot
+}
+
+var _ ModelsClient = &CatalogModelsClient{}
+
+func NewCatalogModelsClient(snapshot *CatalogSnapshot) *CatalogModelsClient {
+	return &CatalogModelsClient{
+		snapshot: snapshot,
+	}
+}
+
+func (c *CatalogModelsClient) findModel(modelID string) (*CatalogModel, error) {
+	for m := range c.snapshot.Models {
+		if c.snapshot.Models[m].Details.ModelID == modelID {
+			return &c.snapshot.Models[m], nil
+		}
+	}
+	return nil, errors.WithMessagef(ErrNotFound, "model %s is not in the catalog snapshot", modelID)
+}
+
+func (c *CatalogModelsClient) findVersion(modelID string, version string) (*CatalogModelVersion, error) {
+	m, err := c.findModel(modelID)
+	if err != nil {
+		return nil, err
+	}
+	for v := range m.Versions {
+		if m.Versions[v].Details.Version == version {
+			return &m.Versions[v], nil
+		}
+	}
+	return nil, errors.WithMessagef(ErrNotFound, "version %s of model %s is not in the catalog snapshot", version, modelID)
+}
+
+func (c *CatalogModelsClient) ListModels(ctx context.Context, input *ListModelsInput) (*ListModelsOutput, error) {
+	input.Paging = input.Paging.withDefaults()
+
+	matches := []model.ModelVersionSummary{}
+	for _, m := range c.snapshot.Models {
+		matched, err := matchesCatalogFilters(m.Details, input.Paging.Filters)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			matches = append(matches, model.ModelVersionSummary{
+				ID:            m.Details.ModelID,
+				LatestVersion: m.Details.LatestVersion,
+				Versions:      m.Details.Versions,
+			})
+		}
+	}
+
+	items, hasMore := pageOf(matches, input.Paging)
+	var nextPage *ListModelsInput
+	if hasMore {
+		nextPage = &ListModelsInput{
+			Paging: input.Paging.Next(),
+		}
+	}
+	return &ListModelsOutput{
+		Models:   items,
+		NextPage: nextPage,
+	}, nil
+}
+
+func (c *CatalogModelsClient) GetLatestModels(ctx context.Context) (*GetLatestModelsOutput, error) {
+	models := []model.ModelDetails{}
+	for _, m := range c.snapshot.Models {
+		models = append(models, m.Details)
+	}
+	sort.SliceStable(models, func(i, j int) bool {
+		return models[i].LastActiveDateTime.After(models[j].LastActiveDateTime.Time)
+	})
+	return &GetLatestModelsOutput{
+		Models: models,
+	}, nil
+}
+
+func (c *CatalogModelsClient) GetMinimumEngines(ctx context.Context) (*GetMinimumEnginesOutput, error) {
+	return nil, errors.WithMessage(ErrNotImplemented, "processing engines are not part of a catalog snapshot")
+}
+
+func (c *CatalogModelsClient) UpdateModelProcessingEngines(ctx context.Context, input *UpdateModelProcessingEnginesInput) (*UpdateModelProcessingEnginesOutput, error) {
+	return nil, errors.WithMessage(ErrNotImplemented, "a catalog snapshot is read-only")
+}
+
+func (c *CatalogModelsClient) GetModelDetails(ctx context.Context, input *GetModelDetailsInput) (*GetModelDetailsOutput, error) {
+	m, err := c.findModel(input.ModelID)
+	if err != nil {
+		return nil, err
+	}
+	return &GetModelDetailsOutput{
+		Details: m.Details,
+	}, nil
+}
+
+func (c *CatalogModelsClient) GetModelDetailsByName(ctx context.Context, input *GetModelDetailsByNameInput) (*GetModelDetailsOutput, error) {
+	for _, m := range c.snapshot.Models {
+		if strings.EqualFold(m.Details.Name, input.Name) {
+			return &GetModelDetailsOutput{
+				Details: m.Details,
+			}, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (c *CatalogModelsClient) ListModelVersions(ctx context.Context, input *ListModelVersionsInput) (*ListModelVersionsOutput, error) {
+	input.Paging = input.Paging.withDefaults()
+
+	m, err := c.findModel(input.ModelID)
+	if err != nil {
+		return nil, err
+	}
+	if len(input.Paging.Filters) > 0 {
+		return nil, errors.WithMessage(ErrNotImplemented, "model versions in a catalog snapshot can not be filtered")
+	}
+	versions := []model.ModelVersion{}
+	for _, v := range m.Versions {
+		versions = append(versions, model.ModelVersion{Version: v.Details.Version})
+	}
+
+	items, hasMore := pageOf(versions, input.Paging)
+	var nextPage *ListModelVersionsInput
+	if hasMore {
+		nextPage = &ListModelVersionsInput{
+			ModelID: input.ModelID,
+			Paging:  input.Paging.Next(),
+		}
+	}
+	return &ListModelVersionsOutput{
+		Versions: items,
+		NextPage: nextPage,
+	}, nil
+}
+
+func (c *CatalogModelsClient) GetRelatedModels(ctx context.Context, input *GetRelatedModelsInput) (*GetRelatedModelsOutput, error) {
+	return nil, errors.WithMessage(ErrNotImplemented, "related models are not part of a catalog snapshot")
+}
+
+func (c *CatalogModelsClient) GetModelVersionDetails(ctx context.Context, input *GetModelVersionDetailsInput) (*GetModelVersionDetailsOutput, error) {
+	v, err := c.findVersion(input.ModelID, input.Version)
+	if err != nil {
+		return nil, err
+	}
+	return &GetModelVersionDetailsOutput{
+		Details: v.Details,
+	}, nil
+}
+
+func (c *CatalogModelsClient) GetModelVersionSampleInput(ctx context.Context, input *GetModelVersionSampleInputInput) (*GetModelVersionSampleInputOutput, error) {
+	v, err := c.findVersion(input.ModelID, input.Version)
+	if err != nil {
+		return nil, err
+	}
+	if v.SampleInput == "" {
+		return nil, errors.WithMessagef(ErrNotFound, "version %s of model %s has no sample input", input.Version, input.ModelID)
+	}
+	return &GetModelVersionSampleInputOutput{
+		Sample: v.SampleInput,
+	}, nil
+}
+
+func (c *CatalogModelsClient) GetModelVersionSampleOutput(ctx context.Context, input *GetModelVersionSampleOutputInput) (*GetModelVersionSampleOutputOutput, error) {
+	v, err := c.findVersion(input.ModelID, input.Version)
+	if err != nil {
+		return nil, err
+	}
+	if v.SampleOutput == "" {
+		return nil, errors.WithMessagef(ErrNotFound, "version %s of model %s has no sample output", input.Version, input.ModelID)
+	}
+	return &GetModelVersionSampleOutputOutput{
+		Sample: v.SampleOutput,
+	}, nil
+}
+
+func (c *CatalogModelsClient) GetTags(ctx context.Context) (*GetTagsOutput, error) {
+	return &GetTagsOutput{
+		Tags: c.snapshot.Tags,
+	}, nil
+}
+
+func (c *CatalogModelsClient) GetTagModels(ctx context.Context, input *GetTagModelsInput) (*GetTagModelsOutput, error) {
+	wanted := map[string]bool{}
+	for _, id := range input.TagIDs {
+		wanted[id] = true
+	}
+
+	out := &GetTagModelsOutput{
+		Tags:   []model.ModelTag{},
+		Models: []model.ModelWithTags{},
+	}
+	for _, tag := range c.snapshot.Tags {
+		if wanted[tag.Identifier] {
+			out.Tags = append(out.Tags, tag)
+		}
+	}
+	for _, m := range c.snapshot.Models {
+		for _, tag := range m.Details.Tags {
+			if wanted[tag.Identifier] {
+				out.Models = append(out.Models, model.ModelWithTags{
+					Identifier: m.Details.ModelID,
+					Name:       m.Details.Name,
+					Tags:       m.Details.Tags,
+				})
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+// Search finds models whose name, description, author, tags or features contain every word of the query, and that have
+// every one of the provided tags (by identifier or name).  Either may be empty.  Models with more words in their name
+// are returned first.
+func (c *CatalogModelsClient) Search(query string, tags ...string) []model.ModelDetails {
+	terms := strings.Fields(strings.ToLower(query))
+
+	type scored struct {
+		details model.ModelDetails
+		score   int
+	}
+	matches := []scored{}
+	for _, m := range c.snapshot.Models {
+		if !hasCatalogTags(m.Details, tags) {
+			continue
+		}
+		name := strings.ToLower(m.Details.Name)
+		text := catalogSearchText(m.Details)
+		score := 0
+		matched := true
+		for _, term := range terms {
+			if !strings.Contains(text, term) {
+				matched = false
+				break
+			}
+			if strings.Contains(name, term) {
+				score++
+			}
+		}
+		if matched {
+			matches = append(matches, scored{details: m.Details, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].details.Name < matches[j].details.Name
+	})
+
+	found := []model.ModelDetails{}
+	for _, m := range matches {
+		found = append(found, m.details)
+	}
+	return found
+}
+
+func catalogSearchText(details model.ModelDetails) string {
+	parts := []string{details.Name, details.Description, details.Author}
+	for _, tag := range details.Tags {
+		parts = append(parts, tag.Name)
+	}
+	for _, feature := range details.Features {
+		parts = append(parts, feature.Name)
+	}
+	return strings.ToLower(strings.Join(parts, "\n"))
+}
+
+func hasCatalogTags(details model.ModelDetails, tags []string) bool {
+	for _, wanted := range tags {
+		found := false
+		for _, tag := range details.Tags {
+			if tag.Identifier == wanted || strings.EqualFold(tag.Name, wanted) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesCatalogFilters applies the ListModels filters that can be answered from a snapshot
+func matchesCatalogFilters(details model.ModelDetails, filters []Filter) (bool, error) {
+	for _, filter := range filters {
+		var matchValue func(value string) bool
+		switch ListModelsFilterField(filter.Field) {
+		case ListModelsFilterFieldModelID:
+			matchValue = func(value string) bool { return details.ModelID == value }
+		case ListModelsFilterFieldName:
+			matchValue = func(value string) bool { return containsFold(details.Name, value) }
+		case ListModelsFilterFieldAuthor:
+			matchValue = func(value string) bool { return containsFold(details.Author, value) }
+		case ListModelsFilterFieldDescription:
+			matchValue = func(value string) bool { return containsFold(details.Description, value) }
+		case ListModelsFilterFieldIsActive:
+			matchValue = func(value string) bool { return value == strconv.FormatBool(details.IsActive) }
+		case ListModelsFilterFieldIsCommercial:
+			matchValue = func(value string) bool { return value == strconv.FormatBool(details.IsCommercial) }
+		case ListModelsFilterFieldIsRecommended:
+			matchValue = func(value string) bool { return value == strconv.FormatBool(details.IsRecommended) }
+		default:
+			return false, errors.WithMessagef(ErrNotImplemented, "filtering a catalog snapshot by %s", filter.Field)
+		}
+
+		matchedAny, matchedAll := false, true
+		for _, value := range filter.Values {
+			if matchValue(value) {
+				matchedAny = true
+			} else {
+				matchedAll = false
+			}
+		}
+		if filter.Type == FilterTypeOr && !matchedAny || filter.Type != FilterTypeOr && !matchedAll {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func containsFold(s string, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// pageOf returns the items of the requested page, and if there are more items after it
+func pageOf[T any](items []T, paging PagingInput) ([]T, bool) {
+	start := (paging.Page - 1) * paging.PerPage
+	if start >= len(items) {
+		return []T{}, false
+	}
+	end := start + paging.PerPage
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end], end < len(items)
+}
+
+func (c *CatalogModelsClient) CreateModel(ctx context.Context, input *CreateModelInput) (*CreateModelOutput, error) {
+	return nil, errors.WithMessage(ErrNotImplemented, "a catalog snapshot is read-only")
+}
+
+func (c *CatalogModelsClient) CreateModelVersion(ctx context.Context, input *CreateModelVersionInput) (*CreateModelVersionOutput, error) {
+	return nil, errors.WithMessage(ErrNotImplemented, "a catalog snapshot is read-only")
+}
+
+func (c *CatalogModelsClient) UploadModelContainerImage(ctx context.Context, input *UploadModelContainerImageInput) (*UploadModelContainerImageOutput, error) {
+	return nil, errors.WithMessage(ErrNotImplemented, "a catalog snapshot is read-only")
+}
+
+func (c *CatalogModelsClient) UpdateModelVersion(ctx context.Context, input *UpdateModelVersionInput) (*UpdateModelVersionOutput, error) {
+	return nil, errors.WithMessage(ErrNotImplemented, "a catalog snapshot is read-only")
+}
+
+func (c *CatalogModelsClient) StartModelVersionLoad(ctx context.Context, input *StartModelVersionLoadInput) (*StartModelVersionLoadOutput, error) {
+	return nil, errors.WithMessage(ErrNotImplemented, "a catalog snapshot is read-only")
+}
+
+func (c *CatalogModelsClient) WaitForModelVersionLoad(ctx context.Context, input *WaitForModelVersionValidationInput, pollInterval time.Duration) (*GetModelVersionDetailsOutput, error) {
+	return nil, errors.WithMessage(ErrNotImplemented, "a catalog snapshot is read-only")
+}
+
+func (c *CatalogModelsClient) StartModelVersionRun(ctx context.Context, input *StartModelVersionRunInput) (*StartModelVersionRunOutput, error) {
+	return nil, errors.WithMessage(ErrNotImplemented, "a catalog snapshot is read-only")
+}
+
+func (c *CatalogModelsClient) WaitForModelVersionRun(ctx context.Context, input *WaitForModelVersionValidationInput, pollInterval time.Duration) (*GetModelVersionDetailsOutput, error) {
+	return nil, errors.WithMessage(ErrNotImplemented, "a catalog snapshot is read-only")
+}
@@ -0,0 +1,212 @@
+package modzy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+func catalogTestSnapshot() *CatalogSnapshot {
+	nlp := model.ModelTag{Identifier: "nlp", Name: "Natural Language"}
+	vision := model.ModelTag{Identifier: "vision", Name: "Computer Vision"}
+	return &CatalogSnapshot{
+		SnapshotVersion: CatalogSnapshotVersion,
+		Tags:            []model.ModelTag{nlp, vision},
+		Models: []CatalogModel{
+			{
+				Details: model.ModelDetails{
+					ModelID:            "sentiment",
+					Name:               "Sentiment Analysis",
+					Description:        "Scores the sentiment of english text",
+					Author:             "Open Source",
+					IsActive:           true,
+					Tags:               []model.ModelTag{nlp},
+					Versions:           model.SortedVersions{"1.0.0", "0.9.0"},
+					LastActiveDateTime: model.ModzyTime{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+				},
+				Versions: []CatalogModelVersion{
+					{Details: model.ModelVersionDetails{ModelID: "sentiment", Version: "1.0.0"}, SampleInput: "in", SampleOutput: "out"},
+					{Details: model.ModelVersionDetails{ModelID: "sentiment", Version: "0.9.0"}},
+				},
+			},
+			{
+				Details: model.ModelDetails{
+					ModelID:            "objects",
+					Name:               "Object Detection",
+					Description:        "Finds objects in images, including text",
+					Author:             "Modzy",
+					Tags:               []model.ModelTag{vision},
+					Features:           []model.ModelFeature{{Name: "Explainable"}},
+					LastActiveDateTime: model.ModzyTime{Time: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+				},
+			},
+			{
+				Details: model.ModelDetails{
+					ModelID:     "language",
+					Name:        "Language Identification",
+					Description: "Identifies the language of a text",
+					Author:      "Open Source",
+					IsActive:    true,
+					Tags:        []model.ModelTag{nlp},
+				},
+			},
+		},
+	}
+}
+
+func TestCatalogModelsClientSearch(t *testing.T) {
+	client := NewCatalogModelsClient(catalogTestSnapshot())
+
+	found := client.Search("text")
+	if len(found) != 3 {
+		t.Errorf("expected 3 models to mention text, got %d", len(found))
+	}
+	// the tag name matches as well, but name matches come first
+	found = client.Search("language text")
+	if len(found) != 2 || found[0].ModelID != "language" || found[1].ModelID != "sentiment" {
+		t.Errorf("expected every word to match: %+v", found)
+	}
+	found = client.Search("", "Natural Language")
+	if len(found) != 2 || found[0].ModelID != "language" || found[1].ModelID != "sentiment" {
+		t.Errorf("tag search not expected: %+v", found)
+	}
+	found = client.Search("text", "vision")
+	if len(found) != 1 || found[0].ModelID != "objects" {
+		t.Errorf("query and tag search not expected: %+v", found)
+	}
+	found = client.Search("explainable")
+	if len(found) != 1 || found[0].ModelID != "objects" {
+		t.Errorf("feature search not expected: %+v", found)
+	}
+	found = client.Search("analysis SENTIMENT")
+	if len(found) != 1 || found[0].ModelID != "sentiment" {
+		t.Errorf("search should ignore case: %+v", found)
+	}
+	if found := client.Search("nothing matches"); len(found) != 0 {
+		t.Errorf("expected no matches: %+v", found)
+	}
+}
+
+func TestCatalogModelsClientListModels(t *testing.T) {
+	client := NewCatalogModelsClient(catalogTestSnapshot())
+	ctx := context.TODO()
+
+	out, err := client.ListModels(ctx, (&ListModelsInput{}).WithPaging(2, 1))
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if len(out.Models) != 2 || out.NextPage == nil {
+		t.Errorf("first page not expected: %+v", out)
+	}
+	out, _ = client.ListModels(ctx, out.NextPage)
+	if len(out.Models) != 1 || out.NextPage != nil {
+		t.Errorf("last page not expected: %+v", out)
+	}
+
+	all, err := NewListModelsPager(client, (&ListModelsInput{}).
+		WithFilter(ListModelsFilterFieldAuthor, "open source").
+		WithFilter(ListModelsFilterFieldIsActive, "true")).ListAll(ctx, 0)
+	if err != nil || len(all) != 2 {
+		t.Errorf("filtered models not expected: %+v, %v", all, err)
+	}
+	all, _ = NewListModelsPager(client, (&ListModelsInput{}).
+		WithFilterOr(ListModelsFilterFieldModelID, "objects", "language")).ListAll(ctx, 0)
+	if len(all) != 2 {
+		t.Errorf("or filter not expected: %+v", all)
+	}
+
+	if _, err := client.ListModels(ctx, (&ListModelsInput{}).WithFilter(ListModelsFilterFieldIsExpired, "true")); errors.Cause(err) != ErrNotImplemented {
+		t.Errorf("expected ErrNotImplemented, got %v", err)
+	}
+}
+
+func TestCatalogModelsClientDetails(t *testing.T) {
+	client := NewCatalogModelsClient(catalogTestSnapshot())
+	ctx := context.TODO()
+
+	details, err := client.GetModelDetails(ctx, &GetModelDetailsInput{ModelID: "sentiment"})
+	if err != nil || details.Details.Name != "Sentiment Analysis" {
+		t.Errorf("details not expected: %+v, %v", details, err)
+	}
+	if _, err := client.GetModelDetails(ctx, &GetModelDetailsInput{ModelID: "missing"}); errors.Cause(err) != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	byName, err := client.GetModelDetailsByName(ctx, &GetModelDetailsByNameInput{Name: "object detection"})
+	if err != nil || byName.Details.ModelID != "objects" {
+		t.Errorf("details by name not expected: %+v, %v", byName, err)
+	}
+	if _, err := client.GetModelDetailsByName(ctx, &GetModelDetailsByNameInput{Name: "missing"}); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	versions, err := client.ListModelVersions(ctx, (&ListModelVersionsInput{ModelID: "sentiment"}).WithPaging(1, 1))
+	if err != nil || len(versions.Versions) != 1 || versions.NextPage.ModelID != "sentiment" {
+		t.Errorf("versions not expected: %+v, %v", versions, err)
+	}
+	if _, err := client.ListModelVersions(ctx, (&ListModelVersionsInput{ModelID: "sentiment"}).WithFilter(ListModelVersionsFilterFieldIsActive, "true")); errors.Cause(err) != ErrNotImplemented {
+		t.Errorf("expected ErrNotImplemented, got %v", err)
+	}
+	version, err := client.GetModelVersionDetails(ctx, &GetModelVersionDetailsInput{ModelID: "sentiment", Version: "0.9.0"})
+	if err != nil || version.Details.Version != "0.9.0" {
+		t.Errorf("version details not expected: %+v, %v", version, err)
+	}
+	if _, err := client.GetModelVersionDetails(ctx, &GetModelVersionDetailsInput{ModelID: "sentiment", Version: "2.0.0"}); errors.Cause(err) != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	sampleInput, err := client.GetModelVersionSampleInput(ctx, &GetModelVersionSampleInputInput{ModelID: "sentiment", Version: "1.0.0"})
+	if err != nil || sampleInput.Sample != "in" {
+		t.Errorf("sample input not expected: %+v, %v", sampleInput, err)
+	}
+	sampleOutput, err := client.GetModelVersionSampleOutput(ctx, &GetModelVersionSampleOutputInput{ModelID: "sentiment", Version: "1.0.0"})
+	if err != nil || sampleOutput.Sample != "out" {
+		t.Errorf("sample output not expected: %+v, %v", sampleOutput, err)
+	}
+	if _, err := client.GetModelVersionSampleInput(ctx, &GetModelVersionSampleInputInput{ModelID: "sentiment", Version: "0.9.0"}); errors.Cause(err) != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := client.GetModelVersionSampleOutput(ctx, &GetModelVersionSampleOutputInput{ModelID: "sentiment", Version: "0.9.0"}); errors.Cause(err) != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCatalogModelsClientTagsAndLatest(t *testing.T) {
+	client := NewCatalogModelsClient(catalogTestSnapshot())
+	ctx := context.TODO()
+
+	tags, _ := client.GetTags(ctx)
+	if len(tags.Tags) != 2 {
+		t.Errorf("tags not expected: %+v", tags)
+	}
+	tagModels, _ := client.GetTagModels(ctx, &GetTagModelsInput{TagIDs: []string{"nlp"}})
+	if len(tagModels.Tags) != 1 || len(tagModels.Models) != 2 {
+		t.Errorf("tag models not expected: %+v", tagModels)
+	}
+	latest, _ := client.GetLatestModels(ctx)
+	if latest.Models[0].ModelID != "objects" {
+		t.Errorf("latest models not sorted: %+v", latest.Models)
+	}
+}
+
+func TestCatalogModelsClientReadOnly(t *testing.T) {
+	client := NewCatalogModelsClient(catalogTestSnapshot())
+	ctx := context.TODO()
+	if _, err := client.UpdateModelProcessingEngines(ctx, &UpdateModelProcessingEnginesInput{}); errors.Cause(err) != ErrNotImplemented {
+		t.Errorf("expected ErrNotImplemented, got %v", err)
+	}
+	if _, err := client.GetMinimumEngines(ctx); errors.Cause(err) != ErrNotImplemented {
+		t.Errorf("expected ErrNotImplemented, got %v", err)
+	}
+	if _, err := client.GetRelatedModels(ctx, &GetRelatedModelsInput{}); errors.Cause(err) != ErrNotImplemented {
+		t.Errorf("expected ErrNotImplemented, got %v", err)
+	}
+	if _, err := client.CreateModel(ctx, &CreateModelInput{}); errors.Cause(err) != ErrNotImplemented {
+		t.Errorf("expected ErrNotImplemented, got %v", err)
+	}
+	if _, err := client.UploadModelContainerImage(ctx, &UploadModelContainerImageInput{}); errors.Cause(err) != ErrNotImplemented {
+		t.Errorf("expected ErrNotImplemented, got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package modzy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// CatalogSnapshotVersion is the snapshot format written by this SDK.  Snapshots with a newer version can not be read.
+const CatalogSnapshotVersion = 1
+
+// CatalogSnapshot is a copy of the model catalog that can be browsed without access to the API.  Use
+// NewCatalogModelsClient to read it through the ModelsClient interface.
+type CatalogSnapshot struct {
+	SnapshotVersion int              `json:"snapshotVersion"`
+	CreatedAt       time.Time        `json:"createdAt"`
+	Tags            []model.ModelTag `json:"tags"`
+	Models          []CatalogModel   `json:"models"`
+}
+
+type CatalogModel struct {
+	Details  model.ModelDetails    `json:"details"`
+	Versions []CatalogModelVersion `json:"versions"`
+}
+
+type CatalogModelVersion struct {
+	Details      model.ModelVersionDetails `json:"details"`
+	SampleInput  string                    `json:"sampleInput,omitempty"`
+	SampleOutput string                    `json:"sampleOutput,omitempty"`
+}
+
+// ExportCatalogSnapshot reads every model, each of its versions, and their sample inputs and outputs.  Samples that do
+// not exist are left empty.
+func ExportCatalogSnapshot(ctx context.Context, client ModelsClient) (*CatalogSnapshot, error) {
+	tags, err := client.GetTags(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read tags")
+	}
+	summaries, err := NewListModelsPager(client, &ListModelsInput{}).ListAll(ctx, 0)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list models")
+	}
+
+	snapshot := &CatalogSnapshot{
+		SnapshotVersion: CatalogSnapshotVersion,
+		CreatedAt:       time.Now().UTC(),
+		Tags:            tags.Tags,
+		Models:          []CatalogModel{},
+	}
+	for _, summary := range summaries {
+		details, err := client.GetModelDetails(ctx, &GetModelDetailsInput{ModelID: summary.ID})
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to read model %s", summary.ID)
+		}
+		catalogModel := CatalogModel{
+			Details:  details.Details,
+			Versions: []CatalogModelVersion{},
+		}
+		for _, v := range details.Details.Versions {
+			catalogVersion, err := exportCatalogModelVersion(ctx, client, summary.ID, v)
+			if err != nil {
+				return nil, err
+			}
+			catalogModel.Versions = append(catalogModel.Versions, *catalogVersion)
+		}
+		snapshot.Models = append(snapshot.Models, catalogModel)
+	}
+	return snapshot, nil
+}
+
+func exportCatalogModelVersion(ctx context.Context, client ModelsClient, modelID string, version string) (*CatalogModelVersion, error) {
+	details, err := client.GetModelVersionDetails(ctx, &GetModelVersionDetailsInput{ModelID: modelID, Version: version})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to read version %s of model %s", version, modelID)
+	}
+	catalogVersion := &CatalogModelVersion{
+		Details: details.Details,
+	}
+
+	sampleInput, err := client.GetModelVersionSampleInput(ctx, &GetModelVersionSampleInputInput{ModelID: modelID, Version: version})
+	if err != nil && errors.Cause(err) != ErrNotFound {
+		return nil, errors.WithMessagef(err, "failed to read the sample input of version %s of model %s", version, modelID)
+	}
+	if err == nil {
+		catalogVersion.SampleInput = sampleInput.Sample
+	}
+
+	sampleOutput, err := client.GetModelVersionSampleOutput(ctx, &GetModelVersionSampleOutputInput{ModelID: modelID, Version: version})
+	if err != nil && errors.Cause(err) != ErrNotFound {
+		return nil, errors.WithMessagef(err, "failed to read the sample output of version %s of model %s", version, modelID)
+	}
+	if err == nil {
+		catalogVersion.SampleOutput = sampleOutput.Sample
+	}
+	return catalogVersion, nil
+}
+
+// Write encodes the snapshot as JSON
+func (s *CatalogSnapshot) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// WriteFile writes the snapshot as JSON to the file, through AppFs
+func (s *CatalogSnapshot) WriteFile(filename string) error {
+	f, err := AppFs.Create(filename)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to create catalog snapshot %s", filename)
+	}
+	if err := s.Write(f); err != nil {
+		f.Close()
+		return errors.WithMessagef(err, "failed to write catalog snapshot %s", filename)
+	}
+	return f.Close()
+}
+
+// ReadCatalogSnapshot decodes a snapshot written by CatalogSnapshot.Write
+func ReadCatalogSnapshot(r io.Reader) (*CatalogSnapshot, error) {
+	var snapshot CatalogSnapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, errors.WithMessage(err, "failed to parse catalog snapshot")
+	}
+	if snapshot.SnapshotVersion < 1 || snapshot.SnapshotVersion > CatalogSnapshotVersion {
+		return nil, fmt.Errorf("catalog snapshot version %d is not supported; expected at most %d", snapshot.SnapshotVersion, CatalogSnapshotVersion)
+	}
+	// the model identifier of a version is not part of its json
+	for m := range snapshot.Models {
+		for v := range snapshot.Models[m].Versions {
+			snapshot.Models[m].Versions[v].Details.ModelID = snapshot.Models[m].Details.ModelID
+		}
+	}
+	return &snapshot, nil
+}
+
+// ReadCatalogSnapshotFile reads a snapshot file through AppFs
+func ReadCatalogSnapshotFile(filename string) (*CatalogSnapshot, error) {
+	b, err := afero.ReadFile(AppFs, filename)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to read catalog snapshot %s", filename)
+	}
+	return ReadCatalogSnapshot(bytes.NewReader(b))
+}
@@ -0,0 +1,135 @@
+package modzy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/spf13/afero"
+)
+
+func TestExportCatalogSnapshot(t *testing.T) {
+	client := &ModelsClientFake{
+		GetTagsFunc: func(ctx context.Context) (*GetTagsOutput, error) {
+			return &GetTagsOutput{Tags: []model.ModelTag{{Identifier: "nlp", Name: "Natural Language"}}}, nil
+		},
+		ListModelsFunc: func(ctx context.Context, input *ListModelsInput) (*ListModelsOutput, error) {
+			return &ListModelsOutput{Models: []model.ModelVersionSummary{{ID: "m1"}, {ID: "m2"}}}, nil
+		},
+		GetModelDetailsFunc: func(ctx context.Context, input *GetModelDetailsInput) (*GetModelDetailsOutput, error) {
+			return &GetModelDetailsOutput{Details: model.ModelDetails{
+				ModelID:  input.ModelID,
+				Name:     "Model " + input.ModelID,
+				Versions: model.SortedVersions{"1.0.0", "0.1.0"},
+			}}, nil
+		},
+		GetModelVersionDetailsFunc: func(ctx context.Context, input *GetModelVersionDetailsInput) (*GetModelVersionDetailsOutput, error) {
+			return &GetModelVersionDetailsOutput{Details: model.ModelVersionDetails{ModelID: input.ModelID, Version: input.Version}}, nil
+		},
+		GetModelVersionSampleInputFunc: func(ctx context.Context, input *GetModelVersionSampleInputInput) (*GetModelVersionSampleInputOutput, error) {
+			if input.Version == "0.1.0" {
+				return nil, &ModzyHTTPError{StatusCode: 404}
+			}
+			return &GetModelVersionSampleInputOutput{Sample: `{"in":1}`}, nil
+		},
+		GetModelVersionSampleOutputFunc: func(ctx context.Context, input *GetModelVersionSampleOutputInput) (*GetModelVersionSampleOutputOutput, error) {
+			return &GetModelVersionSampleOutputOutput{Sample: `{"out":1}`}, nil
+		},
+	}
+	snapshot, err := ExportCatalogSnapshot(context.TODO(), client)
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if snapshot.SnapshotVersion != CatalogSnapshotVersion || snapshot.CreatedAt.IsZero() {
+		t.Errorf("snapshot header not expected: %+v", snapshot)
+	}
+	if len(snapshot.Tags) != 1 || len(snapshot.Models) != 2 {
+		t.Fatalf("snapshot content not expected: %+v", snapshot)
+	}
+	versions := snapshot.Models[1].Versions
+	if len(versions) != 2 || versions[0].Details.Version != "1.0.0" {
+		t.Errorf("versions not expected: %+v", versions)
+	}
+	if versions[0].SampleInput != `{"in":1}` || versions[1].SampleInput != "" || versions[1].SampleOutput != `{"out":1}` {
+		t.Errorf("samples not expected: %+v", versions)
+	}
+}
+
+func TestExportCatalogSnapshotError(t *testing.T) {
+	client := &ModelsClientFake{
+		GetTagsFunc: func(ctx context.Context) (*GetTagsOutput, error) {
+			return &GetTagsOutput{}, nil
+		},
+		ListModelsFunc: func(ctx context.Context, input *ListModelsInput) (*ListModelsOutput, error) {
+			return &ListModelsOutput{Models: []model.ModelVersionSummary{{ID: "m1"}}}, nil
+		},
+		GetModelDetailsFunc: func(ctx context.Context, input *GetModelDetailsInput) (*GetModelDetailsOutput, error) {
+			return &GetModelDetailsOutput{Details: model.ModelDetails{ModelID: input.ModelID, Versions: model.SortedVersions{"1.0.0"}}}, nil
+		},
+		GetModelVersionDetailsFunc: func(ctx context.Context, input *GetModelVersionDetailsInput) (*GetModelVersionDetailsOutput, error) {
+			return &GetModelVersionDetailsOutput{}, nil
+		},
+		GetModelVersionSampleInputFunc: func(ctx context.Context, input *GetModelVersionSampleInputInput) (*GetModelVersionSampleInputOutput, error) {
+			return &GetModelVersionSampleInputOutput{}, nil
+		},
+		GetModelVersionSampleOutputFunc: func(ctx context.Context, input *GetModelVersionSampleOutputInput) (*GetModelVersionSampleOutputOutput, error) {
+			return nil, fmt.Errorf("nope")
+		},
+	}
+	if _, err := ExportCatalogSnapshot(context.TODO(), client); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestCatalogSnapshotRoundTrip(t *testing.T) {
+	AppFs = afero.NewMemMapFs()
+	snapshot := &CatalogSnapshot{
+		SnapshotVersion: CatalogSnapshotVersion,
+		CreatedAt:       time.Now(),
+		Models: []CatalogModel{{
+			Details:  model.ModelDetails{ModelID: "m1", Name: "Model m1"},
+			Versions: []CatalogModelVersion{{Details: model.ModelVersionDetails{ModelID: "m1", Version: "1.0.0"}}},
+		}},
+	}
+	if err := snapshot.WriteFile("catalog.json"); err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	read, err := ReadCatalogSnapshotFile("catalog.json")
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if len(read.Models) != 1 || read.Models[0].Details.Name != "Model m1" {
+		t.Errorf("models not read: %+v", read.Models)
+	}
+	if read.Models[0].Versions[0].Details.ModelID != "m1" {
+		t.Errorf("version model identifier not restored")
+	}
+	if !read.CreatedAt.Equal(snapshot.CreatedAt) {
+		t.Errorf("created at not kept")
+	}
+
+	if _, err := ReadCatalogSnapshotFile("missing.json"); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestReadCatalogSnapshotVersion(t *testing.T) {
+	if _, err := ReadCatalogSnapshot(strings.NewReader(`{"snapshotVersion": 99}`)); err == nil {
+		t.Errorf("expected an error for a newer snapshot")
+	}
+	if _, err := ReadCatalogSnapshot(strings.NewReader(`{}`)); err == nil {
+		t.Errorf("expected an error for a missing version")
+	}
+	if _, err := ReadCatalogSnapshot(strings.NewReader(`nope`)); err == nil {
+		t.Errorf("expected an error for bad json")
+	}
+	var buf bytes.Buffer
+	(&CatalogSnapshot{SnapshotVersion: CatalogSnapshotVersion}).Write(&buf)
+	if _, err := ReadCatalogSnapshot(&buf); err != nil {
+		t.Errorf("err not nil: %v", err)
+	}
+}
@@ -55,11 +55,11 @@ func NewClient(baseURL string, opts ...ClientOption) Client {
 			httpClient: defaultHTTPClient,
 		},
 	}
-	client.WithOptions(opts...)
 
 	// setup our namespaced groupings that all share this as their base client
 	client.accountingClient = &standardAccountingClient{
-		baseClient: client,
+		baseClient:   client,
+		entitlements: newEntitlementCache(defaultEntitlementCacheTTL),
 	}
 	client.jobsClient = &standardJobsClient{
 		baseClient: client,
@@ -73,6 +73,9 @@ func NewClient(baseURL string, opts ...ClientOption) Client {
 	client.resourcesClient = &standardResourcesClient{
 		baseClient: client,
 	}
+	// a forbidden response may mean that the entitlements of the key have changed
+	client.requestor.onForbidden = client.accountingClient.InvalidateEntitlements
+	client.WithOptions(opts...)
 
 	return client
 }
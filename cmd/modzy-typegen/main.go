@@ -0,0 +1,81 @@
+// Command modzy-typegen writes Go types for the output of a model version, inferred from its sample output.
+//
+// It is meant to be run from a go:generate directive so that the types are regenerated when the model changes:
+//
+//	//go:generate go run github.com/modzy/sdk-go/cmd/modzy-typegen -model ed542963de -version 1.0.1 -type Sentiment
+//
+// The sample output is read from the API using the MODZY_BASE_URL and MODZY_API_KEY environment variables, or from a
+// catalog snapshot file when -snapshot is provided.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	modzy "github.com/modzy/sdk-go"
+	"github.com/modzy/sdk-go/typegen"
+)
+
+func main() {
+	modelID := flag.String("model", "", "identifier of the model (required)")
+	version := flag.String("version", "", "version of the model (required)")
+	typeName := flag.String("type", "", "name of the generated type (required)")
+	packageName := flag.String("package", os.Getenv("GOPACKAGE"), "package of the generated file; defaults to the package running go:generate")
+	outputName := flag.String("output-name", typegen.DefaultOutputName, "name of the model output to generate types for")
+	outFile := flag.String("out", "", "file to write; defaults to <type>_gen.go in lower case")
+	snapshot := flag.String("snapshot", "", "catalog snapshot file to read the sample output from instead of the API")
+	flag.Parse()
+
+	if *modelID == "" || *version == "" || *typeName == "" || *packageName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *outFile == "" {
+		*outFile = strings.ToLower(*typeName) + "_gen.go"
+	}
+
+	if err := run(context.Background(), *snapshot, *outFile, typegen.Options{
+		Package:    *packageName,
+		TypeName:   *typeName,
+		OutputName: *outputName,
+		ModelID:    *modelID,
+		Version:    *version,
+	}); err != nil {
+		log.Fatalf("modzy-typegen: %v", err)
+	}
+}
+
+func run(ctx context.Context, snapshotFile string, outFile string, options typegen.Options) error {
+	var models modzy.ModelsClient
+	if snapshotFile != "" {
+		snapshot, err := modzy.ReadCatalogSnapshotFile(snapshotFile)
+		if err != nil {
+			return err
+		}
+		models = modzy.NewCatalogModelsClient(snapshot)
+	} else {
+		baseURL, apiKey := os.Getenv("MODZY_BASE_URL"), os.Getenv("MODZY_API_KEY")
+		if baseURL == "" || apiKey == "" {
+			return fmt.Errorf("MODZY_BASE_URL and MODZY_API_KEY must be set when no snapshot is provided")
+		}
+		models = modzy.NewClient(baseURL).WithAPIKey(apiKey).Models()
+	}
+
+	sample, err := models.GetModelVersionSampleOutput(ctx, &modzy.GetModelVersionSampleOutputInput{ModelID: options.ModelID, Version: options.Version})
+	if err != nil {
+		return fmt.Errorf("failed to read the sample output: %w", err)
+	}
+	output, err := typegen.SelectOutput([]byte(sample.Sample), options.OutputName)
+	if err != nil {
+		return err
+	}
+	code, err := typegen.Generate(output, options)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outFile, code, 0644)
+}
@@ -1,6 +1,9 @@
 package modzy
 
-// JobStatus constants for known statuses
+// JobStatus is the status of a job, as used when filtering the job history.
+type JobStatus string
+
+// JobStatus constants for known statuses.  These are left untyped so that they can be compared with the status of job details.
 const (
 	JobStatusSubmitted  = "SUBMITTED"
 	JobStatusInProgress = "IN_PROGRESS"
@@ -9,3 +12,21 @@ const (
 	JobStatusTimedOut   = "TIMEDOUT"
 	JobStatusOpen       = "OPEN"
 )
+
+// knownJobStatuses are the statuses accepted by the job history filters
+var knownJobStatuses = map[JobStatus]bool{
+	JobStatusSubmitted:  true,
+	JobStatusInProgress: true,
+	JobStatusCompleted:  true,
+	JobStatusCanceled:   true,
+	JobStatusTimedOut:   true,
+	JobStatusOpen:       true,
+}
+
+// Statuses reported while a new model version is validated, in its container image load status and its run status
+const (
+	ModelLoadStatusComplete  = "COMPLETE"
+	ModelLoadStatusFailed    = "FAILED"
+	ModelRunStatusSuccessful = "SUCCESSFUL"
+	ModelRunStatusFailed     = "FAILED"
+)
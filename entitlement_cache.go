@@ -0,0 +1,100 @@
+package modzy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+const defaultEntitlementCacheTTL = 5 * time.Minute
+
+// entitlementCache holds the entitlements of the client's key for a while so that they are not read before every
+// request that depends on them.  Concurrent readers share a single load, and an invalidation that happens while a load
+// is in flight keeps that load's result from being cached.
+type entitlementCache struct {
+	sync.Mutex
+	ttl          time.Duration
+	now          func() time.Time
+	entitlements []model.Entitlement
+	expires      time.Time
+	generation   int
+	loading      chan struct{}
+}
+
+func newEntitlementCache(ttl time.Duration) *entitlementCache {
+	return &entitlementCache{
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+// get returns the cached entitlements, calling load when they are missing or expired
+func (c *entitlementCache) get(ctx context.Context, load func(ctx context.Context) ([]model.Entitlement, error)) ([]model.Entitlement, error) {
+	for {
+		c.Lock()
+		if c.entitlements != nil && c.now().Before(c.expires) {
+			entitlements := c.entitlements
+			c.Unlock()
+			return entitlements, nil
+		}
+		if loading := c.loading; loading != nil {
+			c.Unlock()
+			select {
+			case <-loading:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		loading := make(chan struct{})
+		c.loading = loading
+		generation := c.generation
+		c.Unlock()
+
+		entitlements, err := load(ctx)
+
+		c.Lock()
+		c.loading = nil
+		close(loading)
+		if err == nil && generation == c.generation {
+			c.store(entitlements)
+		}
+		c.Unlock()
+		return entitlements, err
+	}
+}
+
+// set replaces the cached entitlements with ones that were just read
+func (c *entitlementCache) set(entitlements []model.Entitlement) {
+	c.Lock()
+	defer c.Unlock()
+	c.generation++
+	c.store(entitlements)
+}
+
+func (c *entitlementCache) store(entitlements []model.Entitlement) {
+	if c.ttl <= 0 {
+		return
+	}
+	if entitlements == nil {
+		entitlements = []model.Entitlement{}
+	}
+	c.entitlements = entitlements
+	c.expires = c.now().Add(c.ttl)
+}
+
+func (c *entitlementCache) invalidate() {
+	c.Lock()
+	defer c.Unlock()
+	c.generation++
+	c.entitlements = nil
+}
+
+func (c *entitlementCache) setTTL(ttl time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	c.ttl = ttl
+	c.entitlements = nil
+}
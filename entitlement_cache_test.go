@@ -0,0 +1,138 @@
+package modzy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+func TestEntitlementCacheExpires(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newEntitlementCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	loads := 0
+	load := func(ctx context.Context) ([]model.Entitlement, error) {
+		loads++
+		return nil, nil
+	}
+
+	cache.get(context.TODO(), load)
+	now = now.Add(59 * time.Second)
+	cache.get(context.TODO(), load)
+	if loads != 1 {
+		t.Errorf("Expected one load within the ttl, got %d", loads)
+	}
+	now = now.Add(time.Second)
+	cache.get(context.TODO(), load)
+	if loads != 2 {
+		t.Errorf("Expected a load after the ttl, got %d", loads)
+	}
+	cache.invalidate()
+	cache.get(context.TODO(), load)
+	if loads != 3 {
+		t.Errorf("Expected a load after invalidating, got %d", loads)
+	}
+}
+
+func TestEntitlementCacheDisabled(t *testing.T) {
+	cache := newEntitlementCache(0)
+	loads := 0
+	load := func(ctx context.Context) ([]model.Entitlement, error) {
+		loads++
+		return []model.Entitlement{{Identifier: "a"}}, nil
+	}
+	cache.get(context.TODO(), load)
+	cache.set([]model.Entitlement{{Identifier: "b"}})
+	out, _ := cache.get(context.TODO(), load)
+	if loads != 2 || out[0].Identifier != "a" {
+		t.Errorf("Expected every get to load, got %d loads", loads)
+	}
+}
+
+func TestEntitlementCacheLoadError(t *testing.T) {
+	cache := newEntitlementCache(time.Minute)
+	loads := 0
+	_, err := cache.get(context.TODO(), func(ctx context.Context) ([]model.Entitlement, error) {
+		loads++
+		return nil, fmt.Errorf("nope")
+	})
+	if err == nil {
+		t.Errorf("Expected the load error")
+	}
+	cache.get(context.TODO(), func(ctx context.Context) ([]model.Entitlement, error) {
+		loads++
+		return nil, nil
+	})
+	if loads != 2 {
+		t.Errorf("Expected a failed load to not be cached")
+	}
+}
+
+func TestEntitlementCacheSharesLoad(t *testing.T) {
+	cache := newEntitlementCache(time.Minute)
+	release := make(chan struct{})
+	loads := 0
+	load := func(ctx context.Context) ([]model.Entitlement, error) {
+		loads++
+		<-release
+		return []model.Entitlement{{Identifier: "a"}}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out, err := cache.get(context.TODO(), load)
+			if err != nil || len(out) != 1 {
+				t.Errorf("Expected the shared entitlements, got %v, %v", out, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if loads != 1 {
+		t.Errorf("Expected a single load, got %d", loads)
+	}
+}
+
+func TestEntitlementCacheInvalidatedDuringLoad(t *testing.T) {
+	cache := newEntitlementCache(time.Minute)
+	loads := 0
+	load := func(ctx context.Context) ([]model.Entitlement, error) {
+		loads++
+		if loads == 1 {
+			cache.invalidate()
+		}
+		return nil, nil
+	}
+	cache.get(context.TODO(), load)
+	cache.get(context.TODO(), load)
+	if loads != 2 {
+		t.Errorf("Expected the stale load to not be cached, got %d loads", loads)
+	}
+}
+
+func TestEntitlementCacheWaitCanceled(t *testing.T) {
+	cache := newEntitlementCache(time.Minute)
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	go cache.get(context.TODO(), func(ctx context.Context) ([]model.Entitlement, error) {
+		close(started)
+		<-release
+		return nil, nil
+	})
+	<-started
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	if _, err := cache.get(ctx, nil); err != context.Canceled {
+		t.Errorf("Expected context canceled, got %v", err)
+	}
+}
@@ -2,17 +2,26 @@ package modzy
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
 // Known errors
 var (
-	ErrNotImplemented = fmt.Errorf("method not implemented")
-	ErrBadRequest     = fmt.Errorf("the API doesn’t understand the request. Something is missing")
-	ErrUnauthorized   = fmt.Errorf("the API key is missing or misspelled")
-	ErrForbidden      = fmt.Errorf("the API key doesn’t have the roles required to perform the request")
-	ErrNotFound       = fmt.Errorf("the API understands the request but a parameter is missing or misspelled")
-	ErrInternalServer = fmt.Errorf("something went wrong on the server’s side")
-	ErrUnknown        = fmt.Errorf("an unknown error was returned")
+	ErrNotImplemented        = fmt.Errorf("method not implemented")
+	ErrBadRequest            = fmt.Errorf("the API doesn’t understand the request. Something is missing")
+	ErrUnauthorized          = fmt.Errorf("the API key is missing or misspelled")
+	ErrForbidden             = fmt.Errorf("the API key doesn’t have the roles required to perform the request")
+	ErrNotFound              = fmt.Errorf("the API understands the request but a parameter is missing or misspelled")
+	ErrInternalServer        = fmt.Errorf("something went wrong on the server’s side")
+	ErrUnknown               = fmt.Errorf("an unknown error was returned")
+	ErrInvalidFilter         = fmt.Errorf("the filters can not be sent to the API")
+	ErrJobNotOpen            = fmt.Errorf("the job has already been closed or aborted")
+	ErrNoMatchingVersion     = fmt.Errorf("no active and available version matches the constraint")
+	ErrDeploymentFailed      = fmt.Errorf("the model deployment reported an error")
+	ErrModelValidationFailed = fmt.Errorf("the model version failed validation")
+	ErrLicenseExceeded       = fmt.Errorf("the change would use more processing engines than the license allows")
 )
 
 // ModzyHTTPError contains additional error information as returned by the http API
@@ -42,3 +51,41 @@ func (m *ModzyHTTPError) Cause() error {
 	}
 	return ErrUnknown
 }
+
+// ContainerImageUploadError is returned when a container image upload stops part way.  The parts that were uploaded are
+// kept so that the upload can continue from the next part.
+type ContainerImageUploadError struct {
+	UploadID string
+	NextPart int
+	Err      error
+}
+
+func (e *ContainerImageUploadError) Error() string {
+	return fmt.Sprintf("container image upload %s stopped at part %d: %v", e.UploadID, e.NextPart, e.Err)
+}
+
+func (e *ContainerImageUploadError) Cause() error {
+	return errors.Cause(e.Err)
+}
+
+// Resume returns a copy of the input that continues the upload from the part that failed
+func (e *ContainerImageUploadError) Resume(input *UploadModelContainerImageInput) *UploadModelContainerImageInput {
+	resumed := *input
+	resumed.UploadID = e.UploadID
+	resumed.NextPart = e.NextPart
+	return &resumed
+}
+
+// MissingEntitlementsError is returned by RequireEntitlements when the client's key lacks some of the entitlements.  Its
+// cause is ErrForbidden.
+type MissingEntitlementsError struct {
+	Missing []string
+}
+
+func (e *MissingEntitlementsError) Error() string {
+	return fmt.Sprintf("missing entitlements: %s", strings.Join(e.Missing, ", "))
+}
+
+func (e *MissingEntitlementsError) Cause() error {
+	return ErrForbidden
+}
@@ -0,0 +1,128 @@
+// Package explain renders the explanations returned by models when a job is submitted with Explain set.
+//
+// Image explanations can be drawn over the original input so that reviewers can see which pixels drove the prediction:
+//
+//	exp, err := model.DecodeImageExplanation(results.Results.Results["my-input"], "results.json")
+//	if err != nil {
+//		return err
+//	}
+//	original, _ := os.Open("input.png")
+//	out, _ := os.Create("explained.png")
+//	err = explain.RenderOverlayPNG(out, original, exp, explain.OverlayOptions{})
+package explain
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	// register jpeg as well so that RenderOverlayPNG can read the most common inputs
+	_ "image/jpeg"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+// DefaultOverlayColor is used when no color is provided in the OverlayOptions
+var DefaultOverlayColor = color.RGBA{R: 255, G: 0, B: 0, A: 255}
+
+// DefaultOverlayOpacity is used when no opacity is provided in the OverlayOptions
+const DefaultOverlayOpacity = 0.5
+
+type OverlayOptions struct {
+	// Color is painted over the pixels within the mask; defaults to DefaultOverlayColor.
+	Color color.Color
+	// Opacity of the overlay color between 0 and 1; defaults to DefaultOverlayOpacity.
+	Opacity float64
+}
+
+// Mask decodes the run-length encoded mask of an image explanation.  Pixels within the mask are opaque.
+func Mask(exp *model.ImageExplanation) (*image.Alpha, error) {
+	width, height := exp.Dimensions.Width, exp.Dimensions.Height
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("explanation has invalid dimensions %dx%d", width, height)
+	}
+	if len(exp.MaskRLE) != height {
+		return nil, fmt.Errorf("explanation mask has %d rows, expected %d", len(exp.MaskRLE), height)
+	}
+
+	mask := image.NewAlpha(image.Rect(0, 0, width, height))
+	for y, row := range exp.MaskRLE {
+		x := 0
+		for i, run := range row {
+			if run < 0 || x+run > width {
+				return nil, fmt.Errorf("explanation mask row %d does not fit within a width of %d", y, width)
+			}
+			// runs alternate starting with pixels outside of the mask
+			if i%2 == 1 {
+				for end := x + run; x < end; x++ {
+					mask.SetAlpha(x, y, color.Alpha{A: 255})
+				}
+			} else {
+				x += run
+			}
+		}
+	}
+	return mask, nil
+}
+
+// Overlay paints the explanation mask over the original image.  If the original image is not the same size as the
+// explanation, the mask is scaled to fit.
+func Overlay(original image.Image, exp *model.ImageExplanation, options OverlayOptions) (*image.RGBA, error) {
+	mask, err := Mask(exp)
+	if err != nil {
+		return nil, err
+	}
+
+	overlayColor := options.Color
+	if overlayColor == nil {
+		overlayColor = DefaultOverlayColor
+	}
+	opacity := options.Opacity
+	if opacity <= 0 || opacity > 1 {
+		opacity = DefaultOverlayOpacity
+	}
+
+	bounds := original.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(out, out.Bounds(), original, bounds.Min, draw.Src)
+
+	// the mask is applied as a uniform alpha so that the opacity is respected
+	scaled := scaleMask(mask, out.Bounds().Dx(), out.Bounds().Dy(), uint8(opacity*255))
+	draw.DrawMask(out, out.Bounds(), image.NewUniform(overlayColor), image.Point{}, scaled, image.Point{}, draw.Over)
+
+	return out, nil
+}
+
+// RenderOverlayPNG reads the original image (any registered format, including png and jpeg), paints the explanation
+// over it, and writes the result as a png.
+func RenderOverlayPNG(w io.Writer, original io.Reader, exp *model.ImageExplanation, options OverlayOptions) error {
+	img, _, err := image.Decode(original)
+	if err != nil {
+		return errors.WithMessage(err, "failed to decode original image")
+	}
+	out, err := Overlay(img, exp, options)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, out)
+}
+
+// scaleMask resizes the mask to the provided size using the nearest pixel and sets masked pixels to the provided alpha
+func scaleMask(mask *image.Alpha, width int, height int, alpha uint8) *image.Alpha {
+	src := mask.Bounds()
+	scaled := image.NewAlpha(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		srcY := y * src.Dy() / height
+		for x := 0; x < width; x++ {
+			srcX := x * src.Dx() / width
+			if mask.AlphaAt(srcX, srcY).A != 0 {
+				scaled.SetAlpha(x, y, color.Alpha{A: alpha})
+			}
+		}
+	}
+	return scaled
+}
@@ -0,0 +1,129 @@
+package explain
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+func testExplanation() *model.ImageExplanation {
+	return &model.ImageExplanation{
+		// 4x2: row 0 masks x=1..2, row 1 masks x=0 and x=3
+		MaskRLE: [][]int{
+			{1, 2, 1},
+			{0, 1, 2, 1},
+		},
+		Dimensions: model.ImageExplanationDimensions{Width: 4, Height: 2},
+	}
+}
+
+func TestMask(t *testing.T) {
+	mask, err := Mask(testExplanation())
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	expected := [][]uint8{
+		{0, 255, 255, 0},
+		{255, 0, 0, 255},
+	}
+	for y, row := range expected {
+		for x, a := range row {
+			if mask.AlphaAt(x, y).A != a {
+				t.Errorf("pixel %d,%d expected %d, got %d", x, y, a, mask.AlphaAt(x, y).A)
+			}
+		}
+	}
+}
+
+func TestMaskErrors(t *testing.T) {
+	for name, exp := range map[string]*model.ImageExplanation{
+		"invalid dimensions": {},
+		"mask has 1 rows":    {MaskRLE: [][]int{{1}}, Dimensions: model.ImageExplanationDimensions{Width: 1, Height: 2}},
+		"does not fit":       {MaskRLE: [][]int{{1, 2}}, Dimensions: model.ImageExplanationDimensions{Width: 2, Height: 1}},
+	} {
+		_, err := Mask(exp)
+		if err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("expected error containing %q, got %v", name, err)
+		}
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	original := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			original.Set(x, y, color.RGBA{0, 0, 255, 255})
+		}
+	}
+	out, err := Overlay(original, testExplanation(), OverlayOptions{Color: color.RGBA{255, 0, 0, 255}, Opacity: 1})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.RGBAAt(1, 0) != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("masked pixel not painted: %v", out.RGBAAt(1, 0))
+	}
+	if out.RGBAAt(0, 0) != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("unmasked pixel changed: %v", out.RGBAAt(0, 0))
+	}
+}
+
+func TestOverlayScaledWithDefaults(t *testing.T) {
+	original := image.NewRGBA(image.Rect(10, 10, 18, 14))
+	out, err := Overlay(original, testExplanation(), OverlayOptions{})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.Bounds().Dx() != 8 || out.Bounds().Dy() != 4 {
+		t.Errorf("output not the size of the original: %v", out.Bounds())
+	}
+	// x=2,y=0 maps to mask pixel 1,0 which is masked
+	if out.RGBAAt(2, 0).R == 0 {
+		t.Errorf("scaled masked pixel not painted: %v", out.RGBAAt(2, 0))
+	}
+	if out.RGBAAt(0, 0).R != 0 {
+		t.Errorf("scaled unmasked pixel painted: %v", out.RGBAAt(0, 0))
+	}
+}
+
+func TestOverlayBadExplanation(t *testing.T) {
+	_, err := Overlay(image.NewRGBA(image.Rect(0, 0, 1, 1)), &model.ImageExplanation{}, OverlayOptions{})
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestRenderOverlayPNG(t *testing.T) {
+	var in bytes.Buffer
+	_ = png.Encode(&in, image.NewRGBA(image.Rect(0, 0, 4, 2)))
+
+	var out bytes.Buffer
+	if err := RenderOverlayPNG(&out, &in, testExplanation(), OverlayOptions{}); err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	img, err := png.Decode(&out)
+	if err != nil {
+		t.Fatalf("output was not a png: %v", err)
+	}
+	if img.Bounds().Dx() != 4 {
+		t.Errorf("output not expected size: %v", img.Bounds())
+	}
+}
+
+func TestRenderOverlayPNGErrors(t *testing.T) {
+	err := RenderOverlayPNG(&bytes.Buffer{}, strings.NewReader("not an image"), testExplanation(), OverlayOptions{})
+	if err == nil || !strings.Contains(err.Error(), "failed to decode original image") {
+		t.Errorf("error not expected: %v", err)
+	}
+
+	var in bytes.Buffer
+	_ = png.Encode(&in, image.NewRGBA(image.Rect(0, 0, 4, 2)))
+	err = RenderOverlayPNG(&bytes.Buffer{}, &in, &model.ImageExplanation{}, OverlayOptions{})
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -0,0 +1,172 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+// CSVOptions control how outputs are flattened into columns
+type CSVOptions struct {
+	// Output is the name of the output to flatten, such as "results.json".
+	// If empty, every output is flattened using the output name as the first part of each column.
+	Output string
+	// Separator joins the names of nested fields.  Defaults to ".".
+	Separator string
+	// MaxDepth limits how deep nested fields are flattened; anything deeper is written as json.  Zero does not limit the depth.
+	MaxDepth int
+	// Columns fixes the flattened columns that are written; any other fields are left out.
+	// If not provided, the columns are taken from the first result that has outputs, and a later result with fields that
+	// are not in those columns is an error.
+	Columns []string
+	// MaxPendingRows limits how many rows without outputs (such as failures) are held while the columns are not yet
+	// known.  Defaults to 1000; provide Columns to write every row as it arrives.
+	MaxPendingRows int
+}
+
+const defaultMaxPendingRows = 1000
+
+// csvFixedColumns are written before the flattened output columns
+var csvFixedColumns = []string{"input", "status", "elapsedTime", "error"}
+
+type csvRow struct {
+	fixed  []string
+	fields map[string]string
+}
+
+type csvWriter struct {
+	writer        *csv.Writer
+	options       CSVOptions
+	columns       []string
+	headerWritten bool
+	// pending holds rows provided before the columns could be decided
+	pending []csvRow
+}
+
+// NewCSVWriter creates a ResultWriter that writes one row per input with the outputs flattened into columns.
+// Rows for inputs without outputs (such as failures) are held until the columns are known.
+func NewCSVWriter(w io.Writer, options CSVOptions) ResultWriter {
+	if options.Separator == "" {
+		options.Separator = "."
+	}
+	if options.MaxPendingRows <= 0 {
+		options.MaxPendingRows = defaultMaxPendingRows
+	}
+	return &csvWriter{
+		writer:  csv.NewWriter(w),
+		options: options,
+		columns: options.Columns,
+	}
+}
+
+func (c *csvWriter) WriteResult(inputKey string, result model.JobResult) error {
+	fields, err := c.flatten(result)
+	if err != nil {
+		return err
+	}
+	row := csvRow{
+		fixed:  []string{inputKey, result.Status, strconv.Itoa(result.ElapsedTime), result.Error},
+		fields: fields,
+	}
+
+	if c.columns == nil {
+		if len(fields) == 0 {
+			if len(c.pending) >= c.options.MaxPendingRows {
+				return errors.Errorf("no result with outputs was found in the first %d rows to take the columns from; set CSVOptions.Columns", len(c.pending))
+			}
+			c.pending = append(c.pending, row)
+			return nil
+		}
+		c.columns = sortedFields(fields)
+	} else if c.options.Columns == nil {
+		if unknown := unknownFields(fields, c.columns); len(unknown) > 0 {
+			return errors.Errorf("input %s has fields that are not in the columns taken from the first result: [%s]; set CSVOptions.Columns", inputKey, strings.Join(unknown, ", "))
+		}
+	}
+	if err := c.flushPending(); err != nil {
+		return err
+	}
+	return c.writeRow(row)
+}
+
+func (c *csvWriter) Close() error {
+	if err := c.flushPending(); err != nil {
+		return err
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *csvWriter) flatten(result model.JobResult) (map[string]string, error) {
+	fields := map[string]string{}
+	outputs := []string{c.options.Output}
+	prefixed := c.options.Output == ""
+	if prefixed {
+		outputs = result.OutputNames()
+	}
+	for _, output := range outputs {
+		if prefixed && output == "error" {
+			// a failure's error is kept with the results; it is already written in the error column
+			continue
+		}
+		raw, err := result.Output(output)
+		if err != nil {
+			// failed inputs will not have outputs
+			continue
+		}
+		prefix := ""
+		if prefixed {
+			prefix = output
+		}
+		if err := flattenJSON(fields, prefix, raw, c.options.Separator, c.options.MaxDepth); err != nil {
+			return nil, errors.WithMessagef(err, "failed to flatten output %s", output)
+		}
+	}
+	return fields, nil
+}
+
+func (c *csvWriter) flushPending() error {
+	if !c.headerWritten {
+		header := append(append([]string{}, csvFixedColumns...), c.columns...)
+		if err := c.writer.Write(header); err != nil {
+			return err
+		}
+		c.headerWritten = true
+	}
+	for _, row := range c.pending {
+		if err := c.writeRow(row); err != nil {
+			return err
+		}
+	}
+	c.pending = nil
+	return nil
+}
+
+// unknownFields returns the sorted fields that are not one of the columns
+func unknownFields(fields map[string]string, columns []string) []string {
+	known := map[string]bool{}
+	for _, column := range columns {
+		known[column] = true
+	}
+	unknown := []string{}
+	for field := range fields {
+		if !known[field] {
+			unknown = append(unknown, field)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+func (c *csvWriter) writeRow(row csvRow) error {
+	record := append([]string{}, row.fixed...)
+	for _, column := range c.columns {
+		record = append(record, row.fields[column])
+	}
+	return c.writer.Write(record)
+}
@@ -0,0 +1,122 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJobResults(NewCSVWriter(&buf, CSVOptions{Output: "results.json"}), testJobResults(t)); err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	expected := strings.Join([]string{
+		"input,status,elapsedTime,error,box.x,box.y,label",
+		"a,SUCCESSFUL,10,,3,4,cat",
+		"b,SUCCESSFUL,20,,1,2,dog",
+		"c,FAILED,3,bad input,,,",
+		"",
+	}, "\n")
+	if buf.String() != expected {
+		t.Errorf("csv not expected:\n%s", buf.String())
+	}
+}
+
+func TestCSVWriterAllOutputs(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf, CSVOptions{Separator: "_", MaxDepth: 1})
+	if err := WriteJobResults(w, testJobResults(t)); err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	header := strings.Split(buf.String(), "\n")[0]
+	if header != "input,status,elapsedTime,error,results.json_box,results.json_label" {
+		t.Errorf("header not expected: %s", header)
+	}
+}
+
+func TestCSVWriterFixedColumns(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf, CSVOptions{Output: "results.json", Columns: []string{"label", "missing"}})
+	if err := WriteJobResults(w, testJobResults(t)); err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if lines[0] != "input,status,elapsedTime,error,label,missing" {
+		t.Errorf("header not expected: %s", lines[0])
+	}
+	if lines[1] != "a,SUCCESSFUL,10,,cat," {
+		t.Errorf("row not expected: %s", lines[1])
+	}
+}
+
+func TestCSVWriterFailuresFirst(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf, CSVOptions{Output: "results.json"})
+	_ = w.WriteResult("failed", model.JobResult{Status: "FAILED", Error: "nope"})
+	if buf.Len() != 0 {
+		t.Errorf("rows should be held until the columns are known")
+	}
+	_ = w.WriteResult("ok", model.JobResult{Status: "SUCCESSFUL", Data: map[string]interface{}{"results.json": map[string]interface{}{"a": 1}}})
+	if err := w.Close(); err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	expected := "input,status,elapsedTime,error,a\nfailed,FAILED,0,nope,\nok,SUCCESSFUL,0,,1\n"
+	if buf.String() != expected {
+		t.Errorf("csv not expected:\n%s", buf.String())
+	}
+}
+
+func TestCSVWriterOnlyFailures(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf, CSVOptions{})
+	_ = w.WriteResult("failed", model.JobResult{Status: "FAILED", Error: "nope"})
+	if err := w.Close(); err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if buf.String() != "input,status,elapsedTime,error\nfailed,FAILED,0,nope\n" {
+		t.Errorf("csv not expected:\n%s", buf.String())
+	}
+}
+
+func TestCSVWriterBadOutput(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf, CSVOptions{Output: "results.json"})
+	err := w.WriteResult("bad", model.JobResult{RawData: map[string]json.RawMessage{"results.json": []byte(`junk`)}})
+	if err == nil || !strings.Contains(err.Error(), "failed to flatten output results.json") {
+		t.Errorf("error not expected: %v", err)
+	}
+}
+
+func TestCSVWriterUnknownFields(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf, CSVOptions{Output: "results.json"})
+	if err := w.WriteResult("a", model.JobResult{Data: map[string]interface{}{"results.json": map[string]interface{}{"a": 1}}}); err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	err := w.WriteResult("b", model.JobResult{Data: map[string]interface{}{"results.json": map[string]interface{}{"a": 1, "c": 2, "b": 3}}})
+	if err == nil || !strings.Contains(err.Error(), "input b has fields that are not in the columns taken from the first result: [b, c]") {
+		t.Errorf("error not expected: %v", err)
+	}
+}
+
+func TestCSVWriterMaxPendingRows(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf, CSVOptions{Output: "results.json", MaxPendingRows: 2})
+	_ = w.WriteResult("1", model.JobResult{Status: "FAILED"})
+	_ = w.WriteResult("2", model.JobResult{Status: "FAILED"})
+	err := w.WriteResult("3", model.JobResult{Status: "FAILED"})
+	if err == nil || !strings.Contains(err.Error(), "set CSVOptions.Columns") {
+		t.Errorf("error not expected: %v", err)
+	}
+
+	buf.Reset()
+	w = NewCSVWriter(&buf, CSVOptions{Output: "results.json", Columns: []string{"a"}, MaxPendingRows: 1})
+	_ = w.WriteResult("1", model.JobResult{Status: "FAILED"})
+	if err := w.WriteResult("2", model.JobResult{Status: "FAILED"}); err != nil {
+		t.Errorf("expected rows to be written right away with Columns, got %v", err)
+	}
+}
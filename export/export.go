@@ -0,0 +1,82 @@
+// Package export writes job results to files that can be used outside of Go, such as JSONL or CSV.
+//
+// All writers consume results one input at a time so that very large batch results never need to be held in memory:
+//
+//	csvWriter := export.NewCSVWriter(file, export.CSVOptions{Output: "results.json"})
+//	for inputKey, result := range someStreamOfResults {
+//		if err := csvWriter.WriteResult(inputKey, result); err != nil {
+//			return err
+//		}
+//	}
+//	return csvWriter.Close()
+//
+// Or for a complete set of results:
+//
+//	err := export.WriteJobResults(csvWriter, results.Results)
+package export
+
+import (
+	"sort"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+// ResultWriter consumes job results one input at a time.
+type ResultWriter interface {
+	// WriteResult writes the result of a single input
+	WriteResult(inputKey string, result model.JobResult) error
+	// Close flushes any buffered output.  It does not close the underlying io.Writer.
+	Close() error
+}
+
+// WriteJobResults writes all of the results and then all of the failures (each in input key order) and then closes the writer.
+func WriteJobResults(w ResultWriter, results model.JobResults) error {
+	for _, set := range []map[string]model.JobResult{results.Results, results.Failures} {
+		for _, inputKey := range sortedKeys(set) {
+			if err := w.WriteResult(inputKey, set[inputKey]); err != nil {
+				return errors.WithMessagef(err, "failed to write result for input %s", inputKey)
+			}
+		}
+	}
+	return w.Close()
+}
+
+type multiWriter struct {
+	writers []ResultWriter
+}
+
+// MultiWriter creates a ResultWriter that writes each result to all of the provided writers.
+func MultiWriter(writers ...ResultWriter) ResultWriter {
+	return &multiWriter{
+		writers: writers,
+	}
+}
+
+func (m *multiWriter) WriteResult(inputKey string, result model.JobResult) error {
+	for _, w := range m.writers {
+		if err := w.WriteResult(inputKey, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *multiWriter) Close() error {
+	var firstErr error
+	for _, w := range m.writers {
+		if err := w.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func sortedKeys(m map[string]model.JobResult) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,94 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+func testJobResults(t *testing.T) model.JobResults {
+	var results model.JobResults
+	err := json.Unmarshal([]byte(`{
+		"jobIdentifier": "job-1",
+		"total": 3,
+		"completed": 2,
+		"failed": 1,
+		"jobQueueTime": 5,
+		"results": {
+			"b": {"status": "SUCCESSFUL", "elapsedTime": 20, "results.json": {"label": "dog", "box": {"x": 1, "y": 2}}},
+			"a": {"status": "SUCCESSFUL", "elapsedTime": 10, "results.json": {"label": "cat", "box": {"x": 3, "y": 4}}}
+		},
+		"failures": {
+			"c": {"status": "FAILED", "elapsedTime": 3, "error": "bad input"}
+		}
+	}`), &results)
+	if err != nil {
+		t.Fatalf("failed to parse test results: %v", err)
+	}
+	return results
+}
+
+type recordingWriter struct {
+	keys   []string
+	closed bool
+	err    error
+}
+
+func (r *recordingWriter) WriteResult(inputKey string, result model.JobResult) error {
+	r.keys = append(r.keys, inputKey)
+	return r.err
+}
+
+func (r *recordingWriter) Close() error {
+	r.closed = true
+	return r.err
+}
+
+func TestWriteJobResultsOrder(t *testing.T) {
+	w := &recordingWriter{}
+	if err := WriteJobResults(w, testJobResults(t)); err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if fmt.Sprintf("%v", w.keys) != "[a b c]" {
+		t.Errorf("results not written in order: %v", w.keys)
+	}
+	if !w.closed {
+		t.Errorf("writer was not closed")
+	}
+}
+
+func TestWriteJobResultsError(t *testing.T) {
+	w := &recordingWriter{err: fmt.Errorf("nope")}
+	err := WriteJobResults(w, testJobResults(t))
+	if err == nil || err.Error() != "failed to write result for input a: nope" {
+		t.Errorf("error not expected: %v", err)
+	}
+}
+
+func TestMultiWriter(t *testing.T) {
+	w1 := &recordingWriter{}
+	w2 := &recordingWriter{}
+	if err := WriteJobResults(MultiWriter(w1, w2), testJobResults(t)); err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if len(w1.keys) != 3 || len(w2.keys) != 3 {
+		t.Errorf("results not written to all writers")
+	}
+	if !w1.closed || !w2.closed {
+		t.Errorf("writers not closed")
+	}
+}
+
+func TestMultiWriterCloseError(t *testing.T) {
+	w1 := &recordingWriter{err: fmt.Errorf("first")}
+	w2 := &recordingWriter{}
+	err := MultiWriter(w1, w2).Close()
+	if err == nil || err.Error() != "first" {
+		t.Errorf("error not expected: %v", err)
+	}
+	if !w2.closed {
+		t.Errorf("all writers should be closed")
+	}
+}
@@ -0,0 +1,87 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// flattenJSON turns nested json objects and arrays into a single level map keyed by the joined field names.
+// Arrays use the item index as the field name.  Values nested deeper than maxDepth are kept as json; a maxDepth of zero does not limit the depth.
+func flattenJSON(into map[string]string, prefix string, raw json.RawMessage, separator string, maxDepth int) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	return flattenValue(into, prefix, value, separator, 0, maxDepth)
+}
+
+func flattenValue(into map[string]string, prefix string, value interface{}, separator string, depth int, maxDepth int) error {
+	join := func(name string) string {
+		if prefix == "" {
+			return name
+		}
+		return prefix + separator + name
+	}
+
+	if maxDepth > 0 && depth >= maxDepth {
+		return flattenLeaf(into, prefix, value)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return flattenLeaf(into, prefix, value)
+		}
+		for name, inner := range v {
+			if err := flattenValue(into, join(name), inner, separator, depth+1, maxDepth); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return flattenLeaf(into, prefix, value)
+		}
+		for i, inner := range v {
+			if err := flattenValue(into, join(strconv.Itoa(i)), inner, separator, depth+1, maxDepth); err != nil {
+				return err
+			}
+		}
+	default:
+		return flattenLeaf(into, prefix, value)
+	}
+	return nil
+}
+
+func flattenLeaf(into map[string]string, key string, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		into[key] = ""
+	case string:
+		into[key] = v
+	case json.Number:
+		into[key] = v.String()
+	case bool:
+		into[key] = strconv.FormatBool(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to encode nested value of %s: %v", key, err)
+		}
+		into[key] = string(b)
+	}
+	return nil
+}
+
+func sortedFields(m map[string]string) []string {
+	fields := make([]string, 0, len(m))
+	for k := range m {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+	return fields
+}
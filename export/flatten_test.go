@@ -0,0 +1,47 @@
+package export
+
+import (
+	"testing"
+)
+
+func TestFlattenJSON(t *testing.T) {
+	fields := map[string]string{}
+	err := flattenJSON(fields, "", []byte(`{"a":{"b":1.50,"c":[true,null,"x"]},"d":{},"e":[]}`), ".", 0)
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	expected := map[string]string{
+		"a.b":   "1.50",
+		"a.c.0": "true",
+		"a.c.1": "",
+		"a.c.2": "x",
+		"d":     "{}",
+		"e":     "[]",
+	}
+	if len(fields) != len(expected) {
+		t.Errorf("fields not expected: %v", fields)
+	}
+	for k, v := range expected {
+		if fields[k] != v {
+			t.Errorf("expected %s to be %q, got %q", k, v, fields[k])
+		}
+	}
+}
+
+func TestFlattenJSONMaxDepth(t *testing.T) {
+	fields := map[string]string{}
+	err := flattenJSON(fields, "out", []byte(`{"a":{"b":{"c":1}}}`), "/", 2)
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if fields["out/a/b"] != `{"c":1}` {
+		t.Errorf("deep value not kept as json: %v", fields)
+	}
+}
+
+func TestFlattenJSONBad(t *testing.T) {
+	err := flattenJSON(map[string]string{}, "", []byte(`junk`), ".", 0)
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -0,0 +1,38 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+type jsonlLine struct {
+	Input  string          `json:"input"`
+	Result model.JobResult `json:"result"`
+}
+
+type jsonlWriter struct {
+	encoder *json.Encoder
+}
+
+// NewJSONLWriter creates a ResultWriter that writes one json object per line in the form:
+//
+//	{"input":"input-key","result":{"status":"SUCCESSFUL",...,"results.json":{...}}}
+func NewJSONLWriter(w io.Writer) ResultWriter {
+	return &jsonlWriter{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+func (j *jsonlWriter) WriteResult(inputKey string, result model.JobResult) error {
+	return j.encoder.Encode(jsonlLine{
+		Input:  inputKey,
+		Result: result,
+	})
+}
+
+func (j *jsonlWriter) Close() error {
+	// every line is written as it is provided
+	return nil
+}
@@ -0,0 +1,27 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestJSONLWriter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJobResults(NewJSONLWriter(&buf), testJobResults(t)); err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], `{"input":"a","result":{`) {
+		t.Errorf("line not expected: %s", lines[0])
+	}
+	if !strings.Contains(lines[0], `"results.json":{"label":"cat","box":{"x":3,"y":4}}`) {
+		t.Errorf("raw output not kept: %s", lines[0])
+	}
+	if !strings.Contains(lines[2], `"error":"bad input"`) {
+		t.Errorf("failure not written: %s", lines[2])
+	}
+}
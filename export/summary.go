@@ -0,0 +1,122 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+type summaryWriter struct {
+	writer *csv.Writer
+	job    *model.JobResults
+
+	inputs         int
+	statuses       map[string]int
+	failureReasons map[string]int
+	totalElapsed   int64
+	minElapsed     int
+	maxElapsed     int
+	firstStart     time.Time
+	lastEnd        time.Time
+}
+
+// NewSummaryWriter creates a ResultWriter that keeps running totals of the results and writes a summary sheet when closed.
+// The sheet is a csv with the columns "section", "name" and "value" and contains timing information and a count of each failure reason.
+// The job is optional; when provided, the job level timing is included as well.
+func NewSummaryWriter(w io.Writer, job *model.JobResults) ResultWriter {
+	return &summaryWriter{
+		writer:         csv.NewWriter(w),
+		job:            job,
+		statuses:       map[string]int{},
+		failureReasons: map[string]int{},
+	}
+}
+
+func (s *summaryWriter) WriteResult(inputKey string, result model.JobResult) error {
+	if s.inputs == 0 || result.ElapsedTime < s.minElapsed {
+		s.minElapsed = result.ElapsedTime
+	}
+	if result.ElapsedTime > s.maxElapsed {
+		s.maxElapsed = result.ElapsedTime
+	}
+	s.inputs++
+	s.totalElapsed += int64(result.ElapsedTime)
+	s.statuses[result.Status]++
+	if result.Error != "" {
+		s.failureReasons[result.Error]++
+	}
+	if !result.StartTime.IsZero() && (s.firstStart.IsZero() || result.StartTime.Before(s.firstStart)) {
+		s.firstStart = result.StartTime.Time
+	}
+	if result.EndTime.After(s.lastEnd) {
+		s.lastEnd = result.EndTime.Time
+	}
+	return nil
+}
+
+func (s *summaryWriter) Close() error {
+	rows := [][]string{{"section", "name", "value"}}
+	if s.job != nil {
+		rows = append(rows,
+			[]string{"job", "jobIdentifier", s.job.JobIdentifier},
+			[]string{"job", "total", strconv.Itoa(s.job.Total)},
+			[]string{"job", "completed", strconv.Itoa(s.job.Completed)},
+			[]string{"job", "failed", strconv.Itoa(s.job.Failed)},
+			[]string{"job", "submittedAt", formatTime(s.job.SubmittedAt.Time)},
+			[]string{"job", "jobQueueTime", strconv.Itoa(s.job.JobQueueTime)},
+			[]string{"job", "jobProcessedTime", strconv.Itoa(s.job.JobProcessedTime)},
+			[]string{"job", "jobElapsedTime", strconv.Itoa(s.job.JobElapsedTime)},
+		)
+	}
+
+	meanElapsed := int64(0)
+	if s.inputs > 0 {
+		meanElapsed = s.totalElapsed / int64(s.inputs)
+	}
+	rows = append(rows,
+		[]string{"timing", "inputs", strconv.Itoa(s.inputs)},
+		[]string{"timing", "firstStartTime", formatTime(s.firstStart)},
+		[]string{"timing", "lastEndTime", formatTime(s.lastEnd)},
+		[]string{"timing", "minElapsedTime", strconv.Itoa(s.minElapsed)},
+		[]string{"timing", "meanElapsedTime", strconv.FormatInt(meanElapsed, 10)},
+		[]string{"timing", "maxElapsedTime", strconv.Itoa(s.maxElapsed)},
+	)
+
+	for _, status := range sortedByCount(s.statuses) {
+		rows = append(rows, []string{"status", status, strconv.Itoa(s.statuses[status])})
+	}
+	for _, reason := range sortedByCount(s.failureReasons) {
+		rows = append(rows, []string{"failureReason", reason, strconv.Itoa(s.failureReasons[reason])})
+	}
+
+	if err := s.writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return s.writer.Error()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return model.ModzyTime{Time: t}.String()
+}
+
+// sortedByCount returns the keys with the highest count first, ties broken by name
+func sortedByCount(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
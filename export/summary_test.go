@@ -0,0 +1,44 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSummaryWriter(t *testing.T) {
+	results := testJobResults(t)
+	var buf bytes.Buffer
+	if err := WriteJobResults(NewSummaryWriter(&buf, &results), results); err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	out := buf.String()
+	for _, expected := range []string{
+		"section,name,value\n",
+		"job,jobIdentifier,job-1\n",
+		"job,jobQueueTime,5\n",
+		"timing,inputs,3\n",
+		"timing,minElapsedTime,3\n",
+		"timing,meanElapsedTime,11\n",
+		"timing,maxElapsedTime,20\n",
+		"status,SUCCESSFUL,2\nstatus,FAILED,1\n",
+		"failureReason,bad input,1\n",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("summary missing %q:\n%s", expected, out)
+		}
+	}
+}
+
+func TestSummaryWriterWithoutJob(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewSummaryWriter(&buf, nil).Close(); err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if strings.Contains(buf.String(), "job,") {
+		t.Errorf("job section should not be written: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "timing,inputs,0\n") {
+		t.Errorf("timing not written: %s", buf.String())
+	}
+}
@@ -1,14 +1,18 @@
 module github.com/modzy/sdk-go
 
-go 1.16
+go 1.23
 
 require (
 	github.com/docker/go-units v0.4.0
-	github.com/hashicorp/go-version v1.3.0 // indirect
-	github.com/joho/godotenv v1.3.0 // indirect
+	github.com/hashicorp/go-version v1.3.0
+	github.com/joho/godotenv v1.3.0
 	github.com/peterhellberg/link v1.1.0
 	github.com/pkg/errors v0.9.1
 	github.com/sirupsen/logrus v1.8.1
 	github.com/spf13/afero v1.6.0
+)
+
+require (
 	golang.org/x/sys v0.0.0-20210510120138-977fb7262007 // indirect
+	golang.org/x/text v0.3.3 // indirect
 )
@@ -17,6 +17,12 @@ type JobActions interface {
 	Cancel(ctx context.Context) (*CancelJobOutput, error)
 	// GetResults will get the results of a complete job
 	GetResults(ctx context.Context) (*GetJobResultsOutput, error)
+	// GetOutput will stream a single output file of a single input
+	GetOutput(ctx context.Context, inputKey string, outputName string) (*GetJobOutputOutput, error)
+	// DownloadOutputs will write every output of the job into a directory tree
+	DownloadOutputs(ctx context.Context, directory string, concurrency int) (*DownloadJobOutputsOutput, error)
+	// RetryFailedInputs will resubmit the failed inputs of a finished job and merge the new results into the original results
+	RetryFailedInputs(ctx context.Context, input *RetryFailedInputsInput) (*RetryFailedInputsOutput, error)
 }
 
 type standardJobActions struct {
@@ -55,6 +61,22 @@ func (j *standardJobActions) GetResults(ctx context.Context) (*GetJobResultsOutp
 	})
 }
 
+func (j *standardJobActions) GetOutput(ctx context.Context, inputKey string, outputName string) (*GetJobOutputOutput, error) {
+	return j.client.Jobs().GetJobOutput(ctx, &GetJobOutputInput{
+		JobIdentifier: j.jobIdentifier,
+		InputKey:      inputKey,
+		OutputName:    outputName,
+	})
+}
+
+func (j *standardJobActions) DownloadOutputs(ctx context.Context, directory string, concurrency int) (*DownloadJobOutputsOutput, error) {
+	return j.client.Jobs().DownloadJobOutputs(ctx, &DownloadJobOutputsInput{
+		JobIdentifier: j.jobIdentifier,
+		Directory:     directory,
+		Concurrency:   concurrency,
+	})
+}
+
 func (j *standardJobActions) GetModelDetails(ctx context.Context) (*GetModelVersionDetailsOutput, error) {
 	jobDetails, err := j.GetDetails(ctx)
 	if err != nil {
@@ -180,3 +180,53 @@ func TestJobActionsGetModelDetails(t *testing.T) {
 		t.Errorf("Did not hit test code")
 	}
 }
+
+func TestJobActionsGetOutput(t *testing.T) {
+	expectedCtx := context.WithValue(context.TODO(), testContextKey("ck"), "v")
+	jobID := "jobid"
+
+	client := &ClientFake{
+		JobsFunc: func() JobsClient {
+			return &JobsClientFake{
+				GetJobOutputFunc: func(ctx context.Context, input *GetJobOutputInput) (*GetJobOutputOutput, error) {
+					if expectedCtx != ctx {
+						t.Errorf("ctx not passed through")
+					}
+					if input.JobIdentifier != jobID || input.InputKey != "in" || input.OutputName != "out" {
+						t.Errorf("input not passed through: %+v", input)
+					}
+					return nil, fmt.Errorf("made it")
+				},
+			}
+		},
+	}
+	_, err := NewJobActions(client, jobID).GetOutput(expectedCtx, "in", "out")
+	if err.Error() != "made it" {
+		t.Errorf("Did not hit test code")
+	}
+}
+
+func TestJobActionsDownloadOutputs(t *testing.T) {
+	expectedCtx := context.WithValue(context.TODO(), testContextKey("ck"), "v")
+	jobID := "jobid"
+
+	client := &ClientFake{
+		JobsFunc: func() JobsClient {
+			return &JobsClientFake{
+				DownloadJobOutputsFunc: func(ctx context.Context, input *DownloadJobOutputsInput) (*DownloadJobOutputsOutput, error) {
+					if expectedCtx != ctx {
+						t.Errorf("ctx not passed through")
+					}
+					if input.JobIdentifier != jobID || input.Directory != "dir" || input.Concurrency != 3 {
+						t.Errorf("input not passed through: %+v", input)
+					}
+					return nil, fmt.Errorf("made it")
+				},
+			}
+		},
+	}
+	_, err := NewJobActions(client, jobID).DownloadOutputs(expectedCtx, "dir", 3)
+	if err.Error() != "made it" {
+		t.Errorf("Did not hit test code")
+	}
+}
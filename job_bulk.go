@@ -0,0 +1,140 @@
+package modzy
+
+import (
+	"context"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+type BulkJobsInput struct {
+	// Filter selects the jobs to act on, such as (&ListJobsHistoryInput{}).WithStatuses(JobStatusSubmitted, JobStatusInProgress).WithModel("name")
+	Filter *ListJobsHistoryInput
+	// Concurrency is the number of jobs acted on at the same time; defaults to 1.
+	Concurrency int
+	// DryRun lists the matching jobs without acting on them
+	DryRun bool
+}
+
+// BulkJobOutcome reports what happened to a single job during a bulk operation
+type BulkJobOutcome struct {
+	// Job is the job as it was listed
+	Job model.JobDetails
+	// Skipped is true when the operation was a dry run
+	Skipped bool
+	// Details are the job details after it was canceled or waited on
+	Details *model.JobDetails
+	// Results are set when fetching results
+	Results *model.JobResults
+	// Err is set if the operation failed for this job
+	Err error
+}
+
+type BulkJobsOutput struct {
+	Outcomes []BulkJobOutcome
+}
+
+// Failed returns the outcomes that have an error
+func (o *BulkJobsOutput) Failed() []BulkJobOutcome {
+	failed := []BulkJobOutcome{}
+	for _, outcome := range o.Outcomes {
+		if outcome.Err != nil {
+			failed = append(failed, outcome)
+		}
+	}
+	return failed
+}
+
+// BulkCancelJobs cancels every job matching the filter.  If the filter does not select any statuses, only SUBMITTED and
+// IN_PROGRESS jobs are canceled.
+func BulkCancelJobs(ctx context.Context, client JobsClient, input *BulkJobsInput) (*BulkJobsOutput, error) {
+	filter := &ListJobsHistoryInput{}
+	if input.Filter != nil {
+		filter.Paging = input.Filter.Paging
+	}
+	hasStatus := false
+	for _, f := range filter.Paging.Filters {
+		if ListJobsHistoryFilterField(f.Field) == ListJobsHistoryFilterFieldStatus {
+			hasStatus = true
+		}
+	}
+	if !hasStatus {
+		filter.Paging.Filters = append([]Filter{}, filter.Paging.Filters...)
+		filter.WithStatuses(JobStatusSubmitted, JobStatusInProgress)
+	}
+	cancelInput := *input
+	cancelInput.Filter = filter
+	return bulkJobs(ctx, client, &cancelInput, func(ctx context.Context, outcome *BulkJobOutcome) error {
+		out, err := client.CancelJob(ctx, &CancelJobInput{JobIdentifier: outcome.Job.JobIdentifier})
+		if err != nil {
+			return err
+		}
+		outcome.Details = &out.Details
+		return nil
+	})
+}
+
+// BulkWaitForJobs waits for every job matching the filter to finish processing.
+func BulkWaitForJobs(ctx context.Context, client JobsClient, input *BulkJobsInput, pollInterval time.Duration) (*BulkJobsOutput, error) {
+	return bulkJobs(ctx, client, input, func(ctx context.Context, outcome *BulkJobOutcome) error {
+		out, err := client.WaitForJobCompletion(ctx, &WaitForJobCompletionInput{JobIdentifier: outcome.Job.JobIdentifier}, pollInterval)
+		if err != nil {
+			return err
+		}
+		outcome.Details = &out.Details
+		return nil
+	})
+}
+
+// BulkGetJobResults reads the results of every job matching the filter.
+func BulkGetJobResults(ctx context.Context, client JobsClient, input *BulkJobsInput) (*BulkJobsOutput, error) {
+	return bulkJobs(ctx, client, input, func(ctx context.Context, outcome *BulkJobOutcome) error {
+		out, err := client.GetJobResults(ctx, &GetJobResultsInput{JobIdentifier: outcome.Job.JobIdentifier})
+		if err != nil {
+			return err
+		}
+		outcome.Results = &out.Results
+		return nil
+	})
+}
+
+// bulkJobs lists every matching job before acting on any of them; acting while paging would move jobs out of a
+// status filter and cause pages to be skipped.  A failure for one job is recorded in its outcome and does not stop the others.
+func bulkJobs(ctx context.Context, client JobsClient, input *BulkJobsInput, action func(ctx context.Context, outcome *BulkJobOutcome) error) (*BulkJobsOutput, error) {
+	filter := input.Filter
+	if filter == nil {
+		filter = &ListJobsHistoryInput{}
+	}
+	jobs, err := NewListJobsHistoryPager(client, filter).ListAll(ctx, 0)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list the matching jobs")
+	}
+
+	out := &BulkJobsOutput{
+		Outcomes: make([]BulkJobOutcome, len(jobs)),
+	}
+	for i, job := range jobs {
+		out.Outcomes[i] = BulkJobOutcome{Job: job, Skipped: input.DryRun}
+	}
+	if input.DryRun {
+		return out, nil
+	}
+
+	handled := make([]bool, len(out.Outcomes))
+	err = forEachConcurrently(ctx, len(out.Outcomes), input.Concurrency, func(ctx context.Context, i int) error {
+		handled[i] = true
+		out.Outcomes[i].Err = action(ctx, &out.Outcomes[i])
+		return nil
+	})
+	if err != nil {
+		// the jobs that were skipped once the context was done fail with its error
+		for i := range out.Outcomes {
+			if !handled[i] {
+				out.Outcomes[i].Err = err
+			}
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,156 @@
+package modzy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+func TestBulkCancelJobs(t *testing.T) {
+	canceled := &sync.Map{}
+	client := &JobsClientFake{
+		ListJobsHistoryFunc: func(ctx context.Context, input *ListJobsHistoryInput) (*ListJobsHistoryOutput, error) {
+			// 5 jobs over pages of 2
+			input.Paging = input.Paging.withDefaults()
+			out := &ListJobsHistoryOutput{}
+			for i := (input.Paging.Page - 1) * input.Paging.PerPage; i < 5 && len(out.Jobs) < input.Paging.PerPage; i++ {
+				out.Jobs = append(out.Jobs, model.JobDetails{JobIdentifier: fmt.Sprintf("job-%d", i)})
+			}
+			if len(out.Jobs) == input.Paging.PerPage {
+				out.NextPage = &ListJobsHistoryInput{Paging: input.Paging.Next()}
+			}
+			return out, nil
+		},
+		CancelJobFunc: func(ctx context.Context, input *CancelJobInput) (*CancelJobOutput, error) {
+			if input.JobIdentifier == "job-1" {
+				return nil, fmt.Errorf("nope")
+			}
+			canceled.Store(input.JobIdentifier, true)
+			return &CancelJobOutput{Details: model.JobDetails{JobIdentifier: input.JobIdentifier, Status: JobStatusCanceled}}, nil
+		},
+	}
+	out, err := BulkCancelJobs(context.TODO(), client, &BulkJobsInput{
+		Filter:      (&ListJobsHistoryInput{}).WithPaging(2, 1).WithStatuses(JobStatusSubmitted, JobStatusInProgress),
+		Concurrency: 3,
+	})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if len(out.Outcomes) != 5 {
+		t.Fatalf("expected 5 outcomes, got %d", len(out.Outcomes))
+	}
+	for _, outcome := range out.Outcomes {
+		if outcome.Job.JobIdentifier == "job-1" {
+			continue
+		}
+		if outcome.Err != nil || outcome.Details.Status != JobStatusCanceled {
+			t.Errorf("job not canceled: %+v", outcome)
+		}
+		if _, has := canceled.Load(outcome.Job.JobIdentifier); !has {
+			t.Errorf("cancel not called for %s", outcome.Job.JobIdentifier)
+		}
+	}
+	failed := out.Failed()
+	if len(failed) != 1 || failed[0].Job.JobIdentifier != "job-1" {
+		t.Errorf("failed outcomes not expected: %+v", failed)
+	}
+}
+
+func TestBulkCancelJobsDryRun(t *testing.T) {
+	client := &JobsClientFake{
+		ListJobsHistoryFunc: func(ctx context.Context, input *ListJobsHistoryInput) (*ListJobsHistoryOutput, error) {
+			filters := input.Paging.Filters
+			if len(filters) != 2 || filters[1].Field != "status" || strings.Join(filters[1].Values, ",") != "SUBMITTED,IN_PROGRESS" {
+				t.Errorf("Expected only running jobs to be canceled by default, got %+v", filters)
+			}
+			return &ListJobsHistoryOutput{Jobs: []model.JobDetails{{JobIdentifier: "job-0"}, {JobIdentifier: "job-1"}, {JobIdentifier: "job-2"}}}, nil
+		},
+		CancelJobFunc: func(ctx context.Context, input *CancelJobInput) (*CancelJobOutput, error) {
+			t.Errorf("dry run canceled %s", input.JobIdentifier)
+			return &CancelJobOutput{}, nil
+		},
+	}
+	filter := (&ListJobsHistoryInput{}).WithModel("m")
+	out, err := BulkCancelJobs(context.TODO(), client, &BulkJobsInput{Filter: filter, DryRun: true})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if len(out.Outcomes) != 3 || !out.Outcomes[0].Skipped {
+		t.Errorf("dry run outcomes not expected: %+v", out.Outcomes)
+	}
+	if len(filter.Paging.Filters) != 1 {
+		t.Errorf("provided filter should not be changed")
+	}
+}
+
+func TestBulkWaitForJobs(t *testing.T) {
+	client := &JobsClientFake{
+		ListJobsHistoryFunc: func(ctx context.Context, input *ListJobsHistoryInput) (*ListJobsHistoryOutput, error) {
+			return &ListJobsHistoryOutput{Jobs: []model.JobDetails{{JobIdentifier: "job-0"}, {JobIdentifier: "job-1"}}}, nil
+		},
+		WaitForJobCompletionFunc: func(ctx context.Context, input *WaitForJobCompletionInput, pollInterval time.Duration) (*GetJobDetailsOutput, error) {
+			return &GetJobDetailsOutput{Details: model.JobDetails{JobIdentifier: input.JobIdentifier, Status: JobStatusCompleted}}, nil
+		},
+	}
+	out, err := BulkWaitForJobs(context.TODO(), client, &BulkJobsInput{}, time.Millisecond)
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.Outcomes[1].Details.Status != JobStatusCompleted {
+		t.Errorf("wait outcome not expected: %+v", out.Outcomes[1])
+	}
+}
+
+func TestBulkGetJobResults(t *testing.T) {
+	client := &JobsClientFake{
+		ListJobsHistoryFunc: func(ctx context.Context, input *ListJobsHistoryInput) (*ListJobsHistoryOutput, error) {
+			return &ListJobsHistoryOutput{Jobs: []model.JobDetails{{JobIdentifier: "job-0"}, {JobIdentifier: "job-1"}}}, nil
+		},
+		GetJobResultsFunc: func(ctx context.Context, input *GetJobResultsInput) (*GetJobResultsOutput, error) {
+			return &GetJobResultsOutput{Results: model.JobResults{JobIdentifier: input.JobIdentifier}}, nil
+		},
+	}
+	out, err := BulkGetJobResults(context.TODO(), client, &BulkJobsInput{})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.Outcomes[1].Results.JobIdentifier != "job-1" {
+		t.Errorf("results outcome not expected: %+v", out.Outcomes[1])
+	}
+}
+
+func TestBulkJobsListError(t *testing.T) {
+	client := &JobsClientFake{
+		ListJobsHistoryFunc: func(ctx context.Context, input *ListJobsHistoryInput) (*ListJobsHistoryOutput, error) {
+			return nil, fmt.Errorf("nope")
+		},
+	}
+	if _, err := BulkCancelJobs(context.TODO(), client, &BulkJobsInput{}); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestBulkJobsContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	client := &JobsClientFake{
+		ListJobsHistoryFunc: func(ctx context.Context, input *ListJobsHistoryInput) (*ListJobsHistoryOutput, error) {
+			return &ListJobsHistoryOutput{Jobs: []model.JobDetails{{JobIdentifier: "job-0"}, {JobIdentifier: "job-1"}, {JobIdentifier: "job-2"}}}, nil
+		},
+		GetJobResultsFunc: func(ctx context.Context, input *GetJobResultsInput) (*GetJobResultsOutput, error) {
+			cancel()
+			return &GetJobResultsOutput{}, nil
+		},
+	}
+	out, err := BulkGetJobResults(ctx, client, &BulkJobsInput{})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if len(out.Failed()) != 2 {
+		t.Errorf("remaining jobs should fail once canceled: %+v", out.Outcomes)
+	}
+}
@@ -0,0 +1,184 @@
+package modzy
+
+import (
+	"context"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+// OpenJobAction is what CleanupOpenJobs did, or would do during a dry run, to an OPEN job
+type OpenJobAction string
+
+const (
+	OpenJobActionClosed   OpenJobAction = "CLOSED"
+	OpenJobActionCanceled OpenJobAction = "CANCELED"
+	// OpenJobActionSkipped is used for jobs that were listed but had activity within the threshold
+	OpenJobActionSkipped OpenJobAction = "SKIPPED"
+)
+
+type CleanupOpenJobsInput struct {
+	// OlderThan is how long a job must have been OPEN without activity before it is cleaned up
+	OlderThan time.Duration
+	// Filter can narrow the jobs further, such as by model, access key or date range.  It is merged into a copy where
+	// the status is replaced by OPEN and the end date is the earlier of its own and the OlderThan threshold.
+	Filter *ListJobsHistoryInput
+	// InputsComplete decides if an orphaned job should be closed so that its inputs are processed, instead of canceled.
+	// Defaults to closing jobs with at least one input.
+	InputsComplete func(ctx context.Context, job model.JobDetails) (bool, error)
+	// DryRun reports what would be done without closing or canceling anything
+	DryRun bool
+}
+
+// OpenJobCleanup reports what happened to a single OPEN job
+type OpenJobCleanup struct {
+	Job    model.JobDetails
+	Action OpenJobAction
+	// DryRun is true if the action was not actually taken
+	DryRun bool
+	Err    error
+}
+
+type CleanupOpenJobsOutput struct {
+	Jobs []OpenJobCleanup
+}
+
+// Touched returns the jobs that were closed or canceled, or would have been during a dry run
+func (o *CleanupOpenJobsOutput) Touched() []OpenJobCleanup {
+	touched := []OpenJobCleanup{}
+	for _, j := range o.Jobs {
+		if j.Action != OpenJobActionSkipped {
+			touched = append(touched, j)
+		}
+	}
+	return touched
+}
+
+func defaultInputsComplete(ctx context.Context, job model.JobDetails) (bool, error) {
+	return job.Total > 0, nil
+}
+
+// CleanupOpenJobs finds OPEN jobs, such as those left behind by an uploader that crashed, and either closes them if
+// their inputs are complete or cancels them.  A job is only touched if its latest activity is older than the threshold.
+// A failure for one job is recorded in the report and does not stop the others.
+func CleanupOpenJobs(ctx context.Context, client JobsClient, input *CleanupOpenJobsInput) (*CleanupOpenJobsOutput, error) {
+	if input.OlderThan <= 0 {
+		return nil, errors.New("OlderThan must be greater than zero")
+	}
+	inputsComplete := input.InputsComplete
+	if inputsComplete == nil {
+		inputsComplete = defaultInputsComplete
+	}
+
+	cutoff := time.Now().Add(-input.OlderThan)
+	filter, err := openJobsFilter(input.Filter, cutoff)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list open jobs")
+	}
+	if filter == nil {
+		return &CleanupOpenJobsOutput{Jobs: []OpenJobCleanup{}}, nil
+	}
+
+	// everything is listed first since closing and canceling moves jobs out of the filter while paging
+	jobs, err := NewListJobsHistoryPager(client, filter).ListAll(ctx, 0)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list open jobs")
+	}
+
+	out := &CleanupOpenJobsOutput{
+		Jobs: []OpenJobCleanup{},
+	}
+	for _, job := range jobs {
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+		cleanup := OpenJobCleanup{Job: job, Action: OpenJobActionSkipped, DryRun: input.DryRun}
+		if job.Status != JobStatusOpen || lastJobActivity(job).After(cutoff) {
+			out.Jobs = append(out.Jobs, cleanup)
+			continue
+		}
+
+		complete, err := inputsComplete(ctx, job)
+		if err != nil {
+			cleanup.Err = errors.WithMessage(err, "failed to decide if the inputs are complete")
+			out.Jobs = append(out.Jobs, cleanup)
+			continue
+		}
+		if complete {
+			cleanup.Action = OpenJobActionClosed
+			if !input.DryRun {
+				_, cleanup.Err = client.CloseJob(ctx, &CloseJobInput{JobIdentifier: job.JobIdentifier})
+			}
+		} else {
+			cleanup.Action = OpenJobActionCanceled
+			if !input.DryRun {
+				_, cleanup.Err = client.CancelJob(ctx, &CancelJobInput{JobIdentifier: job.JobIdentifier})
+			}
+		}
+		out.Jobs = append(out.Jobs, cleanup)
+	}
+	return out, nil
+}
+
+// openJobsFilter copies the caller's filter with the status replaced by OPEN and the end date capped at the cutoff.  It
+// returns nil if the caller's date range starts after the cutoff, since none of those jobs can be old enough.
+func openJobsFilter(callerFilter *ListJobsHistoryInput, cutoff time.Time) (*ListJobsHistoryInput, error) {
+	filter := &ListJobsHistoryInput{}
+	if callerFilter == nil {
+		return filter.WithStatuses(JobStatusOpen).WithDateRange(time.Time{}, cutoff), nil
+	}
+	if err := callerFilter.Validate(); err != nil {
+		return nil, err
+	}
+	filter.Paging = callerFilter.Paging
+	filter.Paging.Filters = []Filter{}
+	end := cutoff
+	for _, f := range callerFilter.Paging.Filters {
+		switch ListJobsHistoryFilterField(f.Field) {
+		case ListJobsHistoryFilterFieldStatus:
+			continue
+		case ListJobsHistoryFilterFieldStartDate:
+			// Validate made sure the dates are single, well formatted values
+			if start, _ := time.Parse(ListJobsHistoryDateFormat, f.Values[0]); start.After(cutoff) {
+				return nil, nil
+			}
+		case ListJobsHistoryFilterFieldEndDate:
+			if date, _ := time.Parse(ListJobsHistoryDateFormat, f.Values[0]); date.Before(end) {
+				end = date
+			}
+			continue
+		}
+		filter.Paging.Filters = append(filter.Paging.Filters, f)
+	}
+	return filter.WithStatuses(JobStatusOpen).WithDateRange(time.Time{}, end), nil
+}
+
+// RunCleanupOpenJobsEvery calls CleanupOpenJobs right away and then on every interval until the context is done.  Each
+// report is passed to the provided function, including any error, so that one failed run does not stop the schedule.
+func RunCleanupOpenJobsEvery(ctx context.Context, client JobsClient, input *CleanupOpenJobsInput, interval time.Duration, report func(*CleanupOpenJobsOutput, error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report(CleanupOpenJobs(ctx, client, input))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// lastJobActivity is the latest time recorded on the job, so that jobs still receiving inputs are left alone
+func lastJobActivity(job model.JobDetails) time.Time {
+	latest := job.CreatedAt.Time
+	for _, t := range []time.Time{job.SubmittedAt.Time, job.UpdatedAt.Time} {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
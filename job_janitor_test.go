@@ -0,0 +1,202 @@
+package modzy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+func TestCleanupOpenJobs(t *testing.T) {
+	old := model.ModzyTime{Time: time.Now().Add(-2 * time.Hour)}
+	recent := model.ModzyTime{Time: time.Now()}
+	closed, canceled := []string{}, []string{}
+	client := &JobsClientFake{
+		ListJobsHistoryFunc: func(ctx context.Context, input *ListJobsHistoryInput) (*ListJobsHistoryOutput, error) {
+			fields := []string{}
+			for _, f := range input.Paging.Filters {
+				fields = append(fields, fmt.Sprintf("%s=%s", f.Field, strings.Join(f.Values, ",")))
+			}
+			joined := strings.Join(fields, " ")
+			if !strings.Contains(joined, "status=OPEN") || !strings.Contains(joined, "endDate=") || !strings.Contains(joined, "model=m") {
+				t.Errorf("filters not expected: %s", joined)
+			}
+			return &ListJobsHistoryOutput{Jobs: []model.JobDetails{
+				{JobIdentifier: "complete", Status: JobStatusOpen, CreatedAt: old, Total: 2},
+				{JobIdentifier: "empty", Status: JobStatusOpen, CreatedAt: old},
+				{JobIdentifier: "active", Status: JobStatusOpen, CreatedAt: old, UpdatedAt: recent, Total: 1},
+				{JobIdentifier: "fails", Status: JobStatusOpen, CreatedAt: old},
+			}}, nil
+		},
+		CloseJobFunc: func(ctx context.Context, input *CloseJobInput) (*CloseJobOutput, error) {
+			closed = append(closed, input.JobIdentifier)
+			return &CloseJobOutput{}, nil
+		},
+		CancelJobFunc: func(ctx context.Context, input *CancelJobInput) (*CancelJobOutput, error) {
+			canceled = append(canceled, input.JobIdentifier)
+			if input.JobIdentifier == "fails" {
+				return nil, fmt.Errorf("nope")
+			}
+			return &CancelJobOutput{}, nil
+		},
+	}
+	filter := (&ListJobsHistoryInput{}).WithModel("m")
+	out, err := CleanupOpenJobs(context.TODO(), client, &CleanupOpenJobsInput{
+		OlderThan: time.Hour,
+		Filter:    filter,
+	})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if strings.Join(closed, ",") != "complete" {
+		t.Errorf("closed not expected: %v", closed)
+	}
+	if strings.Join(canceled, ",") != "empty,fails" {
+		t.Errorf("canceled not expected: %v", canceled)
+	}
+	if out.Jobs[2].Action != OpenJobActionSkipped {
+		t.Errorf("recently active job should be skipped: %+v", out.Jobs[2])
+	}
+	if out.Jobs[3].Err == nil {
+		t.Errorf("cancel error not reported")
+	}
+	if len(out.Touched()) != 3 {
+		t.Errorf("touched not expected: %+v", out.Touched())
+	}
+	if len(filter.Paging.Filters) != 1 {
+		t.Errorf("provided filter should not be changed")
+	}
+}
+
+func TestCleanupOpenJobsDryRunAndHook(t *testing.T) {
+	old := model.ModzyTime{Time: time.Now().Add(-2 * time.Hour)}
+	client := &JobsClientFake{
+		ListJobsHistoryFunc: func(ctx context.Context, input *ListJobsHistoryInput) (*ListJobsHistoryOutput, error) {
+			return &ListJobsHistoryOutput{Jobs: []model.JobDetails{
+				{JobIdentifier: "complete", Status: JobStatusOpen, CreatedAt: old, Total: 2},
+				{JobIdentifier: "empty", Status: JobStatusOpen, CreatedAt: old},
+				{JobIdentifier: "fails", Status: JobStatusOpen, CreatedAt: old},
+			}}, nil
+		},
+		CloseJobFunc: func(ctx context.Context, input *CloseJobInput) (*CloseJobOutput, error) {
+			t.Errorf("dry run should not close %s", input.JobIdentifier)
+			return &CloseJobOutput{}, nil
+		},
+		CancelJobFunc: func(ctx context.Context, input *CancelJobInput) (*CancelJobOutput, error) {
+			t.Errorf("dry run should not cancel %s", input.JobIdentifier)
+			return &CancelJobOutput{}, nil
+		},
+	}
+	out, err := CleanupOpenJobs(context.TODO(), client, &CleanupOpenJobsInput{
+		OlderThan: time.Hour,
+		Filter:    (&ListJobsHistoryInput{}).WithModel("m"),
+		InputsComplete: func(ctx context.Context, job model.JobDetails) (bool, error) {
+			if job.JobIdentifier == "fails" {
+				return false, fmt.Errorf("nope")
+			}
+			return true, nil
+		},
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.Jobs[1].Action != OpenJobActionClosed || !out.Jobs[1].DryRun {
+		t.Errorf("hook not used: %+v", out.Jobs[1])
+	}
+	if out.Jobs[2].Err == nil {
+		t.Errorf("hook error not reported")
+	}
+}
+
+func TestCleanupOpenJobsCallerDateRange(t *testing.T) {
+	start := time.Now().Add(-48 * time.Hour).UTC()
+	end := time.Now().Add(-24 * time.Hour).UTC()
+	listed := []string{}
+	client := &JobsClientFake{
+		ListJobsHistoryFunc: func(ctx context.Context, input *ListJobsHistoryInput) (*ListJobsHistoryOutput, error) {
+			if err := input.Validate(); err != nil {
+				t.Errorf("merged filter not valid: %v", err)
+			}
+			fields := []string{}
+			for _, f := range input.Paging.Filters {
+				fields = append(fields, fmt.Sprintf("%s=%s", f.Field, strings.Join(f.Values, ",")))
+			}
+			listed = append(listed, strings.Join(fields, " "))
+			return &ListJobsHistoryOutput{}, nil
+		},
+	}
+
+	// the caller's end date is earlier than the threshold and their status is replaced
+	_, err := CleanupOpenJobs(context.TODO(), client, &CleanupOpenJobsInput{
+		OlderThan: time.Hour,
+		Filter:    (&ListJobsHistoryInput{}).WithDateRange(start, end).WithStatuses(JobStatusCompleted),
+	})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	expected := fmt.Sprintf("startDate=%s status=OPEN endDate=%s", start.Format(ListJobsHistoryDateFormat), end.Format(ListJobsHistoryDateFormat))
+	if len(listed) != 1 || listed[0] != expected {
+		t.Errorf("filters not expected: %v", listed)
+	}
+
+	// the threshold is earlier than the caller's end date
+	listed = []string{}
+	_, err = CleanupOpenJobs(context.TODO(), client, &CleanupOpenJobsInput{
+		OlderThan: 36 * time.Hour,
+		Filter:    (&ListJobsHistoryInput{}).WithDateRange(start, end),
+	})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if len(listed) != 1 || strings.Contains(listed[0], end.Format(ListJobsHistoryDateFormat)) || strings.Count(listed[0], "endDate=") != 1 {
+		t.Errorf("filters not expected: %v", listed)
+	}
+
+	// no job started after the threshold can be old enough
+	listed = []string{}
+	out, err := CleanupOpenJobs(context.TODO(), client, &CleanupOpenJobsInput{
+		OlderThan: 72 * time.Hour,
+		Filter:    (&ListJobsHistoryInput{}).WithDateRange(start, end),
+	})
+	if err != nil || len(out.Jobs) != 0 || len(listed) != 0 {
+		t.Errorf("expected nothing to be listed, got %v %+v %v", err, out, listed)
+	}
+}
+
+func TestCleanupOpenJobsRequiresThreshold(t *testing.T) {
+	if _, err := CleanupOpenJobs(context.TODO(), &JobsClientFake{}, &CleanupOpenJobsInput{}); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestRunCleanupOpenJobsEvery(t *testing.T) {
+	client := &JobsClientFake{
+		ListJobsHistoryFunc: func(ctx context.Context, input *ListJobsHistoryInput) (*ListJobsHistoryOutput, error) {
+			return &ListJobsHistoryOutput{Jobs: []model.JobDetails{}}, nil
+		},
+	}
+	ctx, cancel := context.WithCancel(context.TODO())
+	runs := 0
+	err := RunCleanupOpenJobsEvery(ctx, client, &CleanupOpenJobsInput{
+		OlderThan: time.Hour,
+		Filter:    (&ListJobsHistoryInput{}).WithModel("m"),
+	}, time.Millisecond, func(out *CleanupOpenJobsOutput, err error) {
+		if err != nil {
+			t.Errorf("err not nil: %v", err)
+		}
+		runs++
+		if runs == 3 {
+			cancel()
+		}
+	})
+	if err != context.Canceled {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+	if runs != 3 {
+		t.Errorf("expected 3 runs, got %d", runs)
+	}
+}
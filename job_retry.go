@@ -0,0 +1,204 @@
+package modzy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+// defaultRetryPollInterval is used when waiting on retry jobs if no interval was provided
+const defaultRetryPollInterval = time.Second * 5
+
+// RetryableSubmission is an original job submission that individual inputs can be resubmitted from.
+//
+// Provided implementations are:
+//
+//	*SubmitJobTextInput
+//	*SubmitJobEmbeddedInput
+//	*SubmitJobFileInput
+//	*SubmitJobS3Input
+//
+// The data sources of the inputs are read again for each retry, so they must be re-readable (files or strings, not a
+// single-use io.Reader).
+type RetryableSubmission interface {
+	// InputKeys returns the sorted keys of all of the inputs of this submission
+	InputKeys() []string
+	// submitSubset submits a new job of the same type for the provided model and only the provided input keys
+	submitSubset(ctx context.Context, client Client, model model.ModelIdentifier, keys []string) (*SubmitJobOutput, error)
+}
+
+var (
+	_ RetryableSubmission = &SubmitJobTextInput{}
+	_ RetryableSubmission = &SubmitJobEmbeddedInput{}
+	_ RetryableSubmission = &SubmitJobFileInput{}
+	_ RetryableSubmission = &SubmitJobS3Input{}
+)
+
+func (i *SubmitJobTextInput) InputKeys() []string {
+	return sortedInputKeys(i.Inputs)
+}
+
+func (i *SubmitJobTextInput) submitSubset(ctx context.Context, client Client, model model.ModelIdentifier, keys []string) (*SubmitJobOutput, error) {
+	subset := *i
+	subset.ModelIdentifier = model.Identifier
+	subset.ModelVersion = model.Version
+	subset.Inputs = inputSubset(i.Inputs, keys)
+	return client.Jobs().SubmitJobText(ctx, &subset)
+}
+
+func (i *SubmitJobEmbeddedInput) InputKeys() []string {
+	return sortedInputKeys(i.Inputs)
+}
+
+func (i *SubmitJobEmbeddedInput) submitSubset(ctx context.Context, client Client, model model.ModelIdentifier, keys []string) (*SubmitJobOutput, error) {
+	subset := *i
+	subset.ModelIdentifier = model.Identifier
+	subset.ModelVersion = model.Version
+	subset.Inputs = inputSubset(i.Inputs, keys)
+	return client.Jobs().SubmitJobEmbedded(ctx, &subset)
+}
+
+func (i *SubmitJobFileInput) InputKeys() []string {
+	return sortedInputKeys(i.Inputs)
+}
+
+func (i *SubmitJobFileInput) submitSubset(ctx context.Context, client Client, model model.ModelIdentifier, keys []string) (*SubmitJobOutput, error) {
+	subset := *i
+	subset.ModelIdentifier = model.Identifier
+	subset.ModelVersion = model.Version
+	subset.Inputs = inputSubset(i.Inputs, keys)
+	return client.Jobs().SubmitJobFile(ctx, &subset)
+}
+
+func (i *SubmitJobS3Input) InputKeys() []string {
+	return sortedInputKeys(i.Inputs)
+}
+
+func (i *SubmitJobS3Input) submitSubset(ctx context.Context, client Client, model model.ModelIdentifier, keys []string) (*SubmitJobOutput, error) {
+	subset := *i
+	subset.ModelIdentifier = model.Identifier
+	subset.ModelVersion = model.Version
+	subset.Inputs = inputSubset(i.Inputs, keys)
+	return client.Jobs().SubmitJobS3(ctx, &subset)
+}
+
+func sortedInputKeys[T any](inputs map[string]T) []string {
+	keys := make([]string, 0, len(inputs))
+	for k := range inputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func inputSubset[T any](inputs map[string]T, keys []string) map[string]T {
+	subset := make(map[string]T, len(keys))
+	for _, k := range keys {
+		subset[k] = inputs[k]
+	}
+	return subset
+}
+
+func (j *standardJobActions) RetryFailedInputs(ctx context.Context, input *RetryFailedInputsInput) (*RetryFailedInputsOutput, error) {
+	if input.Submission == nil {
+		return nil, fmt.Errorf("the original submission is required to retry failed inputs")
+	}
+	maxAttempts := input.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	pollInterval := input.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultRetryPollInterval
+	}
+
+	details, err := j.GetDetails(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read the details of the original job")
+	}
+	switch details.Details.Status {
+	case JobStatusCompleted, JobStatusCanceled, JobStatusTimedOut:
+	default:
+		return nil, fmt.Errorf("the original job is %s; only the inputs of a finished job can be retried", details.Details.Status)
+	}
+	original, err := j.GetResults(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read the results of the original job")
+	}
+	jobModel := model.ModelIdentifier{
+		Identifier: details.Details.Model.Identifier,
+		Version:    details.Details.Model.Version,
+	}
+
+	merged := copyJobResults(original.Results)
+	out := &RetryFailedInputsOutput{
+		Results:   merged,
+		RetryJobs: []JobActions{},
+	}
+
+	// only inputs that exist in the submission can be retried
+	known := map[string]bool{}
+	for _, k := range input.Submission.InputKeys() {
+		known[k] = true
+	}
+	toRetry := retryableKeys(merged.Failures, known)
+
+	for attempt := 0; attempt < maxAttempts && len(toRetry) > 0; attempt++ {
+		submitted, err := input.Submission.submitSubset(ctx, j.client, jobModel, toRetry)
+		if err != nil {
+			return out, errors.WithMessagef(err, "failed to submit retry attempt %d", attempt+1)
+		}
+		out.RetryJobs = append(out.RetryJobs, submitted.JobActions)
+
+		if _, err := submitted.WaitForCompletion(ctx, pollInterval); err != nil {
+			return out, errors.WithMessagef(err, "failed waiting for retry attempt %d", attempt+1)
+		}
+		retried, err := submitted.GetResults(ctx)
+		if err != nil {
+			return out, errors.WithMessagef(err, "failed to read the results of retry attempt %d", attempt+1)
+		}
+
+		for k, result := range retried.Results.Results {
+			merged.Results[k] = result
+			delete(merged.Failures, k)
+		}
+		for k, result := range retried.Results.Failures {
+			merged.Failures[k] = result
+		}
+		merged.Completed = len(merged.Results)
+		merged.Failed = len(merged.Failures)
+		out.Results = merged
+
+		toRetry = retryableKeys(merged.Failures, known)
+	}
+
+	return out, nil
+}
+
+func retryableKeys(failures map[string]model.JobResult, known map[string]bool) []string {
+	keys := []string{}
+	for k := range failures {
+		if known[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func copyJobResults(results model.JobResults) model.JobResults {
+	copied := results
+	copied.Results = make(map[string]model.JobResult, len(results.Results))
+	for k, v := range results.Results {
+		copied.Results[k] = v
+	}
+	copied.Failures = make(map[string]model.JobResult, len(results.Failures))
+	for k, v := range results.Failures {
+		copied.Failures[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,225 @@
+package modzy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+func TestRetryableSubmissionInputKeys(t *testing.T) {
+	text := &SubmitJobTextInput{Inputs: map[string]TextInputItem{"b": {}, "a": {}}}
+	if strings.Join(text.InputKeys(), ",") != "a,b" {
+		t.Errorf("text keys not sorted: %v", text.InputKeys())
+	}
+	embedded := &SubmitJobEmbeddedInput{Inputs: map[string]EmbeddedInputItem{"c": {}}}
+	if strings.Join(embedded.InputKeys(), ",") != "c" {
+		t.Errorf("embedded keys not expected: %v", embedded.InputKeys())
+	}
+	file := &SubmitJobFileInput{Inputs: map[string]FileInputItem{"d": {}}}
+	if strings.Join(file.InputKeys(), ",") != "d" {
+		t.Errorf("file keys not expected: %v", file.InputKeys())
+	}
+	s3 := &SubmitJobS3Input{Inputs: map[string]S3InputItem{"e": {}}}
+	if strings.Join(s3.InputKeys(), ",") != "e" {
+		t.Errorf("s3 keys not expected: %v", s3.InputKeys())
+	}
+}
+
+func TestJobActionsRetryFailedInputs(t *testing.T) {
+	original := model.JobResults{
+		JobIdentifier: "original",
+		Completed:     1,
+		Failed:        3,
+		Results:       map[string]model.JobResult{"ok": {Status: "SUCCESSFUL"}},
+		Failures: map[string]model.JobResult{
+			"flaky":   {Status: "FAILED"},
+			"broken":  {Status: "FAILED"},
+			"unknown": {Status: "FAILED"},
+		},
+	}
+	retries := []model.JobResults{
+		{
+			Results:  map[string]model.JobResult{"flaky": {Status: "SUCCESSFUL"}},
+			Failures: map[string]model.JobResult{"broken": {Status: "FAILED", Error: "attempt 1"}},
+		},
+		{
+			Failures: map[string]model.JobResult{"broken": {Status: "FAILED", Error: "attempt 2"}},
+		},
+	}
+	submitted := []*SubmitJobTextInput{}
+	client := &ClientFake{}
+	client.JobsFunc = func() JobsClient {
+		return &JobsClientFake{
+			GetJobDetailsFunc: func(ctx context.Context, input *GetJobDetailsInput) (*GetJobDetailsOutput, error) {
+				return &GetJobDetailsOutput{
+					Details: model.JobDetails{
+						JobIdentifier: input.JobIdentifier,
+						Status:        JobStatusCompleted,
+						Model:         model.ModelNamedIdentifier{Identifier: "modelID", Version: "1.0.0"},
+					},
+				}, nil
+			},
+			SubmitJobTextFunc: func(ctx context.Context, input *SubmitJobTextInput) (*SubmitJobTextOutput, error) {
+				submitted = append(submitted, input)
+				return &SubmitJobTextOutput{
+					JobActions: NewJobActions(client, fmt.Sprintf("retry-%d", len(submitted)-1)),
+				}, nil
+			},
+			WaitForJobCompletionFunc: func(ctx context.Context, input *WaitForJobCompletionInput, pollInterval time.Duration) (*GetJobDetailsOutput, error) {
+				if pollInterval != defaultRetryPollInterval {
+					t.Errorf("default poll interval not used: %v", pollInterval)
+				}
+				return &GetJobDetailsOutput{}, nil
+			},
+			GetJobResultsFunc: func(ctx context.Context, input *GetJobResultsInput) (*GetJobResultsOutput, error) {
+				// the original job has failed inputs, and retry jobs are answered with the retries in order
+				if input.JobIdentifier == "original" {
+					return &GetJobResultsOutput{Results: original}, nil
+				}
+				var i int
+				fmt.Sscanf(input.JobIdentifier, "retry-%d", &i)
+				return &GetJobResultsOutput{Results: retries[i]}, nil
+			},
+		}
+	}
+
+	out, err := NewJobActions(client, "original").RetryFailedInputs(context.TODO(), &RetryFailedInputsInput{
+		Submission: &SubmitJobTextInput{
+			Explain: true,
+			Inputs: map[string]TextInputItem{
+				"ok":     {"input.txt": "ok"},
+				"flaky":  {"input.txt": "flaky"},
+				"broken": {"input.txt": "broken"},
+			},
+		},
+		MaxAttempts: 2,
+	})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+
+	if len(submitted) != 2 {
+		t.Fatalf("expected 2 retry jobs, got %d", len(submitted))
+	}
+	if strings.Join(submitted[0].InputKeys(), ",") != "broken,flaky" {
+		t.Errorf("first retry inputs not expected: %v", submitted[0].InputKeys())
+	}
+	if strings.Join(submitted[1].InputKeys(), ",") != "broken" {
+		t.Errorf("second retry inputs not expected: %v", submitted[1].InputKeys())
+	}
+	if submitted[0].ModelIdentifier != "modelID" || submitted[0].ModelVersion != "1.0.0" || !submitted[0].Explain {
+		t.Errorf("retry did not keep the submission settings: %+v", submitted[0])
+	}
+
+	if len(out.RetryJobs) != 2 {
+		t.Errorf("retry jobs not reported")
+	}
+	if out.Results.Completed != 2 || out.Results.Failed != 2 {
+		t.Errorf("counts not updated: %d/%d", out.Results.Completed, out.Results.Failed)
+	}
+	if _, has := out.Results.Results["flaky"]; !has {
+		t.Errorf("retried result not merged")
+	}
+	if out.Results.Failures["broken"].Error != "attempt 2" {
+		t.Errorf("latest failure not kept: %+v", out.Results.Failures["broken"])
+	}
+	if _, has := original.Results["flaky"]; has {
+		t.Errorf("original results should not be changed")
+	}
+}
+
+func TestJobActionsRetryFailedInputsNothingFailed(t *testing.T) {
+	client := &ClientFake{}
+	client.JobsFunc = func() JobsClient {
+		return &JobsClientFake{
+			GetJobDetailsFunc: func(ctx context.Context, input *GetJobDetailsInput) (*GetJobDetailsOutput, error) {
+				return &GetJobDetailsOutput{Details: model.JobDetails{Status: JobStatusCompleted}}, nil
+			},
+			GetJobResultsFunc: func(ctx context.Context, input *GetJobResultsInput) (*GetJobResultsOutput, error) {
+				return &GetJobResultsOutput{Results: model.JobResults{Results: map[string]model.JobResult{"a": {}}}}, nil
+			},
+			SubmitJobTextFunc: func(ctx context.Context, input *SubmitJobTextInput) (*SubmitJobTextOutput, error) {
+				t.Errorf("nothing should have been submitted")
+				return nil, fmt.Errorf("nope")
+			},
+		}
+	}
+	out, err := NewJobActions(client, "original").RetryFailedInputs(context.TODO(), &RetryFailedInputsInput{
+		Submission: &SubmitJobTextInput{Inputs: map[string]TextInputItem{"a": {}}},
+	})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if len(out.RetryJobs) != 0 {
+		t.Errorf("nothing should have been submitted")
+	}
+}
+
+func TestJobActionsRetryFailedInputsErrors(t *testing.T) {
+	_, err := NewJobActions(&ClientFake{}, "original").RetryFailedInputs(context.TODO(), &RetryFailedInputsInput{})
+	if err == nil || !strings.Contains(err.Error(), "original submission is required") {
+		t.Errorf("error not expected: %v", err)
+	}
+
+	failingClient := &ClientFake{
+		JobsFunc: func() JobsClient {
+			return &JobsClientFake{
+				GetJobDetailsFunc: func(ctx context.Context, input *GetJobDetailsInput) (*GetJobDetailsOutput, error) {
+					return nil, fmt.Errorf("nope")
+				},
+			}
+		},
+	}
+	_, err = NewJobActions(failingClient, "original").RetryFailedInputs(context.TODO(), &RetryFailedInputsInput{
+		Submission: &SubmitJobTextInput{},
+	})
+	if err == nil || !strings.Contains(err.Error(), "failed to read the details of the original job") {
+		t.Errorf("error not expected: %v", err)
+	}
+
+	runningClient := &ClientFake{
+		JobsFunc: func() JobsClient {
+			return &JobsClientFake{
+				GetJobDetailsFunc: func(ctx context.Context, input *GetJobDetailsInput) (*GetJobDetailsOutput, error) {
+					return &GetJobDetailsOutput{Details: model.JobDetails{Status: JobStatusInProgress}}, nil
+				},
+			}
+		},
+	}
+	_, err = NewJobActions(runningClient, "original").RetryFailedInputs(context.TODO(), &RetryFailedInputsInput{
+		Submission: &SubmitJobTextInput{},
+	})
+	if err == nil || !strings.Contains(err.Error(), "the original job is IN_PROGRESS") {
+		t.Errorf("error not expected: %v", err)
+	}
+}
+
+func TestJobActionsRetryFailedInputsSubmitError(t *testing.T) {
+	client := &ClientFake{}
+	client.JobsFunc = func() JobsClient {
+		return &JobsClientFake{
+			GetJobDetailsFunc: func(ctx context.Context, input *GetJobDetailsInput) (*GetJobDetailsOutput, error) {
+				return &GetJobDetailsOutput{Details: model.JobDetails{Status: JobStatusCompleted}}, nil
+			},
+			GetJobResultsFunc: func(ctx context.Context, input *GetJobResultsInput) (*GetJobResultsOutput, error) {
+				return &GetJobResultsOutput{Results: model.JobResults{Failures: map[string]model.JobResult{"a": {}}}}, nil
+			},
+			SubmitJobEmbeddedFunc: func(ctx context.Context, input *SubmitJobEmbeddedInput) (*SubmitJobEmbeddedOutput, error) {
+				return nil, fmt.Errorf("nope")
+			},
+		}
+	}
+	out, err := NewJobActions(client, "original").RetryFailedInputs(context.TODO(), &RetryFailedInputsInput{
+		Submission: &SubmitJobEmbeddedInput{Inputs: map[string]EmbeddedInputItem{"a": {}}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "failed to submit retry attempt 1") {
+		t.Errorf("error not expected: %v", err)
+	}
+	if out == nil || len(out.Results.Failures) != 1 {
+		t.Errorf("original results should be returned with the error")
+	}
+}
@@ -6,6 +6,10 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/docker/go-units"
@@ -24,6 +28,8 @@ type JobsClient interface {
 	SubmitJobEmbedded(ctx context.Context, input *SubmitJobEmbeddedInput) (*SubmitJobEmbeddedOutput, error)
 	// SubmitJobFile will submit a new job and post the provided byte data as multiple chunks of data based on your account's maximum chunk size.
 	SubmitJobFile(ctx context.Context, input *SubmitJobFileInput) (*SubmitJobFileOutput, error)
+	// OpenJob will create a new job that accepts inputs one at a time until it is closed.
+	OpenJob(ctx context.Context, input *OpenJobInput) (*OpenJobOutput, error)
 	// SubmitJobS3 submits a job that reads inputs from an S3 bucket
 	SubmitJobS3(ctx context.Context, input *SubmitJobS3Input) (*SubmitJobS3Output, error)
 	// SubmitJobJDBC submits a job that reads inputs from a Postgres database through a provided query
@@ -32,10 +38,16 @@ type JobsClient interface {
 	WaitForJobCompletion(ctx context.Context, input *WaitForJobCompletionInput, pollInterval time.Duration) (*GetJobDetailsOutput, error)
 	// CancelJob will cancel a job
 	CancelJob(ctx context.Context, input *CancelJobInput) (*CancelJobOutput, error)
+	// CloseJob will close an OPEN job so that the inputs already added are processed
+	CloseJob(ctx context.Context, input *CloseJobInput) (*CloseJobOutput, error)
 	// GetJobResults will get the results for a job
 	GetJobResults(ctx context.Context, input *GetJobResultsInput) (*GetJobResultsOutput, error)
 	// GetJobFeatures will read settings related to submitting jobs such as chunk size
 	GetJobFeatures(ctx context.Context) (*GetJobFeaturesOutput, error)
+	// GetJobOutput will stream a single output file of a single input.  This works for non-json outputs such as images.
+	GetJobOutput(ctx context.Context, input *GetJobOutputInput) (*GetJobOutputOutput, error)
+	// DownloadJobOutputs will write every output of every successful input of a job into a directory tree.
+	DownloadJobOutputs(ctx context.Context, input *DownloadJobOutputsInput) (*DownloadJobOutputsOutput, error)
 }
 
 type standardJobsClient struct {
@@ -58,6 +70,9 @@ func (c *standardJobsClient) GetJobDetails(ctx context.Context, input *GetJobDet
 }
 
 func (c *standardJobsClient) ListJobsHistory(ctx context.Context, input *ListJobsHistoryInput) (*ListJobsHistoryOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
 	input.Paging = input.Paging.withDefaults()
 
 	var items []model.JobDetails
@@ -166,6 +181,37 @@ func (c *standardJobsClient) SubmitJobEmbedded(ctx context.Context, input *Submi
 }
 
 func (c *standardJobsClient) SubmitJobFile(ctx context.Context, input *SubmitJobFileInput) (*SubmitJobFileOutput, error) {
+	open, err := c.OpenJob(ctx, &OpenJobInput{
+		ModelIdentifier: input.ModelIdentifier,
+		ModelVersion:    input.ModelVersion,
+		Explain:         input.Explain,
+		Timeout:         input.Timeout,
+		ChunkSize:       input.ChunkSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, inputKey := range sortedInputKeys(input.Inputs) {
+		if err := open.AddInput(ctx, inputKey, input.Inputs[inputKey]); err != nil {
+			// uploading the inputs failed, cancel the job
+			_, _ = open.Abort(ctx)
+			return nil, errors.WithMessage(err, "job canceled due to failure to upload data")
+		}
+	}
+
+	// close the job since everything is posted
+	if _, err := open.Close(ctx); err != nil {
+		return nil, errors.WithMessage(err, "failed to close open job after successfully uploading inputs")
+	}
+
+	return &SubmitJobFileOutput{
+		Response:   open.Response,
+		JobActions: open.OpenJobActions,
+	}, nil
+}
+
+func (c *standardJobsClient) OpenJob(ctx context.Context, input *OpenJobInput) (*OpenJobOutput, error) {
 	chunkSize, err := c.getMaxChunkSize(ctx, input.ChunkSize)
 	if err != nil {
 		return nil, errors.WithMessage(err, "Failed to get max chunk size")
@@ -183,24 +229,10 @@ func (c *standardJobsClient) SubmitJobFile(ctx context.Context, input *SubmitJob
 	if _, err := c.baseClient.requestor.Post(ctx, "/api/jobs", noInputJob, &response); err != nil {
 		return nil, errors.WithMessage(err, "failed to post open job before posting input chunks")
 	}
-	jobActions := NewJobActions(c.baseClient, response.JobIdentifier)
-
-	chunkErr := c.postInputsAsChunks(ctx, response.JobIdentifier, chunkSize, input.Inputs)
-	if chunkErr != nil {
-		// uploading the inputs failed, close the job
-		_, _ = jobActions.Cancel(ctx)
-		return nil, errors.WithMessage(chunkErr, "job canceled due to failure to upload data")
-	}
 
-	// close the job since everything is posted
-	closeURL := fmt.Sprintf("/api/jobs/%s/close", response.JobIdentifier)
-	if _, err := c.baseClient.requestor.Post(ctx, closeURL, nil, nil); err != nil {
-		return nil, errors.WithMessage(err, "failed to close open job after successfully uploading inputs")
-	}
-
-	return &SubmitJobFileOutput{
-		Response:   response,
-		JobActions: jobActions,
+	return &OpenJobOutput{
+		Response:       response,
+		OpenJobActions: newOpenJobActions(c.baseClient, c, response.JobIdentifier, chunkSize),
 	}, nil
 }
 
@@ -223,38 +255,36 @@ func (c *standardJobsClient) getMaxChunkSize(ctx context.Context, defaultChunkSi
 	return chunkSize, nil
 }
 
-func (c *standardJobsClient) postInputsAsChunks(ctx context.Context, jobID string, chunkSize int64, inputs map[string]FileInputItem) error {
-	// go through each input and submit the data in chunks as necessary
-	for k, v := range inputs {
-		for innerK, innerV := range v {
-			dataReader, err := innerV()
-			if err != nil {
-				return errors.WithMessagef(err, "failed to get data reader for item %s/%s", k, innerK)
-			}
+// postInputChunks posts each item of a single input, splitting the data into chunks as necessary
+func (c *standardJobsClient) postInputChunks(ctx context.Context, jobID string, chunkSize int64, inputKey string, items FileInputItem) error {
+	for _, itemName := range sortedInputKeys(items) {
+		dataReader, err := items[itemName]()
+		if err != nil {
+			return errors.WithMessagef(err, "failed to get data reader for item %s/%s", inputKey, itemName)
+		}
 
-			// post as many chunks as necessary
-			buf, err := ioutil.ReadAll(dataReader)
-			if err != nil {
-				return errors.WithMessage(err, "failed reading a chunk of data")
+		// post as many chunks as necessary
+		buf, err := ioutil.ReadAll(dataReader)
+		if err != nil {
+			return errors.WithMessage(err, "failed reading a chunk of data")
+		}
+		start := 0
+		end := 0
+		for {
+			end = start + int(chunkSize)
+			if end > len(buf) {
+				end = len(buf)
 			}
-			start := 0
-			end := 0
-			for {
-				end = start + int(chunkSize)
-				if end > len(buf) {
-					end = len(buf)
-				}
-				if start == end {
-					break
-				}
-				chunk := buf[start:end]
-				chunkURL := fmt.Sprintf("/api/jobs/%s/%s/%s", jobID, k, innerK)
-				chunkReader := bytes.NewReader(chunk)
-				if _, err := c.baseClient.requestor.PostMultipart(ctx, chunkURL, map[string]io.Reader{"input": chunkReader}, nil); err != nil {
-					return errors.WithMessage(err, "failed to post a chunk of data")
-				}
-				start = end
+			if start == end {
+				break
+			}
+			chunk := buf[start:end]
+			chunkURL := fmt.Sprintf("/api/jobs/%s/%s/%s", jobID, inputKey, itemName)
+			chunkReader := bytes.NewReader(chunk)
+			if _, err := c.baseClient.requestor.PostMultipart(ctx, chunkURL, map[string]io.Reader{"input": chunkReader}, nil); err != nil {
+				return errors.WithMessage(err, "failed to post a chunk of data")
 			}
+			start = end
 		}
 	}
 	return nil
@@ -384,6 +414,21 @@ func (c *standardJobsClient) CancelJob(ctx context.Context, input *CancelJobInpu
 	}, nil
 }
 
+func (c *standardJobsClient) CloseJob(ctx context.Context, input *CloseJobInput) (*CloseJobOutput, error) {
+	var response model.JobDetails
+
+	url := fmt.Sprintf("/api/jobs/%s/close", input.JobIdentifier)
+	_, err := c.baseClient.requestor.Post(ctx, url, nil, &response)
+	// the job may be closed without any details in the response
+	if err != nil && errors.Cause(err) != io.EOF {
+		return nil, err
+	}
+
+	return &CloseJobOutput{
+		Details: response,
+	}, nil
+}
+
 func (c *standardJobsClient) GetJobResults(ctx context.Context, input *GetJobResultsInput) (*GetJobResultsOutput, error) {
 	var response model.JobResults
 
@@ -411,3 +456,102 @@ func (c *standardJobsClient) GetJobFeatures(ctx context.Context) (*GetJobFeature
 		Features: response,
 	}, nil
 }
+
+func (c *standardJobsClient) GetJobOutput(ctx context.Context, input *GetJobOutputInput) (*GetJobOutputOutput, error) {
+	path := fmt.Sprintf("/api/results/%s/%s/%s",
+		input.JobIdentifier,
+		url.PathEscape(input.InputKey),
+		url.PathEscape(input.OutputName),
+	)
+	resp, err := c.baseClient.requestor.GetStream(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetJobOutputOutput{
+		Output:        resp.Body,
+		ContentType:   resp.Header.Get("Content-Type"),
+		ContentLength: resp.ContentLength,
+	}, nil
+}
+
+func (c *standardJobsClient) DownloadJobOutputs(ctx context.Context, input *DownloadJobOutputsInput) (*DownloadJobOutputsOutput, error) {
+	results, err := c.GetJobResults(ctx, &GetJobResultsInput{JobIdentifier: input.JobIdentifier})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read job results to find the outputs")
+	}
+
+	wantedOutputs := map[string]bool{}
+	for _, o := range input.OutputNames {
+		wantedOutputs[o] = true
+	}
+
+	// decide on everything to download up front so that bad names fail before anything is written
+	toDownload := []DownloadedJobOutput{}
+	for inputKey, result := range results.Results.Results {
+		for _, outputName := range result.OutputNames() {
+			if len(wantedOutputs) > 0 && !wantedOutputs[outputName] {
+				continue
+			}
+			for _, name := range []string{inputKey, outputName} {
+				if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+					return nil, fmt.Errorf("refusing to write output %s/%s; the name is not safe to use as a file path", inputKey, outputName)
+				}
+			}
+			toDownload = append(toDownload, DownloadedJobOutput{
+				InputKey:   inputKey,
+				OutputName: outputName,
+				Path:       filepath.Join(input.Directory, inputKey, outputName),
+			})
+		}
+	}
+	sort.Slice(toDownload, func(i, j int) bool {
+		return toDownload[i].Path < toDownload[j].Path
+	})
+
+	err = forEachConcurrently(ctx, len(toDownload), input.Concurrency, func(ctx context.Context, i int) error {
+		written, err := c.downloadJobOutput(ctx, input.JobIdentifier, toDownload[i])
+		if err != nil {
+			return err
+		}
+		toDownload[i].Bytes = written
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &DownloadJobOutputsOutput{
+		Files: toDownload,
+	}, nil
+}
+
+func (c *standardJobsClient) downloadJobOutput(ctx context.Context, jobIdentifier string, file DownloadedJobOutput) (int64, error) {
+	out, err := c.GetJobOutput(ctx, &GetJobOutputInput{
+		JobIdentifier: jobIdentifier,
+		InputKey:      file.InputKey,
+		OutputName:    file.OutputName,
+	})
+	if err != nil {
+		return 0, errors.WithMessagef(err, "failed to get output %s/%s", file.InputKey, file.OutputName)
+	}
+	defer out.Output.Close()
+
+	if err := AppFs.MkdirAll(filepath.Dir(file.Path), 0755); err != nil {
+		return 0, errors.WithMessagef(err, "failed to create directory for %s", file.Path)
+	}
+	f, err := AppFs.Create(file.Path)
+	if err != nil {
+		return 0, errors.WithMessagef(err, "failed to create file %s", file.Path)
+	}
+
+	written, err := io.Copy(f, out.Output)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// do not leave a partial file behind that looks like a finished download
+		_ = AppFs.Remove(file.Path)
+		return written, errors.WithMessagef(err, "failed to write file %s", file.Path)
+	}
+	return written, nil
+}
@@ -12,12 +12,16 @@ type JobsClientFake struct {
 	SubmitJobTextFunc        func(ctx context.Context, input *SubmitJobTextInput) (*SubmitJobTextOutput, error)
 	SubmitJobEmbeddedFunc    func(ctx context.Context, input *SubmitJobEmbeddedInput) (*SubmitJobEmbeddedOutput, error)
 	SubmitJobFileFunc        func(ctx context.Context, input *SubmitJobFileInput) (*SubmitJobFileOutput, error)
+	OpenJobFunc              func(ctx context.Context, input *OpenJobInput) (*OpenJobOutput, error)
 	SubmitJobS3Func          func(ctx context.Context, input *SubmitJobS3Input) (*SubmitJobS3Output, error)
 	SubmitJobJDBCFunc        func(ctx context.Context, input *SubmitJobJDBCInput) (*SubmitJobJDBCOutput, error)
 	WaitForJobCompletionFunc func(ctx context.Context, input *WaitForJobCompletionInput, pollInterval time.Duration) (*GetJobDetailsOutput, error)
 	CancelJobFunc            func(ctx context.Context, input *CancelJobInput) (*CancelJobOutput, error)
+	CloseJobFunc             func(ctx context.Context, input *CloseJobInput) (*CloseJobOutput, error)
 	GetJobResultsFunc        func(ctx context.Context, input *GetJobResultsInput) (*GetJobResultsOutput, error)
 	GetJobFeaturesFunc       func(ctx context.Context) (*GetJobFeaturesOutput, error)
+	GetJobOutputFunc         func(ctx context.Context, input *GetJobOutputInput) (*GetJobOutputOutput, error)
+	DownloadJobOutputsFunc   func(ctx context.Context, input *DownloadJobOutputsInput) (*DownloadJobOutputsOutput, error)
 }
 
 var _ JobsClient = &JobsClientFake{}
@@ -42,6 +46,10 @@ func (c *JobsClientFake) SubmitJobFile(ctx context.Context, input *SubmitJobFile
 	return c.SubmitJobFileFunc(ctx, input)
 }
 
+func (c *JobsClientFake) OpenJob(ctx context.Context, input *OpenJobInput) (*OpenJobOutput, error) {
+	return c.OpenJobFunc(ctx, input)
+}
+
 func (c *JobsClientFake) SubmitJobS3(ctx context.Context, input *SubmitJobS3Input) (*SubmitJobS3Output, error) {
 	return c.SubmitJobS3Func(ctx, input)
 }
@@ -58,6 +66,10 @@ func (c *JobsClientFake) CancelJob(ctx context.Context, input *CancelJobInput) (
 	return c.CancelJobFunc(ctx, input)
 }
 
+func (c *JobsClientFake) CloseJob(ctx context.Context, input *CloseJobInput) (*CloseJobOutput, error) {
+	return c.CloseJobFunc(ctx, input)
+}
+
 func (c *JobsClientFake) GetJobResults(ctx context.Context, input *GetJobResultsInput) (*GetJobResultsOutput, error) {
 	return c.GetJobResultsFunc(ctx, input)
 }
@@ -65,3 +77,11 @@ func (c *JobsClientFake) GetJobResults(ctx context.Context, input *GetJobResults
 func (c *JobsClientFake) GetJobFeatures(ctx context.Context) (*GetJobFeaturesOutput, error) {
 	return c.GetJobFeaturesFunc(ctx)
 }
+
+func (c *JobsClientFake) GetJobOutput(ctx context.Context, input *GetJobOutputInput) (*GetJobOutputOutput, error) {
+	return c.GetJobOutputFunc(ctx, input)
+}
+
+func (c *JobsClientFake) DownloadJobOutputs(ctx context.Context, input *DownloadJobOutputsInput) (*DownloadJobOutputsOutput, error) {
+	return c.DownloadJobOutputsFunc(ctx, input)
+}
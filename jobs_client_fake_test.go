@@ -62,6 +62,16 @@ func TestJobsClientFake(t *testing.T) {
 			}
 			return nil, nil
 		},
+		OpenJobFunc: func(ctx context.Context, input *OpenJobInput) (*OpenJobOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
 		SubmitJobS3Func: func(ctx context.Context, input *SubmitJobS3Input) (*SubmitJobS3Output, error) {
 			calls++
 			if ctx != expectedCtx {
@@ -105,6 +115,16 @@ func TestJobsClientFake(t *testing.T) {
 			}
 			return nil, nil
 		},
+		CloseJobFunc: func(ctx context.Context, input *CloseJobInput) (*CloseJobOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
 		GetJobResultsFunc: func(ctx context.Context, input *GetJobResultsInput) (*GetJobResultsOutput, error) {
 			calls++
 			if ctx != expectedCtx {
@@ -122,6 +142,26 @@ func TestJobsClientFake(t *testing.T) {
 			}
 			return nil, nil
 		},
+		GetJobOutputFunc: func(ctx context.Context, input *GetJobOutputInput) (*GetJobOutputOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		DownloadJobOutputsFunc: func(ctx context.Context, input *DownloadJobOutputsInput) (*DownloadJobOutputsOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
 	}
 
 	fake.GetJobDetails(expectedCtx, &GetJobDetailsInput{})
@@ -129,14 +169,18 @@ func TestJobsClientFake(t *testing.T) {
 	fake.SubmitJobText(expectedCtx, &SubmitJobTextInput{})
 	fake.SubmitJobEmbedded(expectedCtx, &SubmitJobEmbeddedInput{})
 	fake.SubmitJobFile(expectedCtx, &SubmitJobFileInput{})
+	fake.OpenJob(expectedCtx, &OpenJobInput{})
 	fake.SubmitJobS3(expectedCtx, &SubmitJobS3Input{})
 	fake.SubmitJobJDBC(expectedCtx, &SubmitJobJDBCInput{})
 	fake.WaitForJobCompletion(expectedCtx, &WaitForJobCompletionInput{}, time.Second*12)
 	fake.CancelJob(expectedCtx, &CancelJobInput{})
+	fake.CloseJob(expectedCtx, &CloseJobInput{})
 	fake.GetJobResults(expectedCtx, &GetJobResultsInput{})
 	fake.GetJobFeatures(expectedCtx)
+	fake.GetJobOutput(expectedCtx, &GetJobOutputInput{})
+	fake.DownloadJobOutputs(expectedCtx, &DownloadJobOutputsInput{})
 
-	if calls != 11 {
+	if calls != 15 {
 		t.Errorf("Did not call all of the funcs: %d", calls)
 	}
 }
@@ -10,6 +10,9 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
 )
 
 func TestGetJobDetailsHTTPError(t *testing.T) {
@@ -85,6 +88,18 @@ func TestListJobsHistory(t *testing.T) {
 	}
 }
 
+func TestListJobsHistoryInvalidFilter(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request should not be sent")
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	_, err := client.Jobs().ListJobsHistory(context.TODO(), (&ListJobsHistoryInput{}).WithStatuses("DONE"))
+	if errors.Cause(err) != ErrInvalidFilter {
+		t.Errorf("expected ErrInvalidFilter, got %v", err)
+	}
+}
+
 func TestSubmitJobTextHTTPError(t *testing.T) {
 	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(500)
@@ -693,6 +708,50 @@ func TestCancelJob(t *testing.T) {
 	}
 }
 
+func TestCloseJobHTTPError(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	_, err := client.Jobs().CloseJob(context.TODO(), &CloseJobInput{JobIdentifier: "inputID"})
+	if err == nil {
+		t.Errorf("Expected error")
+	}
+}
+
+func TestCloseJobEmptyResponse(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	_, err := client.Jobs().CloseJob(context.TODO(), &CloseJobInput{JobIdentifier: "inputID"})
+	if err != nil {
+		t.Errorf("err not nil: %v", err)
+	}
+}
+
+func TestCloseJob(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("expected method to be POST, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/jobs/inputID/close" {
+			t.Errorf("get url not expected: %s", r.RequestURI)
+		}
+		w.Write([]byte(`{"jobIdentifier": "jsonID"}`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Jobs().CloseJob(context.TODO(), &CloseJobInput{JobIdentifier: "inputID"})
+	if err != nil {
+		t.Errorf("err not nil: %v", err)
+	}
+	if out.Details.JobIdentifier != "jsonID" {
+		t.Errorf("response not parsed")
+	}
+}
+
 func TestGetJobResultsHTTPError(t *testing.T) {
 	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(500)
@@ -758,3 +817,206 @@ func TestGetJobFeatures(t *testing.T) {
 		t.Errorf("response not parsed")
 	}
 }
+
+func TestGetJobOutputHTTPError(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	_, err := client.Jobs().GetJobOutput(context.TODO(), &GetJobOutputInput{JobIdentifier: "jobID", InputKey: "in", OutputName: "out"})
+	if err == nil {
+		t.Errorf("Expected error")
+	}
+}
+
+func TestGetJobOutput(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("expected method to be GET, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/results/jobID/input%201/mask.png" {
+			t.Errorf("get url not expected: %s", r.RequestURI)
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("not-json"))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Jobs().GetJobOutput(context.TODO(), &GetJobOutputInput{JobIdentifier: "jobID", InputKey: "input 1", OutputName: "mask.png"})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	defer out.Output.Close()
+	if out.ContentType != "image/png" {
+		t.Errorf("content type not read: %s", out.ContentType)
+	}
+	b, _ := io.ReadAll(out.Output)
+	if string(b) != "not-json" {
+		t.Errorf("output not streamed: %s", string(b))
+	}
+}
+
+func TestDownloadJobOutputs(t *testing.T) {
+	AppFs = afero.NewMemMapFs()
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/api/results/jobID":
+			w.Write([]byte(`{
+				"results": {
+					"in-1": {"status": "SUCCESSFUL", "results.json": {"a": 1}, "mask.png": "mask.png"},
+					"in-2": {"status": "SUCCESSFUL", "results.json": {"a": 2}}
+				},
+				"failures": {
+					"in-3": {"status": "FAILED"}
+				}
+			}`))
+		case "/api/results/jobID/in-1/results.json":
+			w.Write([]byte(`{"a":1}`))
+		case "/api/results/jobID/in-1/mask.png":
+			w.Write([]byte(`png-data`))
+		case "/api/results/jobID/in-2/results.json":
+			w.Write([]byte(`{"a":2}`))
+		default:
+			t.Errorf("get url not expected: %s", r.RequestURI)
+			w.WriteHeader(404)
+		}
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Jobs().DownloadJobOutputs(context.TODO(), &DownloadJobOutputsInput{
+		JobIdentifier: "jobID",
+		Directory:     "out",
+		Concurrency:   2,
+	})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if len(out.Files) != 3 {
+		t.Fatalf("expected 3 files, got %d", len(out.Files))
+	}
+	if out.Files[0].Path != "out/in-1/mask.png" || out.Files[0].Bytes != 8 {
+		t.Errorf("file not reported: %+v", out.Files[0])
+	}
+	b, _ := afero.ReadFile(AppFs, "out/in-2/results.json")
+	if string(b) != `{"a":2}` {
+		t.Errorf("file not written: %s", string(b))
+	}
+}
+
+func TestDownloadJobOutputsFiltered(t *testing.T) {
+	AppFs = afero.NewMemMapFs()
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/api/results/jobID":
+			w.Write([]byte(`{"results": {"in-1": {"results.json": {"a": 1}, "mask.png": "mask.png"}}}`))
+		case "/api/results/jobID/in-1/mask.png":
+			w.Write([]byte(`png-data`))
+		default:
+			t.Errorf("get url not expected: %s", r.RequestURI)
+			w.WriteHeader(404)
+		}
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Jobs().DownloadJobOutputs(context.TODO(), &DownloadJobOutputsInput{
+		JobIdentifier: "jobID",
+		Directory:     "out",
+		OutputNames:   []string{"mask.png"},
+	})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if len(out.Files) != 1 {
+		t.Errorf("expected 1 file, got %d", len(out.Files))
+	}
+}
+
+func TestDownloadJobOutputsResultsError(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	_, err := client.Jobs().DownloadJobOutputs(context.TODO(), &DownloadJobOutputsInput{JobIdentifier: "jobID"})
+	if err == nil || !strings.Contains(err.Error(), "failed to read job results") {
+		t.Errorf("Expected error: %v", err)
+	}
+}
+
+func TestDownloadJobOutputsUnsafeName(t *testing.T) {
+	AppFs = afero.NewMemMapFs()
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results": {"..": {"results.json": {"a": 1}}}}`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	_, err := client.Jobs().DownloadJobOutputs(context.TODO(), &DownloadJobOutputsInput{JobIdentifier: "jobID", Directory: "out"})
+	if err == nil || !strings.Contains(err.Error(), "not safe") {
+		t.Errorf("Expected error: %v", err)
+	}
+}
+
+func TestDownloadJobOutputsPartialFile(t *testing.T) {
+	AppFs = afero.NewMemMapFs()
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.RequestURI == "/api/results/jobID" {
+			w.Write([]byte(`{"results": {"in-1": {"results.json": {"a": 1}}}}`))
+			return
+		}
+		// promise more than is sent so the copy fails part way through
+		w.Header().Set("Content-Length", "100")
+		w.Write([]byte(`{"a":`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	_, err := client.Jobs().DownloadJobOutputs(context.TODO(), &DownloadJobOutputsInput{JobIdentifier: "jobID", Directory: "out"})
+	if err == nil || !strings.Contains(err.Error(), "failed to write file out/in-1/results.json") {
+		t.Errorf("Expected error: %v", err)
+	}
+	if exists, _ := afero.Exists(AppFs, "out/in-1/results.json"); exists {
+		t.Errorf("partial file was not removed")
+	}
+}
+
+func TestDownloadJobOutputsDownloadError(t *testing.T) {
+	AppFs = afero.NewMemMapFs()
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.RequestURI == "/api/results/jobID" {
+			w.Write([]byte(`{"results": {"in-1": {"a.json": 1, "b.json": 2, "c.json": 3}}}`))
+			return
+		}
+		w.WriteHeader(500)
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	_, err := client.Jobs().DownloadJobOutputs(context.TODO(), &DownloadJobOutputsInput{JobIdentifier: "jobID", Directory: "out", Concurrency: 2})
+	if err == nil || !strings.Contains(err.Error(), "failed to get output in-1/") {
+		t.Errorf("Expected error: %v", err)
+	}
+}
+
+func TestDownloadJobOutputsCanceled(t *testing.T) {
+	AppFs = afero.NewMemMapFs()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	requested := []string{}
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.RequestURI == "/api/results/jobID" {
+			w.Write([]byte(`{"results": {"in-1": {"a.json": 1, "b.json": 2, "c.json": 3}}}`))
+			return
+		}
+		requested = append(requested, r.RequestURI)
+		cancel()
+		w.Write([]byte(`1`))
+	}))
+	defer serv.Close()
+	client := NewClient(serv.URL)
+	out, err := client.Jobs().DownloadJobOutputs(ctx, &DownloadJobOutputsInput{JobIdentifier: "jobID", Directory: "out"})
+	if err == nil {
+		t.Errorf("Expected an error for the skipped files, got %+v", out.Files)
+	}
+	if len(requested) != 1 {
+		t.Errorf("Expected the downloads to stop, got %v", requested)
+	}
+}
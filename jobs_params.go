@@ -1,9 +1,11 @@
 package modzy
 
 import (
+	"io"
 	"time"
 
 	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
 )
 
 // GetJobDetailsInput -
@@ -81,6 +83,89 @@ func (i *ListJobsHistoryInput) WithSort(sortDirection SortDirection, sortBy ...L
 	return i
 }
 
+// ListJobsHistoryDateFormat is the format of the startDate and endDate filters; dates are always sent in UTC.
+const ListJobsHistoryDateFormat = "2006-01-02T15:04:05.000Z"
+
+// WithDateRange filters the jobs to those within the range.  A zero start or end leaves that side of the range open.
+func (i *ListJobsHistoryInput) WithDateRange(start time.Time, end time.Time) *ListJobsHistoryInput {
+	if !start.IsZero() {
+		i.WithFilter(ListJobsHistoryFilterFieldStartDate, start.UTC().Format(ListJobsHistoryDateFormat))
+	}
+	if !end.IsZero() {
+		i.WithFilter(ListJobsHistoryFilterFieldEndDate, end.UTC().Format(ListJobsHistoryDateFormat))
+	}
+	return i
+}
+
+// WithStatuses filters the jobs to those with any of the provided statuses
+func (i *ListJobsHistoryInput) WithStatuses(statuses ...JobStatus) *ListJobsHistoryInput {
+	values := []string{}
+	for _, s := range statuses {
+		values = append(values, string(s))
+	}
+	return i.WithFilterOr(ListJobsHistoryFilterFieldStatus, values...)
+}
+
+// WithModel filters the jobs to those submitted to the named model
+func (i *ListJobsHistoryInput) WithModel(modelName string) *ListJobsHistoryInput {
+	return i.WithFilter(ListJobsHistoryFilterFieldModel, modelName)
+}
+
+// WithUser filters the jobs to those submitted by the user
+func (i *ListJobsHistoryInput) WithUser(user string) *ListJobsHistoryInput {
+	return i.WithFilter(ListJobsHistoryFilterFieldUser, user)
+}
+
+// WithAccessKey filters the jobs to those submitted with the access key prefix
+func (i *ListJobsHistoryInput) WithAccessKey(accessKeyPrefix string) *ListJobsHistoryInput {
+	return i.WithFilter(ListJobsHistoryFilterFieldAccessKey, accessKeyPrefix)
+}
+
+// Validate catches filters that the API would silently ignore or answer with no results.  It is called by ListJobsHistory,
+// and any error wraps ErrInvalidFilter.  Known fields are checked however the filter was added, so a date passed to
+// WithFilter must be formatted as ListJobsHistoryDateFormat.
+func (i *ListJobsHistoryInput) Validate() error {
+	seen := map[string]bool{}
+	dates := map[ListJobsHistoryFilterField]time.Time{}
+	for _, filter := range i.Paging.Filters {
+		field := ListJobsHistoryFilterField(filter.Field)
+		if len(filter.Values) == 0 {
+			return errors.WithMessagef(ErrInvalidFilter, "%s filter has no values", field)
+		}
+		switch field {
+		case ListJobsHistoryFilterFieldStartDate, ListJobsHistoryFilterFieldEndDate:
+			if seen[filter.Field] || len(filter.Values) > 1 {
+				return errors.WithMessagef(ErrInvalidFilter, "%s can only be filtered by a single value", field)
+			}
+			seen[filter.Field] = true
+			date, err := time.Parse(ListJobsHistoryDateFormat, filter.Values[0])
+			if err != nil {
+				return errors.WithMessagef(ErrInvalidFilter, "%s '%s' is not formatted as %s", field, filter.Values[0], ListJobsHistoryDateFormat)
+			}
+			dates[field] = date
+		case ListJobsHistoryFilterFieldStatus:
+			for _, status := range filter.Values {
+				if !knownJobStatuses[JobStatus(status)] {
+					return errors.WithMessagef(ErrInvalidFilter, "status '%s' is not a known job status", status)
+				}
+			}
+		case ListJobsHistoryFilterFieldModel, ListJobsHistoryFilterFieldUser, ListJobsHistoryFilterFieldAccessKey:
+			for _, value := range filter.Values {
+				if value == "" {
+					return errors.WithMessagef(ErrInvalidFilter, "%s filter has an empty value", field)
+				}
+			}
+		}
+	}
+
+	start, hasStart := dates[ListJobsHistoryFilterFieldStartDate]
+	end, hasEnd := dates[ListJobsHistoryFilterFieldEndDate]
+	if hasStart && hasEnd && end.Before(start) {
+		return errors.WithMessagef(ErrInvalidFilter, "endDate %s is before startDate %s", end.Format(ListJobsHistoryDateFormat), start.Format(ListJobsHistoryDateFormat))
+	}
+	return nil
+}
+
 type ListJobsHistoryOutput struct {
 	Jobs     []model.JobDetails    `json:"jobs"`
 	NextPage *ListJobsHistoryInput `json:"nextPage"`
@@ -130,6 +215,21 @@ type SubmitJobFileInput struct {
 
 type SubmitJobFileOutput = SubmitJobOutput
 
+type OpenJobInput struct {
+	ModelIdentifier string
+	ModelVersion    string
+	Explain         bool
+	Timeout         time.Duration
+	// ChunkSize (in bytes) is optional -- if not provided it will use the configured MaximumChunkSize.
+	// If provided it will be limited to the configured maximum;
+	ChunkSize int
+}
+
+type OpenJobOutput struct {
+	Response model.SubmitJobResponse
+	OpenJobActions
+}
+
 type S3InputItem map[string]S3Inputable
 
 type SubmitJobS3Input struct {
@@ -166,6 +266,14 @@ type CancelJobOutput struct {
 	Details model.JobDetails
 }
 
+type CloseJobInput struct {
+	JobIdentifier string `json:"jobIdentifier"`
+}
+
+type CloseJobOutput struct {
+	Details model.JobDetails
+}
+
 type GetJobResultsInput struct {
 	JobIdentifier string
 }
@@ -180,3 +288,53 @@ type GetJobFeaturesInput struct {
 type GetJobFeaturesOutput struct {
 	Features model.JobFeatures `json:"features"`
 }
+
+type GetJobOutputInput struct {
+	JobIdentifier string
+	InputKey      string
+	OutputName    string
+}
+
+type GetJobOutputOutput struct {
+	// Output is the raw output data; you must close this when done reading.
+	Output        io.ReadCloser
+	ContentType   string
+	ContentLength int64
+}
+
+type DownloadJobOutputsInput struct {
+	JobIdentifier string
+	// Directory is where the outputs will be written as Directory/{input key}/{output name}
+	Directory string
+	// OutputNames limits which outputs are downloaded; if empty, all outputs are downloaded.
+	OutputNames []string
+	// Concurrency is the number of outputs downloaded at the same time; defaults to 1.
+	Concurrency int
+}
+
+type DownloadedJobOutput struct {
+	InputKey   string
+	OutputName string
+	Path       string
+	Bytes      int64
+}
+
+type DownloadJobOutputsOutput struct {
+	Files []DownloadedJobOutput
+}
+
+type RetryFailedInputsInput struct {
+	// Submission is the original submission of the job.  Only the failed inputs are resubmitted, as a new job of the same type.
+	Submission RetryableSubmission
+	// MaxAttempts is the most retry jobs that will be submitted while inputs keep failing; defaults to 1.
+	MaxAttempts int
+	// PollInterval is used while waiting for each retry job to complete; defaults to 5 seconds.
+	PollInterval time.Duration
+}
+
+type RetryFailedInputsOutput struct {
+	// Results are the original results with the retried inputs merged in
+	Results model.JobResults
+	// RetryJobs are the jobs that were submitted, in order
+	RetryJobs []JobActions
+}
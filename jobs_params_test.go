@@ -1,8 +1,12 @@
 package modzy
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
 func TestListJobsHistoryInputWithPaging(t *testing.T) {
@@ -73,3 +77,72 @@ func TestListJobsHistoryInputWitSort(t *testing.T) {
 		t.Errorf("expected filter values to be [identifier,c], got %+v", i.Paging.SortBy)
 	}
 }
+
+func TestListJobsHistoryInputWithDateRange(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	i := (&ListJobsHistoryInput{}).WithDateRange(
+		time.Date(2021, 4, 13, 20, 1, 2, 3000000, est),
+		time.Date(2021, 4, 14, 0, 0, 0, 0, time.UTC),
+	)
+
+	if i.Paging.Filters[0].Field != "startDate" || i.Paging.Filters[0].Values[0] != "2021-04-14T01:01:02.003Z" {
+		t.Errorf("start date not expected: %+v", i.Paging.Filters[0])
+	}
+	if i.Paging.Filters[1].Field != "endDate" || i.Paging.Filters[1].Values[0] != "2021-04-14T00:00:00.000Z" {
+		t.Errorf("end date not expected: %+v", i.Paging.Filters[1])
+	}
+
+	open := (&ListJobsHistoryInput{}).WithDateRange(time.Time{}, time.Date(2021, 4, 14, 0, 0, 0, 0, time.UTC))
+	if len(open.Paging.Filters) != 1 || open.Paging.Filters[0].Field != "endDate" {
+		t.Errorf("zero start should be left out: %+v", open.Paging.Filters)
+	}
+}
+
+func TestListJobsHistoryInputTypedFilters(t *testing.T) {
+	i := (&ListJobsHistoryInput{}).
+		WithStatuses(JobStatusOpen, JobStatusTimedOut).
+		WithModel("model").
+		WithUser("user").
+		WithAccessKey("prefix")
+
+	expected := []string{"status OR OPEN,TIMEDOUT", "model AND model", "user AND user", "accessKey AND prefix"}
+	for x, e := range expected {
+		f := i.Paging.Filters[x]
+		if actual := fmt.Sprintf("%s %s %s", f.Field, f.Type, strings.Join(f.Values, ",")); actual != e {
+			t.Errorf("filter %d expected %s, got %s", x, e, actual)
+		}
+	}
+	if err := i.Validate(); err != nil {
+		t.Errorf("err not nil: %v", err)
+	}
+}
+
+func TestListJobsHistoryInputValidate(t *testing.T) {
+	start := time.Date(2021, 4, 14, 0, 0, 0, 0, time.UTC)
+	cases := map[string]*ListJobsHistoryInput{
+		"end before start": (&ListJobsHistoryInput{}).WithDateRange(start, start.Add(-time.Second)),
+		"duplicate date":   (&ListJobsHistoryInput{}).WithDateRange(start, time.Time{}).WithDateRange(start, time.Time{}),
+		"bad date":         (&ListJobsHistoryInput{}).WithFilter(ListJobsHistoryFilterFieldStartDate, "2021-04-14"),
+		"multiple dates":   (&ListJobsHistoryInput{}).WithFilterOr(ListJobsHistoryFilterFieldEndDate, "a", "b"),
+		"untyped status":   (&ListJobsHistoryInput{}).WithFilter(ListJobsHistoryFilterFieldStatus, "DONE"),
+		"empty model":      (&ListJobsHistoryInput{}).WithModel(""),
+		"empty user":       (&ListJobsHistoryInput{}).WithUser(""),
+		"empty access key": (&ListJobsHistoryInput{}).WithAccessKey(""),
+		"unknown status":   (&ListJobsHistoryInput{}).WithStatuses("DONE"),
+		"no statuses":      (&ListJobsHistoryInput{}).WithStatuses(),
+	}
+	for name, input := range cases {
+		err := input.Validate()
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+			continue
+		}
+		if errors.Cause(err) != ErrInvalidFilter {
+			t.Errorf("%s: expected ErrInvalidFilter, got %v", name, err)
+		}
+	}
+
+	if err := (&ListJobsHistoryInput{}).WithDateRange(start, start).Validate(); err != nil {
+		t.Errorf("equal dates should be valid: %v", err)
+	}
+}
@@ -1,5 +1,11 @@
 package model
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 type Entitlement struct {
 	Identifier      string `json:"identifier"`
 	Description     string `json:"description"`
@@ -18,11 +24,73 @@ type AccountingUser struct {
 	Onboarded          bool      `json:"onboarded"`
 }
 
+// AccountingUserDetails is a user along with the roles, entitlements, teams and access keys assigned to them
+type AccountingUserDetails struct {
+	AccountingUser
+	ExternalIdentifier string        `json:"externalIdentifier"`
+	Title              string        `json:"title"`
+	Roles              []Role        `json:"roles"`
+	Entitlements       []Entitlement `json:"entitlements"`
+	Teams              []TeamSummary `json:"teams"`
+	AccessKeys         []AccessKey   `json:"accessKeys"`
+}
+
+type Role struct {
+	Identifier  string `json:"identifier"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type TeamSummary struct {
+	Identifier string `json:"identifier"`
+	Name       string `json:"name"`
+}
+
+type AccountingTeam struct {
+	Identifier  string        `json:"identifier"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Members     []UserSummary `json:"members"`
+	CreatedAt   ModzyTime     `json:"createdAt"`
+	UpdatedAt   ModzyTime     `json:"updatedAt"`
+}
+
 type License struct {
 	CompanyName       string `json:"companyName"`
 	ProcessingEngines string `json:"processingEngines"`
 }
 
+// ProcessingEngineLimit is the number of processing engines allowed by a license
+type ProcessingEngineLimit struct {
+	Unlimited bool
+	Engines   int
+}
+
+// Allows is true if the limit allows the provided number of engines
+func (l ProcessingEngineLimit) Allows(engines int) bool {
+	return l.Unlimited || engines <= l.Engines
+}
+
+func (l ProcessingEngineLimit) String() string {
+	if l.Unlimited {
+		return "unlimited"
+	}
+	return strconv.Itoa(l.Engines)
+}
+
+// EngineLimit parses ProcessingEngines.  A license that leaves it empty or states that it is unlimited has no limit.
+func (l License) EngineLimit() (ProcessingEngineLimit, error) {
+	value := strings.TrimSpace(l.ProcessingEngines)
+	if value == "" || strings.EqualFold(value, "unlimited") {
+		return ProcessingEngineLimit{Unlimited: true}, nil
+	}
+	engines, err := strconv.Atoi(value)
+	if err != nil || engines < 0 {
+		return ProcessingEngineLimit{}, fmt.Errorf("license has an invalid number of processing engines: %q", l.ProcessingEngines)
+	}
+	return ProcessingEngineLimit{Engines: engines}, nil
+}
+
 type AccountingProject struct {
 	Identifier  string      `json:"identifier"`
 	Name        string      `json:"name"`
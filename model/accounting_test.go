@@ -0,0 +1,44 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+func TestLicenseEngineLimit(t *testing.T) {
+	cases := []struct {
+		engines  string
+		expected model.ProcessingEngineLimit
+	}{
+		{"10", model.ProcessingEngineLimit{Engines: 10}},
+		{" 0 ", model.ProcessingEngineLimit{Engines: 0}},
+		{"", model.ProcessingEngineLimit{Unlimited: true}},
+		{"Unlimited", model.ProcessingEngineLimit{Unlimited: true}},
+	}
+	for _, c := range cases {
+		limit, err := model.License{ProcessingEngines: c.engines}.EngineLimit()
+		if err != nil {
+			t.Errorf("%q: expected no error, got %v", c.engines, err)
+		}
+		if limit != c.expected {
+			t.Errorf("%q: expected %+v, got %+v", c.engines, c.expected, limit)
+		}
+	}
+
+	for _, bad := range []string{"ten", "-1", "1.5"} {
+		if _, err := (model.License{ProcessingEngines: bad}).EngineLimit(); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestProcessingEngineLimitAllows(t *testing.T) {
+	limit := model.ProcessingEngineLimit{Engines: 3}
+	if !limit.Allows(3) || limit.Allows(4) {
+		t.Errorf("Expected 3 engines to be allowed and not 4")
+	}
+	if !(model.ProcessingEngineLimit{Unlimited: true}).Allows(1000) || limit.String() != "3" {
+		t.Errorf("Expected an unlimited license to allow anything")
+	}
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrOutputNotFound is the cause of an OutputNotFoundError
+var ErrOutputNotFound = fmt.Errorf("output not found")
+
+// OutputNotFoundError is returned when a result does not contain a requested output
+type OutputNotFoundError struct {
+	Output    string
+	Available []string
+}
+
+func (e *OutputNotFoundError) Error() string {
+	return fmt.Sprintf("output %s not found; available outputs: [%s]", e.Output, strings.Join(e.Available, ", "))
+}
+
+func (e *OutputNotFoundError) Cause() error {
+	return ErrOutputNotFound
+}
+
+func (e *OutputNotFoundError) Unwrap() error {
+	return ErrOutputNotFound
+}
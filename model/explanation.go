@@ -0,0 +1,86 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// ImageExplanation is the explanation provided by image models when a job is submitted with Explain set.
+type ImageExplanation struct {
+	// MaskRLE is the run-length encoded saliency mask, one entry per row of the image.
+	// Each row alternates between runs of pixels outside and inside the mask, starting with pixels outside the mask.
+	MaskRLE    [][]int                    `json:"maskRLE"`
+	Dimensions ImageExplanationDimensions `json:"dimensions"`
+}
+
+type ImageExplanationDimensions struct {
+	Height int `json:"height"`
+	Width  int `json:"width"`
+}
+
+// TextExplanation is the explanation provided by text models when a job is submitted with Explain set.
+type TextExplanation struct {
+	// WordImportances are keyed by the predicted class
+	WordImportances map[string][]WordImportance `json:"wordImportances"`
+	ExplainableText json.RawMessage             `json:"explainableText,omitempty"`
+}
+
+type WordImportance struct {
+	Word       string  `json:"word"`
+	Importance float64 `json:"importance"`
+}
+
+// explanationHolder matches the places an explanation can be found within an output
+type explanationHolder struct {
+	Explanation json.RawMessage `json:"explanation"`
+	Data        *struct {
+		Explanation json.RawMessage `json:"explanation"`
+	} `json:"data"`
+}
+
+// RawExplanation finds the explanation within the named output.  It may be at the top level of the output or within its "data".
+func RawExplanation(result JobResult, outputName string) (json.RawMessage, error) {
+	raw, err := result.Output(outputName)
+	if err != nil {
+		return nil, err
+	}
+	var holder explanationHolder
+	if err := json.Unmarshal(raw, &holder); err != nil {
+		return nil, errors.WithMessagef(err, "failed to read output %s", outputName)
+	}
+	if len(holder.Explanation) != 0 && string(holder.Explanation) != "null" {
+		return holder.Explanation, nil
+	}
+	if holder.Data != nil && len(holder.Data.Explanation) != 0 && string(holder.Data.Explanation) != "null" {
+		return holder.Data.Explanation, nil
+	}
+	return nil, fmt.Errorf("output %s does not contain an explanation; was the job submitted with Explain set?", outputName)
+}
+
+// DecodeImageExplanation reads the image explanation from the named output, usually "results.json".
+func DecodeImageExplanation(result JobResult, outputName string) (*ImageExplanation, error) {
+	raw, err := RawExplanation(result, outputName)
+	if err != nil {
+		return nil, err
+	}
+	var explanation ImageExplanation
+	if err := json.Unmarshal(raw, &explanation); err != nil {
+		return nil, errors.WithMessage(err, "failed to decode image explanation")
+	}
+	return &explanation, nil
+}
+
+// DecodeTextExplanation reads the text explanation from the named output, usually "results.json".
+func DecodeTextExplanation(result JobResult, outputName string) (*TextExplanation, error) {
+	raw, err := RawExplanation(result, outputName)
+	if err != nil {
+		return nil, err
+	}
+	var explanation TextExplanation
+	if err := json.Unmarshal(raw, &explanation); err != nil {
+		return nil, errors.WithMessage(err, "failed to decode text explanation")
+	}
+	return &explanation, nil
+}
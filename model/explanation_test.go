@@ -0,0 +1,70 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDecodeImageExplanation(t *testing.T) {
+	var jr JobResult
+	err := json.Unmarshal([]byte(`{"status":"s","results.json":{"data":{"result":{},"explanation":{"maskRLE":[[1,2],[3]],"dimensions":{"height":2,"width":3}}}}}`), &jr)
+	if err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+	exp, err := DecodeImageExplanation(jr, "results.json")
+	if err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+	if exp.Dimensions.Height != 2 || exp.Dimensions.Width != 3 {
+		t.Errorf("dimensions not decoded: %+v", exp.Dimensions)
+	}
+	if len(exp.MaskRLE) != 2 || exp.MaskRLE[0][1] != 2 {
+		t.Errorf("mask not decoded: %+v", exp.MaskRLE)
+	}
+}
+
+func TestDecodeTextExplanation(t *testing.T) {
+	var jr JobResult
+	err := json.Unmarshal([]byte(`{"results.json":{"explanation":{"wordImportances":{"positive":[{"word":"love","importance":0.9}]}}}}`), &jr)
+	if err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+	exp, err := DecodeTextExplanation(jr, "results.json")
+	if err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+	if exp.WordImportances["positive"][0].Word != "love" || exp.WordImportances["positive"][0].Importance != 0.9 {
+		t.Errorf("word importances not decoded: %+v", exp.WordImportances)
+	}
+}
+
+func TestDecodeExplanationMissing(t *testing.T) {
+	var jr JobResult
+	_ = json.Unmarshal([]byte(`{"results.json":{"data":{"result":{}}}}`), &jr)
+	_, err := DecodeImageExplanation(jr, "results.json")
+	if err == nil || !strings.Contains(err.Error(), "does not contain an explanation") {
+		t.Errorf("error was not expected kind: %v", err)
+	}
+	_, err = DecodeTextExplanation(jr, "other.json")
+	if _, ok := err.(*OutputNotFoundError); !ok {
+		t.Errorf("error was not expected kind: %v", err)
+	}
+}
+
+func TestDecodeExplanationBadFormat(t *testing.T) {
+	var jr JobResult
+	_ = json.Unmarshal([]byte(`{"results.json":{"explanation":{"maskRLE":"nope","wordImportances":"nope"}},"other.json":[1]}`), &jr)
+	_, err := DecodeImageExplanation(jr, "results.json")
+	if err == nil || !strings.Contains(err.Error(), "failed to decode image explanation") {
+		t.Errorf("error was not expected kind: %v", err)
+	}
+	_, err = DecodeTextExplanation(jr, "results.json")
+	if err == nil || !strings.Contains(err.Error(), "failed to decode text explanation") {
+		t.Errorf("error was not expected kind: %v", err)
+	}
+	_, err = RawExplanation(jr, "other.json")
+	if err == nil || !strings.Contains(err.Error(), "failed to read output other.json") {
+		t.Errorf("error was not expected kind: %v", err)
+	}
+}
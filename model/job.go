@@ -17,16 +17,15 @@ type JobInputIdentifier struct {
 }
 
 type User struct {
-	Identifier         string `json:"identifier"`
-	FirstName          string `json:"firstName"`
-	LastName           string `json:"lastName"`
-	Email              string `json:"email"`
-	ExternalIdentifier string `json:"externalIdentifier"`
-	PictureURL         string `json:"pictureURL"`
-	Status             string `json:"status"`
-	Title              string `json:"title"`
-	// TODO: type this
-	AccessKeys []interface{} `json:"accessKeys"`
+	Identifier         string      `json:"identifier"`
+	FirstName          string      `json:"firstName"`
+	LastName           string      `json:"lastName"`
+	Email              string      `json:"email"`
+	ExternalIdentifier string      `json:"externalIdentifier"`
+	PictureURL         string      `json:"pictureURL"`
+	Status             string      `json:"status"`
+	Title              string      `json:"title"`
+	AccessKeys         []AccessKey `json:"accessKeys"`
 }
 
 type Team struct {
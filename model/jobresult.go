@@ -2,6 +2,7 @@ package model
 
 import (
 	"encoding/json"
+	"sort"
 
 	"github.com/modzy/sdk-go/internal/impossible"
 	"github.com/pkg/errors"
@@ -21,14 +22,15 @@ type JobResults struct {
 	JobProcessedTime int `json:"jobProcessedTime"`
 	JobElapsedTime   int `json:"jobElapsedTime"`
 
-	// next api version:
-	// InitialQueueTime          int       `json:"initialQueueTime"`
-	// TotalQueueTime            int       `json:"totalQueueTime"`
-	// AverageModelLatency       float64   `json:"averageModelLatency"`
-	// TotalModelLatency         float64   `json:"totalModelLatency"`
-	// ElapsedTime               float64   `json:"elapsedTime"`
-	// StartingResultSummarizing ModzyTime `json:"startingResultSummarizing"`
-	// ResultSummarizing         int       `json:"resultSummarizing"`
+	// next api version; these are left empty by servers that do not provide them
+	InitialQueueTime    int     `json:"initialQueueTime,omitempty"`
+	TotalQueueTime      int     `json:"totalQueueTime,omitempty"`
+	AverageModelLatency float64 `json:"averageModelLatency,omitempty"`
+	TotalModelLatency   float64 `json:"totalModelLatency,omitempty"`
+	ElapsedTime         float64 `json:"elapsedTime,omitempty"`
+	// StartingResultSummarizing is a pointer so that it is omitted when not provided, like the other fields here
+	StartingResultSummarizing *ModzyTime `json:"startingResultSummarizing,omitempty"`
+	ResultSummarizing         int        `json:"resultSummarizing,omitempty"`
 
 	Results  map[string]JobResult `json:"results"`
 	Failures map[string]JobResult `json:"failures"`
@@ -43,9 +45,23 @@ type JobResult struct {
 	EndTime     ModzyTime              `json:"endTime"`
 	ElapsedTime int                    `json:"elapsedTime"`
 	Data        map[string]interface{} `json:"data"`
+	// RawData holds the undecoded json of each entry in Data, keyed the same way, and is what Output returns.  Use
+	// SetOutput to change an output, or delete its entry here after changing Data, so that the two stay in step.
+	RawData map[string]json.RawMessage `json:"-"`
 }
 
 var _ json.Unmarshaler = &JobResult{}
+var _ json.Marshaler = &JobResult{}
+
+// jobResultKnownFields are the keys that are not treated as model outputs
+var jobResultKnownFields = []string{
+	"status",
+	"engine",
+	"startTime",
+	"updateTime",
+	"endTime",
+	"elapsedTime",
+}
 
 // UnmarshalJSON custom unmarshal in order to fill in ResultData with extra fields
 func (j *JobResult) UnmarshalJSON(b []byte) error {
@@ -59,24 +75,142 @@ func (j *JobResult) UnmarshalJSON(b []byte) error {
 	}
 
 	// get the extra fields
-	extra := make(map[string]interface{})
+	rawExtra := make(map[string]json.RawMessage)
 
 	// I cannot think of a way this could error after the previous marshal passes...
-	impossible.HandleError(json.Unmarshal(b, &extra))
+	impossible.HandleError(json.Unmarshal(b, &rawExtra))
 
 	// do not repeat the known fields within the extras
-	for _, f := range []string{
-		"status",
-		"engine",
-		"startTime",
-		"updateTime",
-		"endTime",
-		"elapsedTime",
-	} {
-		delete(extra, f)
+	for _, f := range jobResultKnownFields {
+		delete(rawExtra, f)
+	}
+	extra := make(map[string]interface{}, len(rawExtra))
+	for name, raw := range rawExtra {
+		var value interface{}
+		impossible.HandleError(json.Unmarshal(raw, &value))
+		extra[name] = value
 	}
 	inner.Data = extra
+	inner.RawData = rawExtra
 
 	*j = JobResult(inner)
 	return nil
 }
+
+// MarshalJSON writes the result in the same shape as the API returns it, with each entry in Data
+// placed next to the known fields.  This allows a JobResult to survive a round trip through json.
+func (j JobResult) MarshalJSON() ([]byte, error) {
+	type innerJobResult JobResult
+
+	inner := innerJobResult(j)
+	inner.Data = nil
+	b, err := json.Marshal(inner)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]json.RawMessage)
+	impossible.HandleError(json.Unmarshal(b, &out))
+	delete(out, "data")
+
+	for _, name := range j.OutputNames() {
+		if _, known := out[name]; known {
+			continue
+		}
+		raw, err := j.Output(name)
+		if err != nil {
+			return nil, err
+		}
+		out[name] = raw
+	}
+
+	return json.Marshal(out)
+}
+
+// OutputNames returns the sorted names of all of the outputs (such as "results.json") found in this result.
+func (j JobResult) OutputNames() []string {
+	names := []string{}
+	for name := range j.Data {
+		names = append(names, name)
+	}
+	for name := range j.RawData {
+		if _, has := j.Data[name]; !has {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Output returns the raw json of the named output.  If the output does not exist an *OutputNotFoundError is returned.
+// Outputs without an entry in RawData, such as in results built by hand, are marshalled from Data.
+func (j JobResult) Output(name string) (json.RawMessage, error) {
+	if raw, has := j.RawData[name]; has {
+		return raw, nil
+	}
+	if data, has := j.Data[name]; has {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to marshal output %s", name)
+		}
+		return raw, nil
+	}
+	return nil, &OutputNotFoundError{
+		Output:    name,
+		Available: j.OutputNames(),
+	}
+}
+
+// SetOutput replaces the named output in both Data and RawData.
+func (j *JobResult) SetOutput(name string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to marshal output %s", name)
+	}
+	if j.Data == nil {
+		j.Data = map[string]interface{}{}
+	}
+	if j.RawData == nil {
+		j.RawData = map[string]json.RawMessage{}
+	}
+	j.Data[name] = value
+	j.RawData[name] = raw
+	return nil
+}
+
+// DecodeOutput will decode the named output (such as "results.json") of a single result into the provided type.
+func DecodeOutput[T any](result JobResult, outputName string) (T, error) {
+	var out T
+	raw, err := result.Output(outputName)
+	if err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return out, errors.WithMessagef(err, "failed to decode output %s", outputName)
+	}
+	return out, nil
+}
+
+// TypedJobResults is a view of JobResults where the named output of each successful input has been decoded.
+type TypedJobResults[T any] struct {
+	JobResults
+	// Outputs are keyed by the input key, the same as JobResults.Results
+	Outputs map[string]T
+}
+
+// DecodeJobResults will decode the named output of every successful input into the provided type.
+// Failed inputs are left in Failures and are not decoded.
+func DecodeJobResults[T any](results JobResults, outputName string) (*TypedJobResults[T], error) {
+	typed := &TypedJobResults[T]{
+		JobResults: results,
+		Outputs:    make(map[string]T, len(results.Results)),
+	}
+	for inputKey, result := range results.Results {
+		out, err := DecodeOutput[T](result, outputName)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "input %s", inputKey)
+		}
+		typed.Outputs[inputKey] = out
+	}
+	return typed, nil
+}
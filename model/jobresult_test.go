@@ -33,3 +33,207 @@ func TestJobResultUnmarshalError(t *testing.T) {
 		t.Fatalf("error was not expected kind: %v", err)
 	}
 }
+
+func TestJobResultUnmarshalRawData(t *testing.T) {
+	var jr JobResult
+	err := json.Unmarshal([]byte(`{"status":"s","results.json":{"a": 1.50}}`), &jr)
+	if err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+	if string(jr.RawData["results.json"]) != `{"a": 1.50}` {
+		t.Errorf("raw data not kept: %s", jr.RawData["results.json"])
+	}
+	if _, has := jr.RawData["status"]; has {
+		t.Errorf("known fields should not be in the raw data")
+	}
+}
+
+func TestJobResultMarshalRoundTrip(t *testing.T) {
+	var jr JobResult
+	err := json.Unmarshal([]byte(`{"status":"s","engine":"e","results.json":{"a":1}}`), &jr)
+	if err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+	b, err := json.Marshal(jr)
+	if err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+	var again JobResult
+	if err := json.Unmarshal(b, &again); err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+	if again.Status != "s" || again.Engine != "e" {
+		t.Errorf("known fields not kept: %s", string(b))
+	}
+	if string(again.RawData["results.json"]) != `{"a":1}` {
+		t.Errorf("output not kept at the top level: %s", string(b))
+	}
+	if _, has := again.Data["data"]; has {
+		t.Errorf("data should not be nested: %s", string(b))
+	}
+}
+
+func TestJobResultMarshalWithoutRawData(t *testing.T) {
+	jr := JobResult{
+		Status: "s",
+		Data:   map[string]interface{}{"results.json": map[string]interface{}{"a": 1}},
+	}
+	b, err := json.Marshal(jr)
+	if err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+	if !strings.Contains(string(b), `"results.json":{"a":1}`) {
+		t.Errorf("output not written: %s", string(b))
+	}
+}
+
+func TestJobResultMarshalChangedData(t *testing.T) {
+	var jr JobResult
+	err := json.Unmarshal([]byte(`{"status":"s","results.json":{"a":1.50},"other.json":{"b":2}}`), &jr)
+	if err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+	if err := jr.SetOutput("results.json", map[string]interface{}{"a": 3}); err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+
+	b, err := json.Marshal(jr)
+	if err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+	if !strings.Contains(string(b), `"results.json":{"a":3}`) {
+		t.Errorf("changed output not written: %s", string(b))
+	}
+	if !strings.Contains(string(b), `"other.json":{"b":2}`) {
+		t.Errorf("unchanged output not written: %s", string(b))
+	}
+	if raw, _ := jr.Output("other.json"); string(raw) != `{"b":2}` {
+		t.Errorf("expected the raw json of an unchanged output, got %s", raw)
+	}
+}
+
+func TestJobResultsOmitsStartingResultSummarizing(t *testing.T) {
+	b, err := json.Marshal(JobResults{})
+	if err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+	if strings.Contains(string(b), "startingResultSummarizing") {
+		t.Errorf("expected startingResultSummarizing to be omitted: %s", string(b))
+	}
+}
+
+func TestJobResultOutputNames(t *testing.T) {
+	jr := JobResult{
+		Data:    map[string]interface{}{"b": 1, "a": 2},
+		RawData: map[string]json.RawMessage{"a": []byte(`2`), "c": []byte(`3`)},
+	}
+	names := jr.OutputNames()
+	if strings.Join(names, ",") != "a,b,c" {
+		t.Errorf("names not expected: %v", names)
+	}
+}
+
+func TestDecodeOutput(t *testing.T) {
+	var jr JobResult
+	err := json.Unmarshal([]byte(`{"status":"s","results.json":{"label":"cat","score":0.5}}`), &jr)
+	if err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+	type classification struct {
+		Label string  `json:"label"`
+		Score float64 `json:"score"`
+	}
+	out, err := DecodeOutput[classification](jr, "results.json")
+	if err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+	if out.Label != "cat" || out.Score != 0.5 {
+		t.Errorf("output not decoded: %+v", out)
+	}
+}
+
+func TestDecodeOutputFromData(t *testing.T) {
+	jr := JobResult{
+		Data: map[string]interface{}{"results.json": map[string]interface{}{"label": "dog"}},
+	}
+	out, err := DecodeOutput[map[string]string](jr, "results.json")
+	if err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+	if out["label"] != "dog" {
+		t.Errorf("output not decoded: %+v", out)
+	}
+}
+
+func TestDecodeOutputMissing(t *testing.T) {
+	jr := JobResult{
+		Data: map[string]interface{}{"other.json": 1},
+	}
+	_, err := DecodeOutput[map[string]string](jr, "results.json")
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	notFound, ok := err.(*OutputNotFoundError)
+	if !ok {
+		t.Fatalf("error was not expected kind: %T", err)
+	}
+	if notFound.Cause() != ErrOutputNotFound {
+		t.Errorf("cause was not ErrOutputNotFound")
+	}
+	if err.Error() != "output results.json not found; available outputs: [other.json]" {
+		t.Errorf("error message not expected: %v", err)
+	}
+}
+
+func TestDecodeOutputWrongType(t *testing.T) {
+	jr := JobResult{
+		RawData: map[string]json.RawMessage{"results.json": []byte(`"a string"`)},
+	}
+	_, err := DecodeOutput[map[string]string](jr, "results.json")
+	if err == nil || !strings.Contains(err.Error(), "failed to decode output results.json") {
+		t.Errorf("error was not expected kind: %v", err)
+	}
+}
+
+func TestDecodeJobResults(t *testing.T) {
+	var results JobResults
+	err := json.Unmarshal([]byte(`{
+		"jobIdentifier": "job",
+		"totalQueueTime": 12,
+		"results": {
+			"input-1": {"status": "SUCCESSFUL", "results.json": {"label": "a"}},
+			"input-2": {"status": "SUCCESSFUL", "results.json": {"label": "b"}}
+		},
+		"failures": {
+			"input-3": {"status": "FAILED", "error": "bad"}
+		}
+	}`), &results)
+	if err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+	typed, err := DecodeJobResults[map[string]string](results, "results.json")
+	if err != nil {
+		t.Fatalf("error was not nil: %v", err)
+	}
+	if typed.JobIdentifier != "job" || typed.TotalQueueTime != 12 {
+		t.Errorf("job information not kept")
+	}
+	if len(typed.Outputs) != 2 || typed.Outputs["input-2"]["label"] != "b" {
+		t.Errorf("outputs not decoded: %+v", typed.Outputs)
+	}
+	if typed.Failures["input-3"].Error != "bad" {
+		t.Errorf("failures not kept")
+	}
+}
+
+func TestDecodeJobResultsMissingOutput(t *testing.T) {
+	results := JobResults{
+		Results: map[string]JobResult{
+			"input-1": {Data: map[string]interface{}{}},
+		},
+	}
+	_, err := DecodeJobResults[map[string]string](results, "results.json")
+	if err == nil || !strings.Contains(err.Error(), "input input-1") {
+		t.Errorf("error was not expected kind: %v", err)
+	}
+}
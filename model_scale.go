@@ -0,0 +1,119 @@
+package modzy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+const defaultScalePollInterval = 10 * time.Second
+
+type ScaleModelVersionInput struct {
+	ModelID                 string
+	Version                 string
+	MinimumParallelCapacity int
+	MaximumParallelCapacity int
+	// PollInterval is how often the processing models are read; defaults to 10 seconds
+	PollInterval time.Duration
+	// Progress, if provided, is called with the processing model every time it is read
+	Progress func(processing model.ResourcesProcessingModel)
+}
+
+type ScaleModelVersionOutput struct {
+	Details model.ModelVersionDetails
+	// Processing is the processing model once the requested engines were ready; it is empty when scaled to zero
+	Processing model.ResourcesProcessingModel
+}
+
+// ScaleModelVersion updates the processing engines of a model version and waits until the minimum number of engines are
+// ready and the deployment is ready.  Cancel the context to stop waiting.  If the deployment reports an error, the
+// returned error wraps ErrDeploymentFailed and describes the conditions of every engine.
+func ScaleModelVersion(ctx context.Context, client Client, input *ScaleModelVersionInput) (*ScaleModelVersionOutput, error) {
+	updated, err := client.Models().UpdateModelProcessingEngines(ctx, &UpdateModelProcessingEnginesInput{
+		ModelID:                 input.ModelID,
+		Version:                 input.Version,
+		MinimumParallelCapacity: input.MinimumParallelCapacity,
+		MaximumParallelCapacity: input.MaximumParallelCapacity,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to update processing engines")
+	}
+	out := &ScaleModelVersionOutput{Details: updated.Details}
+	if input.MinimumParallelCapacity <= 0 {
+		// nothing is kept running so there is nothing to wait for
+		return out, nil
+	}
+
+	pollInterval := input.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultScalePollInterval
+	}
+	timer := time.NewTimer(pollInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, errors.WithMessage(ctx.Err(), "wait for processing engines was canceled")
+		case <-timer.C:
+			processing, err := client.Resources().GetProcessingModels(ctx)
+			if err != nil {
+				return nil, errors.WithMessage(err, "failed to read processing models")
+			}
+			// the model version may not be listed until the first engine is scheduled
+			for _, pm := range processing.Models {
+				if pm.Identifier != input.ModelID || pm.Version != input.Version {
+					continue
+				}
+				if input.Progress != nil {
+					input.Progress(pm)
+				}
+				if pm.ModelDeploymentState.HasError {
+					return nil, errors.WithMessagef(ErrDeploymentFailed, "%s@%s: %s", input.ModelID, input.Version, EngineConditions(pm.Engines))
+				}
+				if pm.ModelDeploymentState.Ready && readyEngines(pm.Engines) >= input.MinimumParallelCapacity {
+					out.Processing = pm
+					return out, nil
+				}
+			}
+			timer.Reset(pollInterval)
+		}
+	}
+}
+
+// EngineConditions describes the readiness and conditions of each engine, such as "engine-1 (not ready: PodScheduled=True, ContainersReady=False)"
+func EngineConditions(engines []model.ResourcesProcessingModelEngine) string {
+	if len(engines) == 0 {
+		return "no engines"
+	}
+	described := []string{}
+	for _, engine := range engines {
+		state := "ready"
+		if !engine.Ready {
+			state = "not ready"
+		}
+		conditions := []string{}
+		for _, condition := range engine.Conditions {
+			conditions = append(conditions, fmt.Sprintf("%s=%s", condition.Type, condition.Status))
+		}
+		if len(conditions) > 0 {
+			state = state + ": " + strings.Join(conditions, ", ")
+		}
+		described = append(described, fmt.Sprintf("%s (%s)", engine.Name, state))
+	}
+	return strings.Join(described, "; ")
+}
+
+func readyEngines(engines []model.ResourcesProcessingModelEngine) int {
+	ready := 0
+	for _, engine := range engines {
+		if engine.Ready {
+			ready++
+		}
+	}
+	return ready
+}
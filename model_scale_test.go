@@ -0,0 +1,155 @@
+package modzy
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+func TestScaleModelVersion(t *testing.T) {
+	starting := model.ResourcesProcessingModel{
+		Identifier: "model", Version: "1.0.0",
+		Engines: []model.ResourcesProcessingModelEngine{{Name: "e1", Ready: true}, {Name: "e2"}},
+	}
+	ready := model.ResourcesProcessingModel{
+		Identifier: "model", Version: "1.0.0",
+		Engines:              []model.ResourcesProcessingModelEngine{{Name: "e1", Ready: true}, {Name: "e2", Ready: true}},
+		ModelDeploymentState: model.ResourcesProcessingModelDeploymentState{Ready: true},
+	}
+	var updated UpdateModelProcessingEnginesInput
+	models := &ModelsClientFake{
+		UpdateModelProcessingEnginesFunc: func(ctx context.Context, input *UpdateModelProcessingEnginesInput) (*UpdateModelProcessingEnginesOutput, error) {
+			updated = *input
+			return &UpdateModelProcessingEnginesOutput{Details: model.ModelVersionDetails{ModelID: input.ModelID, Version: input.Version}}, nil
+		},
+	}
+	polls := 0
+	resources := &ResourcesClientFake{
+		GetProcessingModelsFunc: func(ctx context.Context) (*GetProcessingModelsOutput, error) {
+			// the version is not listed until it starts, and other versions are ignored
+			state := []model.ResourcesProcessingModel{{}, starting, ready}[min(polls, 2)]
+			polls++
+			return &GetProcessingModelsOutput{Models: []model.ResourcesProcessingModel{
+				{Identifier: "other", Version: "1.0.0", ModelDeploymentState: model.ResourcesProcessingModelDeploymentState{HasError: true}},
+				state,
+			}}, nil
+		},
+	}
+	client := &ClientFake{
+		ModelsFunc:    func() ModelsClient { return models },
+		ResourcesFunc: func() ResourcesClient { return resources },
+	}
+
+	progress := 0
+	out, err := ScaleModelVersion(context.TODO(), client, &ScaleModelVersionInput{
+		ModelID:                 "model",
+		Version:                 "1.0.0",
+		MinimumParallelCapacity: 2,
+		MaximumParallelCapacity: 3,
+		PollInterval:            time.Millisecond,
+		Progress:                func(processing model.ResourcesProcessingModel) { progress++ },
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated.MinimumParallelCapacity != 2 || updated.MaximumParallelCapacity != 3 {
+		t.Errorf("Expected capacity to be updated, got %+v", updated)
+	}
+	if polls != 3 || progress != 2 {
+		t.Errorf("Expected 3 polls with 2 progress reports, got %d and %d", polls, progress)
+	}
+	if out.Details.ModelID != "model" || readyEngines(out.Processing.Engines) != 2 {
+		t.Errorf("Expected the ready processing model, got %+v", out)
+	}
+}
+
+func TestScaleModelVersionToZero(t *testing.T) {
+	models := &ModelsClientFake{
+		UpdateModelProcessingEnginesFunc: func(ctx context.Context, input *UpdateModelProcessingEnginesInput) (*UpdateModelProcessingEnginesOutput, error) {
+			return &UpdateModelProcessingEnginesOutput{Details: model.ModelVersionDetails{ModelID: input.ModelID, Version: input.Version}}, nil
+		},
+	}
+	client := &ClientFake{
+		ModelsFunc: func() ModelsClient { return models },
+		ResourcesFunc: func() ResourcesClient {
+			t.Errorf("Expected no polling when scaling to zero")
+			return &ResourcesClientFake{}
+		},
+	}
+
+	_, err := ScaleModelVersion(context.TODO(), client, &ScaleModelVersionInput{ModelID: "model", Version: "1.0.0", MaximumParallelCapacity: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestScaleModelVersionHasError(t *testing.T) {
+	failed := model.ResourcesProcessingModel{
+		Identifier: "model", Version: "1.0.0",
+		Engines: []model.ResourcesProcessingModelEngine{{
+			Name:       "e1",
+			Conditions: []model.ResourcesProcessingModelEngineCondition{{Type: "PodScheduled", Status: "False"}},
+		}},
+		ModelDeploymentState: model.ResourcesProcessingModelDeploymentState{HasError: true},
+	}
+	models := &ModelsClientFake{
+		UpdateModelProcessingEnginesFunc: func(ctx context.Context, input *UpdateModelProcessingEnginesInput) (*UpdateModelProcessingEnginesOutput, error) {
+			return &UpdateModelProcessingEnginesOutput{Details: model.ModelVersionDetails{ModelID: input.ModelID, Version: input.Version}}, nil
+		},
+	}
+	resources := &ResourcesClientFake{
+		GetProcessingModelsFunc: func(ctx context.Context) (*GetProcessingModelsOutput, error) {
+			return &GetProcessingModelsOutput{Models: []model.ResourcesProcessingModel{failed}}, nil
+		},
+	}
+	client := &ClientFake{
+		ModelsFunc:    func() ModelsClient { return models },
+		ResourcesFunc: func() ResourcesClient { return resources },
+	}
+
+	_, err := ScaleModelVersion(context.TODO(), client, &ScaleModelVersionInput{ModelID: "model", Version: "1.0.0", MinimumParallelCapacity: 1, PollInterval: time.Millisecond})
+	if errors.Cause(err) != ErrDeploymentFailed {
+		t.Fatalf("Expected ErrDeploymentFailed, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "e1 (not ready: PodScheduled=False)") {
+		t.Errorf("Expected engine conditions in the error, got %v", err)
+	}
+}
+
+func TestScaleModelVersionCanceled(t *testing.T) {
+	models := &ModelsClientFake{
+		UpdateModelProcessingEnginesFunc: func(ctx context.Context, input *UpdateModelProcessingEnginesInput) (*UpdateModelProcessingEnginesOutput, error) {
+			return &UpdateModelProcessingEnginesOutput{Details: model.ModelVersionDetails{ModelID: input.ModelID, Version: input.Version}}, nil
+		},
+	}
+	resources := &ResourcesClientFake{
+		GetProcessingModelsFunc: func(ctx context.Context) (*GetProcessingModelsOutput, error) {
+			return &GetProcessingModelsOutput{}, nil
+		},
+	}
+	client := &ClientFake{
+		ModelsFunc:    func() ModelsClient { return models },
+		ResourcesFunc: func() ResourcesClient { return resources },
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := ScaleModelVersion(ctx, client, &ScaleModelVersionInput{ModelID: "model", Version: "1.0.0", MinimumParallelCapacity: 1, PollInterval: time.Millisecond})
+	if errors.Cause(err) != context.DeadlineExceeded {
+		t.Errorf("Expected the context error, got %v", err)
+	}
+}
+
+func TestEngineConditions(t *testing.T) {
+	if EngineConditions(nil) != "no engines" {
+		t.Errorf("Expected no engines")
+	}
+	got := EngineConditions([]model.ResourcesProcessingModelEngine{{Name: "a", Ready: true}, {Name: "b"}})
+	if got != "a (ready); b (not ready)" {
+		t.Errorf("Unexpected conditions %q", got)
+	}
+}
@@ -0,0 +1,253 @@
+package modzy
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+type SearchModelsInput struct {
+	// Query is matched against model names, descriptions, tags and features while allowing for typos
+	Query string
+	// Tags narrow the search to models with every one of the tags, by identifier or name
+	Tags []string
+	// Features narrow the search to models with every one of the features, by identifier or name
+	Features []string
+	// Limit is the maximum number of results; zero returns every match
+	Limit int
+	// Concurrency is the number of model details read at the same time; defaults to 4
+	Concurrency int
+}
+
+type ModelSearchResult struct {
+	Details model.ModelDetails
+	// Score is higher for better matches
+	Score float64
+}
+
+type SearchModelsOutput struct {
+	// Results are ordered from the best match
+	Results []ModelSearchResult
+	// TagFacets count the tags of every matching model by tag name, before the limit is applied
+	TagFacets map[string]int
+	// FeatureFacets count the features of every matching model by feature name, before the limit is applied
+	FeatureFacets map[string]int
+}
+
+const (
+	searchWeightName        = 3
+	searchWeightTagFeature  = 2
+	searchWeightDescription = 1
+	defaultSearchDetails    = 4
+)
+
+// SearchModels finds models that match every word of the query, with small typos allowed, and ranks name matches above
+// tag or feature matches, which are ranked above description matches.
+func SearchModels(ctx context.Context, client ModelsClient, input *SearchModelsInput) (*SearchModelsOutput, error) {
+	modelIDs, err := searchCandidates(ctx, client, input.Tags)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := readModelDetails(ctx, client, modelIDs, input.Concurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := searchWords(input.Query)
+	out := &SearchModelsOutput{
+		Results:       []ModelSearchResult{},
+		TagFacets:     map[string]int{},
+		FeatureFacets: map[string]int{},
+	}
+	for _, details := range candidates {
+		if !hasCatalogTags(details, input.Tags) || !hasFeatures(details, input.Features) {
+			continue
+		}
+		score, matched := scoreModel(details, terms)
+		if !matched {
+			continue
+		}
+		out.Results = append(out.Results, ModelSearchResult{Details: details, Score: score})
+		for _, tag := range details.Tags {
+			out.TagFacets[tag.Name]++
+		}
+		for _, feature := range details.Features {
+			out.FeatureFacets[feature.Name]++
+		}
+	}
+
+	sort.SliceStable(out.Results, func(i, j int) bool {
+		if out.Results[i].Score != out.Results[j].Score {
+			return out.Results[i].Score > out.Results[j].Score
+		}
+		return out.Results[i].Details.Name < out.Results[j].Details.Name
+	})
+	if input.Limit > 0 && len(out.Results) > input.Limit {
+		out.Results = out.Results[:input.Limit]
+	}
+	return out, nil
+}
+
+// searchCandidates lists the models to consider; when tags are provided only models with the first tag are read
+func searchCandidates(ctx context.Context, client ModelsClient, tags []string) ([]string, error) {
+	if len(tags) > 0 {
+		allTags, err := client.GetTags(ctx)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to read tags")
+		}
+		tagID := ""
+		for _, tag := range allTags.Tags {
+			if tag.Identifier == tags[0] || strings.EqualFold(tag.Name, tags[0]) {
+				tagID = tag.Identifier
+				break
+			}
+		}
+		if tagID == "" {
+			return []string{}, nil
+		}
+		tagModels, err := client.GetTagModels(ctx, &GetTagModelsInput{TagIDs: []string{tagID}})
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to read the models of tag %s", tagID)
+		}
+		ids := []string{}
+		for _, m := range tagModels.Models {
+			ids = append(ids, m.Identifier)
+		}
+		return ids, nil
+	}
+
+	summaries, err := NewListModelsPager(client, &ListModelsInput{}).ListAll(ctx, 0)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list models")
+	}
+	ids := []string{}
+	for _, summary := range summaries {
+		ids = append(ids, summary.ID)
+	}
+	return ids, nil
+}
+
+func readModelDetails(ctx context.Context, client ModelsClient, modelIDs []string, concurrency int) ([]model.ModelDetails, error) {
+	if concurrency <= 0 {
+		concurrency = defaultSearchDetails
+	}
+	details := make([]model.ModelDetails, len(modelIDs))
+	err := forEachConcurrently(ctx, len(modelIDs), concurrency, func(ctx context.Context, i int) error {
+		out, err := client.GetModelDetails(ctx, &GetModelDetailsInput{ModelID: modelIDs[i]})
+		if err != nil {
+			return errors.WithMessagef(err, "failed to read model %s", modelIDs[i])
+		}
+		details[i] = out.Details
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+func hasFeatures(details model.ModelDetails, features []string) bool {
+	for _, wanted := range features {
+		found := false
+		for _, feature := range details.Features {
+			if feature.Identifier == wanted || strings.EqualFold(feature.Name, wanted) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// scoreModel adds up the best weighted similarity of each term; every term must match somewhere
+func scoreModel(details model.ModelDetails, terms []string) (float64, bool) {
+	tagsAndFeatures := []string{}
+	for _, tag := range details.Tags {
+		tagsAndFeatures = append(tagsAndFeatures, searchWords(tag.Name)...)
+	}
+	for _, feature := range details.Features {
+		tagsAndFeatures = append(tagsAndFeatures, searchWords(feature.Name)...)
+	}
+	fields := []struct {
+		words  []string
+		weight float64
+	}{
+		{searchWords(details.Name), searchWeightName},
+		{tagsAndFeatures, searchWeightTagFeature},
+		{searchWords(details.Description), searchWeightDescription},
+	}
+
+	total := 0.0
+	for _, term := range terms {
+		best := 0.0
+		for _, field := range fields {
+			for _, word := range field.words {
+				if score := wordSimilarity(term, word) * field.weight; score > best {
+					best = score
+				}
+			}
+		}
+		if best == 0 {
+			return 0, false
+		}
+		total += best
+	}
+	return total, true
+}
+
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// wordSimilarity is 1 for the same word, slightly less for a prefix, and lower with each typo.  Words that need more
+// edits than their length allows are not similar.
+func wordSimilarity(term string, word string) float64 {
+	if term == word {
+		return 1
+	}
+	if len(term) >= 3 && strings.HasPrefix(word, term) {
+		return 0.9
+	}
+	allowed := 0
+	switch {
+	case len(term) > 7:
+		allowed = 2
+	case len(term) > 3:
+		allowed = 1
+	}
+	distance := levenshtein(term, word)
+	if distance > allowed {
+		return 0
+	}
+	return 0.8 - 0.2*float64(distance-1)
+}
+
+func levenshtein(a string, b string) int {
+	ar, br := []rune(a), []rune(b)
+	previous := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(br)]
+}
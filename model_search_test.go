@@ -0,0 +1,136 @@
+package modzy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestSearchModelsTypos(t *testing.T) {
+	client := NewCatalogModelsClient(catalogTestSnapshot())
+
+	out, err := SearchModels(context.TODO(), client, &SearchModelsInput{Query: "sentimnet analysys"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(out.Results) != 1 || out.Results[0].Details.ModelID != "sentiment" {
+		t.Fatalf("Expected only the sentiment model, got %+v", out.Results)
+	}
+	if out.TagFacets["Natural Language"] != 1 {
+		t.Errorf("Expected tag facets to count the match, got %v", out.TagFacets)
+	}
+}
+
+func TestSearchModelsRanking(t *testing.T) {
+	client := NewCatalogModelsClient(catalogTestSnapshot())
+
+	out, err := SearchModels(context.TODO(), client, &SearchModelsInput{Query: "langage"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// the name match ranks above the tag match
+	if len(out.Results) != 2 || out.Results[0].Details.ModelID != "language" || out.Results[1].Details.ModelID != "sentiment" {
+		t.Fatalf("Expected language then sentiment, got %+v", out.Results)
+	}
+	if out.Results[0].Score <= out.Results[1].Score {
+		t.Errorf("Expected descending scores, got %v and %v", out.Results[0].Score, out.Results[1].Score)
+	}
+
+	out, err = SearchModels(context.TODO(), client, &SearchModelsInput{Query: "langage", Limit: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(out.Results) != 1 || out.TagFacets["Natural Language"] != 2 {
+		t.Errorf("Expected the limit to apply after the facets, got %+v %v", out.Results, out.TagFacets)
+	}
+}
+
+func TestSearchModelsFacets(t *testing.T) {
+	client := NewCatalogModelsClient(catalogTestSnapshot())
+
+	out, err := SearchModels(context.TODO(), client, &SearchModelsInput{Tags: []string{"natural language"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(out.Results) != 2 || out.Results[0].Details.ModelID != "language" {
+		t.Errorf("Expected the two nlp models ordered by name, got %+v", out.Results)
+	}
+
+	out, err = SearchModels(context.TODO(), client, &SearchModelsInput{Query: "text", Features: []string{"explainable"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(out.Results) != 1 || out.Results[0].Details.ModelID != "objects" || out.FeatureFacets["Explainable"] != 1 {
+		t.Errorf("Expected only the explainable model, got %+v", out.Results)
+	}
+
+	out, err = SearchModels(context.TODO(), client, &SearchModelsInput{Tags: []string{"missing"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(out.Results) != 0 {
+		t.Errorf("Expected no results for an unknown tag, got %+v", out.Results)
+	}
+}
+
+func TestSearchModelsDetailsError(t *testing.T) {
+	catalog := NewCatalogModelsClient(catalogTestSnapshot())
+	client := &ModelsClientFake{
+		ListModelsFunc: catalog.ListModels,
+		GetModelDetailsFunc: func(ctx context.Context, input *GetModelDetailsInput) (*GetModelDetailsOutput, error) {
+			if input.ModelID == "objects" {
+				return nil, fmt.Errorf("nope")
+			}
+			return catalog.GetModelDetails(ctx, input)
+		},
+	}
+
+	_, err := SearchModels(context.TODO(), client, &SearchModelsInput{Query: "text", Concurrency: 1})
+	if err == nil || errors.Cause(err).Error() != "nope" {
+		t.Errorf("Expected the details error, got %v", err)
+	}
+}
+
+func TestSearchModelsCanceled(t *testing.T) {
+	catalog := NewCatalogModelsClient(catalogTestSnapshot())
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	client := &ModelsClientFake{
+		ListModelsFunc: catalog.ListModels,
+		GetModelDetailsFunc: func(ctx context.Context, input *GetModelDetailsInput) (*GetModelDetailsOutput, error) {
+			cancel()
+			return catalog.GetModelDetails(ctx, input)
+		},
+	}
+
+	out, err := SearchModels(ctx, client, &SearchModelsInput{Concurrency: 1})
+	if err != context.Canceled {
+		t.Errorf("Expected the context error instead of the skipped models, got %v %+v", err, out)
+	}
+}
+
+func TestWordSimilarity(t *testing.T) {
+	cases := []struct {
+		term    string
+		word    string
+		similar bool
+	}{
+		{"text", "text", true},
+		{"sent", "sentiment", true},
+		{"tetx", "text", false},
+		{"txet", "text", false},
+		{"objct", "object", true},
+		{"detecton", "detection", true},
+		{"detectoin", "detection", true},
+		{"dtecton", "detection", false},
+		{"cat", "car", false},
+		{"ab", "abc", false},
+	}
+	for _, c := range cases {
+		if got := wordSimilarity(c.term, c.word) > 0; got != c.similar {
+			t.Errorf("Expected %q and %q similar=%v", c.term, c.word, c.similar)
+		}
+	}
+}
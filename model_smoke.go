@@ -0,0 +1,266 @@
+package modzy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const defaultSmokeTestPollInterval = 2 * time.Second
+
+type SmokeTestModelVersionInput struct {
+	ModelID string
+	Version string
+	// CompareValues also compares the values of the outputs, not only their keys and types
+	CompareValues bool
+	// Tolerance is the largest absolute difference allowed between two numbers when comparing values
+	Tolerance float64
+	// PollInterval is how often the job is checked; defaults to 2 seconds
+	PollInterval time.Duration
+}
+
+// SmokeTestMismatch is a difference between the actual output and the sample output
+type SmokeTestMismatch struct {
+	// Path locates the difference, such as "my-input/results.json/classes[0]/score"
+	Path     string `json:"path"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	Reason   string `json:"reason"`
+}
+
+type SmokeTestReport struct {
+	ModelID       string              `json:"modelId"`
+	Version       string              `json:"version"`
+	JobIdentifier string              `json:"jobIdentifier"`
+	Passed        bool                `json:"passed"`
+	Mismatches    []SmokeTestMismatch `json:"mismatches"`
+	Duration      time.Duration       `json:"duration"`
+}
+
+// sampleJobInput is the shape of the sample input of a model version, which is a job request
+type sampleJobInput struct {
+	Input struct {
+		Type    string                       `json:"type"`
+		Sources map[string]map[string]string `json:"sources"`
+	} `json:"input"`
+}
+
+// SmokeTestModelVersion submits the sample input of a model version as a job and compares what the model returns with
+// its sample output.  The sample output may hold the results of every input under "results", or the outputs shared by
+// every input.  Keys and types must match, along with values if requested, while outputs that the sample does not
+// mention are ignored.  An error is only returned if the test could not be run; a model that produces the wrong output
+// results in a report that did not pass.
+func SmokeTestModelVersion(ctx context.Context, client Client, input *SmokeTestModelVersionInput) (*SmokeTestReport, error) {
+	started := time.Now()
+	pollInterval := input.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultSmokeTestPollInterval
+	}
+
+	sampleInput, err := client.Models().GetModelVersionSampleInput(ctx, &GetModelVersionSampleInputInput{ModelID: input.ModelID, Version: input.Version})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read the sample input")
+	}
+	var request sampleJobInput
+	if err := json.Unmarshal([]byte(sampleInput.Sample), &request); err != nil {
+		return nil, errors.WithMessage(err, "failed to parse the sample input")
+	}
+	if len(request.Input.Sources) == 0 {
+		return nil, errors.New("the sample input does not have any sources")
+	}
+	sampleOutput, err := client.Models().GetModelVersionSampleOutput(ctx, &GetModelVersionSampleOutputInput{ModelID: input.ModelID, Version: input.Version})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read the sample output")
+	}
+	var expected interface{}
+	if err := json.Unmarshal([]byte(sampleOutput.Sample), &expected); err != nil {
+		return nil, errors.WithMessage(err, "failed to parse the sample output")
+	}
+
+	submitted, err := submitSample(ctx, client.Jobs(), input, request)
+	if err != nil {
+		return nil, err
+	}
+	report := &SmokeTestReport{
+		ModelID:       input.ModelID,
+		Version:       input.Version,
+		JobIdentifier: submitted.Response.JobIdentifier,
+		Mismatches:    []SmokeTestMismatch{},
+	}
+
+	details, err := submitted.WaitForCompletion(ctx, pollInterval)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed waiting for the sample job")
+	}
+	if details.Details.Status != JobStatusCompleted {
+		report.Mismatches = append(report.Mismatches, SmokeTestMismatch{Expected: JobStatusCompleted, Actual: details.Details.Status, Reason: "job did not complete"})
+	} else {
+		results, err := submitted.GetResults(ctx)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to read the sample job results")
+		}
+		for _, inputKey := range sortedInputKeys(request.Input.Sources) {
+			if failure, failed := results.Results.Failures[inputKey]; failed {
+				report.Mismatches = append(report.Mismatches, SmokeTestMismatch{Path: inputKey, Actual: failure.Error, Reason: "input failed"})
+				continue
+			}
+			result, ok := results.Results.Results[inputKey]
+			if !ok {
+				report.Mismatches = append(report.Mismatches, SmokeTestMismatch{Path: inputKey, Reason: "input has no results"})
+				continue
+			}
+			comparer := &smokeComparer{input: input}
+			comparer.compare(inputKey, expectedOutputs(expected, inputKey, result.Data), result.Data)
+			report.Mismatches = append(report.Mismatches, comparer.mismatches...)
+		}
+	}
+
+	report.Passed = len(report.Mismatches) == 0
+	report.Duration = time.Since(started)
+	return report, nil
+}
+
+func submitSample(ctx context.Context, jobs JobsClient, input *SmokeTestModelVersionInput, request sampleJobInput) (*SubmitJobOutput, error) {
+	switch request.Input.Type {
+	case "text":
+		inputs := map[string]TextInputItem{}
+		for k, v := range request.Input.Sources {
+			inputs[k] = TextInputItem(v)
+		}
+		out, err := jobs.SubmitJobText(ctx, &SubmitJobTextInput{ModelIdentifier: input.ModelID, ModelVersion: input.Version, Inputs: inputs})
+		return out, errors.WithMessage(err, "failed to submit the sample input")
+	case "embedded":
+		inputs := map[string]EmbeddedInputItem{}
+		for k, v := range request.Input.Sources {
+			item := EmbeddedInputItem{}
+			for itemName, encoded := range v {
+				item[itemName] = URIEncodedString(encoded)
+			}
+			inputs[k] = item
+		}
+		out, err := jobs.SubmitJobEmbedded(ctx, &SubmitJobEmbeddedInput{ModelIdentifier: input.ModelID, ModelVersion: input.Version, Inputs: inputs})
+		return out, errors.WithMessage(err, "failed to submit the sample input")
+	}
+	return nil, errors.WithMessagef(ErrNotImplemented, "sample inputs of type %q can not be submitted", request.Input.Type)
+}
+
+// expectedOutputs finds the outputs of an input within the sample output.  If the sample is only the content of an
+// output and the input has a single output, it is compared with that output.
+func expectedOutputs(sample interface{}, inputKey string, actual map[string]interface{}) interface{} {
+	sampleMap, ok := sample.(map[string]interface{})
+	if !ok {
+		return wrapSingleOutput(sample, actual)
+	}
+	if results, ok := sampleMap["results"].(map[string]interface{}); ok {
+		if forInput, ok := results[inputKey].(map[string]interface{}); ok {
+			outputs := map[string]interface{}{}
+			for k, v := range forInput {
+				outputs[k] = v
+			}
+			for _, known := range []string{"status", "engine", "error", "startTime", "updateTime", "endTime", "elapsedTime"} {
+				delete(outputs, known)
+			}
+			return outputs
+		}
+	}
+	for k := range sampleMap {
+		if _, ok := actual[k]; ok {
+			return sampleMap
+		}
+	}
+	return wrapSingleOutput(sample, actual)
+}
+
+func wrapSingleOutput(sample interface{}, actual map[string]interface{}) interface{} {
+	if len(actual) != 1 {
+		return sample
+	}
+	for name := range actual {
+		return map[string]interface{}{name: sample}
+	}
+	return sample
+}
+
+type smokeComparer struct {
+	input      *SmokeTestModelVersionInput
+	mismatches []SmokeTestMismatch
+}
+
+func (c *smokeComparer) mismatch(path string, expected interface{}, actual interface{}, reason string) {
+	c.mismatches = append(c.mismatches, SmokeTestMismatch{Path: path, Expected: describeValue(expected), Actual: describeValue(actual), Reason: reason})
+}
+
+func (c *smokeComparer) compare(path string, expected interface{}, actual interface{}) {
+	if expected == nil {
+		// a null sample value says nothing about the output
+		return
+	}
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			c.mismatch(path, expected, actual, "expected an object")
+			return
+		}
+		keys := make([]string, 0, len(e))
+		for k := range e {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			value, found := a[k]
+			if !found && e[k] != nil {
+				c.mismatch(path+"/"+k, e[k], nil, "missing key")
+				continue
+			}
+			c.compare(path+"/"+k, e[k], value)
+		}
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok {
+			c.mismatch(path, expected, actual, "expected an array")
+			return
+		}
+		if c.input.CompareValues && len(a) != len(e) {
+			c.mismatch(path, len(e), len(a), "array length differs")
+			return
+		}
+		for i := 0; i < len(e) && i < len(a); i++ {
+			c.compare(fmt.Sprintf("%s[%d]", path, i), e[i], a[i])
+		}
+	case float64:
+		a, ok := actual.(float64)
+		if !ok {
+			c.mismatch(path, expected, actual, "expected a number")
+			return
+		}
+		if c.input.CompareValues && math.Abs(a-e) > c.input.Tolerance {
+			c.mismatch(path, expected, actual, "number differs by more than the tolerance")
+		}
+	default:
+		// strings and booleans
+		if fmt.Sprintf("%T", expected) != fmt.Sprintf("%T", actual) {
+			c.mismatch(path, expected, actual, fmt.Sprintf("expected a %T", expected))
+			return
+		}
+		if c.input.CompareValues && expected != actual {
+			c.mismatch(path, expected, actual, "value differs")
+		}
+	}
+}
+
+func describeValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
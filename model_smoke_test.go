@@ -0,0 +1,237 @@
+package modzy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+func TestSmokeTestModelVersionPasses(t *testing.T) {
+	submitted := &SubmitJobTextInput{}
+	client := &ClientFake{}
+	models := &ModelsClientFake{
+		GetModelVersionSampleInputFunc: func(ctx context.Context, input *GetModelVersionSampleInputInput) (*GetModelVersionSampleInputOutput, error) {
+			return &GetModelVersionSampleInputOutput{
+				Sample: `{"model":{"identifier":"abc","version":"1.0.0"},"input":{"type":"text","sources":{"in":{"input.txt":"hello"}}}}`,
+			}, nil
+		},
+		GetModelVersionSampleOutputFunc: func(ctx context.Context, input *GetModelVersionSampleOutputInput) (*GetModelVersionSampleOutputOutput, error) {
+			return &GetModelVersionSampleOutputOutput{
+				Sample: `{"results":{"in":{"status":"SUCCESSFUL","results.json":{"label":"greeting","score":0.9,"extra":null}}}}`,
+			}, nil
+		},
+	}
+	jobs := &JobsClientFake{
+		SubmitJobTextFunc: func(ctx context.Context, input *SubmitJobTextInput) (*SubmitJobTextOutput, error) {
+			*submitted = *input
+			return &SubmitJobTextOutput{
+				Response:   model.SubmitJobResponse{JobIdentifier: "job"},
+				JobActions: NewJobActions(client, "job"),
+			}, nil
+		},
+		WaitForJobCompletionFunc: func(ctx context.Context, input *WaitForJobCompletionInput, pollInterval time.Duration) (*GetJobDetailsOutput, error) {
+			return &GetJobDetailsOutput{Details: model.JobDetails{JobIdentifier: input.JobIdentifier, Status: JobStatusCompleted}}, nil
+		},
+		GetJobResultsFunc: func(ctx context.Context, input *GetJobResultsInput) (*GetJobResultsOutput, error) {
+			var out model.JobResults
+			if err := json.Unmarshal([]byte(`{"results":{"in":{"status":"SUCCESSFUL","results.json":{"label":"greeting","score":0.91,"more":[1]}}}}`), &out); err != nil {
+				t.Fatalf("bad results: %v", err)
+			}
+			return &GetJobResultsOutput{Results: out}, nil
+		},
+	}
+	client.ModelsFunc = func() ModelsClient { return models }
+	client.JobsFunc = func() JobsClient { return jobs }
+
+	report, err := SmokeTestModelVersion(context.TODO(), client, &SmokeTestModelVersionInput{
+		ModelID:       "abc",
+		Version:       "1.0.0",
+		CompareValues: true,
+		Tolerance:     0.05,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !report.Passed || report.JobIdentifier != "job" {
+		t.Errorf("Expected the smoke test to pass, got %+v", report)
+	}
+	if submitted.Inputs["in"]["input.txt"] != "hello" || submitted.ModelIdentifier != "abc" {
+		t.Errorf("Expected the sample input to be submitted, got %+v", submitted)
+	}
+}
+
+func TestSmokeTestModelVersionMismatches(t *testing.T) {
+	client := &ClientFake{}
+	models := &ModelsClientFake{
+		GetModelVersionSampleInputFunc: func(ctx context.Context, input *GetModelVersionSampleInputInput) (*GetModelVersionSampleInputOutput, error) {
+			return &GetModelVersionSampleInputOutput{
+				Sample: `{"model":{"identifier":"abc","version":"1.0.0"},"input":{"type":"text","sources":{"in":{"input.txt":"hello"}}}}`,
+			}, nil
+		},
+		GetModelVersionSampleOutputFunc: func(ctx context.Context, input *GetModelVersionSampleOutputInput) (*GetModelVersionSampleOutputOutput, error) {
+			return &GetModelVersionSampleOutputOutput{Sample: `{"label":"greeting","score":0.9,"classes":[{"name":"a"}]}`}, nil
+		},
+	}
+	jobs := &JobsClientFake{
+		SubmitJobTextFunc: func(ctx context.Context, input *SubmitJobTextInput) (*SubmitJobTextOutput, error) {
+			return &SubmitJobTextOutput{
+				Response:   model.SubmitJobResponse{JobIdentifier: "job"},
+				JobActions: NewJobActions(client, "job"),
+			}, nil
+		},
+		WaitForJobCompletionFunc: func(ctx context.Context, input *WaitForJobCompletionInput, pollInterval time.Duration) (*GetJobDetailsOutput, error) {
+			return &GetJobDetailsOutput{Details: model.JobDetails{JobIdentifier: input.JobIdentifier, Status: JobStatusCompleted}}, nil
+		},
+		GetJobResultsFunc: func(ctx context.Context, input *GetJobResultsInput) (*GetJobResultsOutput, error) {
+			var out model.JobResults
+			if err := json.Unmarshal([]byte(`{"results":{"in":{"status":"SUCCESSFUL","results.json":{"label":"farewell","score":"high","classes":[{}]}}}}`), &out); err != nil {
+				t.Fatalf("bad results: %v", err)
+			}
+			return &GetJobResultsOutput{Results: out}, nil
+		},
+	}
+	client.ModelsFunc = func() ModelsClient { return models }
+	client.JobsFunc = func() JobsClient { return jobs }
+
+	report, err := SmokeTestModelVersion(context.TODO(), client, &SmokeTestModelVersionInput{ModelID: "abc", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// without comparing values only the structure differs
+	if report.Passed || len(report.Mismatches) != 2 {
+		t.Fatalf("Expected two mismatches, got %+v", report.Mismatches)
+	}
+	if report.Mismatches[0].Path != "in/results.json/classes[0]/name" || report.Mismatches[0].Reason != "missing key" {
+		t.Errorf("Unexpected first mismatch %+v", report.Mismatches[0])
+	}
+	if report.Mismatches[1].Path != "in/results.json/score" || report.Mismatches[1].Reason != "expected a number" {
+		t.Errorf("Unexpected second mismatch %+v", report.Mismatches[1])
+	}
+
+	report, _ = SmokeTestModelVersion(context.TODO(), client, &SmokeTestModelVersionInput{ModelID: "abc", Version: "1.0.0", CompareValues: true})
+	if len(report.Mismatches) != 3 || report.Mismatches[1].Path != "in/results.json/label" {
+		t.Errorf("Expected the label value to differ as well, got %+v", report.Mismatches)
+	}
+}
+
+func TestSmokeTestModelVersionJobFailed(t *testing.T) {
+	status := JobStatusCompleted
+	client := &ClientFake{}
+	models := &ModelsClientFake{
+		GetModelVersionSampleInputFunc: func(ctx context.Context, input *GetModelVersionSampleInputInput) (*GetModelVersionSampleInputOutput, error) {
+			return &GetModelVersionSampleInputOutput{
+				Sample: `{"model":{"identifier":"abc","version":"1.0.0"},"input":{"type":"text","sources":{"in":{"input.txt":"hello"}}}}`,
+			}, nil
+		},
+		GetModelVersionSampleOutputFunc: func(ctx context.Context, input *GetModelVersionSampleOutputInput) (*GetModelVersionSampleOutputOutput, error) {
+			return &GetModelVersionSampleOutputOutput{Sample: `{}`}, nil
+		},
+	}
+	jobs := &JobsClientFake{
+		SubmitJobTextFunc: func(ctx context.Context, input *SubmitJobTextInput) (*SubmitJobTextOutput, error) {
+			return &SubmitJobTextOutput{
+				Response:   model.SubmitJobResponse{JobIdentifier: "job"},
+				JobActions: NewJobActions(client, "job"),
+			}, nil
+		},
+		WaitForJobCompletionFunc: func(ctx context.Context, input *WaitForJobCompletionInput, pollInterval time.Duration) (*GetJobDetailsOutput, error) {
+			return &GetJobDetailsOutput{Details: model.JobDetails{JobIdentifier: input.JobIdentifier, Status: status}}, nil
+		},
+		GetJobResultsFunc: func(ctx context.Context, input *GetJobResultsInput) (*GetJobResultsOutput, error) {
+			var out model.JobResults
+			if err := json.Unmarshal([]byte(`{"failures":{"in":{"status":"FAILED","error":"out of memory"}}}`), &out); err != nil {
+				t.Fatalf("bad results: %v", err)
+			}
+			return &GetJobResultsOutput{Results: out}, nil
+		},
+	}
+	client.ModelsFunc = func() ModelsClient { return models }
+	client.JobsFunc = func() JobsClient { return jobs }
+
+	report, err := SmokeTestModelVersion(context.TODO(), client, &SmokeTestModelVersionInput{ModelID: "abc", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Passed || len(report.Mismatches) != 1 || report.Mismatches[0].Actual != "out of memory" {
+		t.Errorf("Expected the failed input to be reported, got %+v", report.Mismatches)
+	}
+
+	status = JobStatusTimedOut
+	report, _ = SmokeTestModelVersion(context.TODO(), client, &SmokeTestModelVersionInput{ModelID: "abc", Version: "1.0.0"})
+	if report.Passed || report.Mismatches[0].Reason != "job did not complete" {
+		t.Errorf("Expected the timed out job to be reported, got %+v", report.Mismatches)
+	}
+}
+
+func TestSmokeTestModelVersionEmbedded(t *testing.T) {
+	client := &ClientFake{}
+	models := &ModelsClientFake{
+		GetModelVersionSampleInputFunc: func(ctx context.Context, input *GetModelVersionSampleInputInput) (*GetModelVersionSampleInputOutput, error) {
+			return &GetModelVersionSampleInputOutput{
+				Sample: `{"input":{"type":"embedded","sources":{"in":{"image.png":"data:image/png;base64,AAAA"}}}}`,
+			}, nil
+		},
+		GetModelVersionSampleOutputFunc: func(ctx context.Context, input *GetModelVersionSampleOutputInput) (*GetModelVersionSampleOutputOutput, error) {
+			return &GetModelVersionSampleOutputOutput{Sample: `{"label":"cat"}`}, nil
+		},
+	}
+	jobs := &JobsClientFake{
+		SubmitJobEmbeddedFunc: func(ctx context.Context, input *SubmitJobEmbeddedInput) (*SubmitJobEmbeddedOutput, error) {
+			encoded, _ := input.Inputs["in"]["image.png"]()
+			if encoded == nil {
+				t.Errorf("embedded input not passed through")
+			}
+			return &SubmitJobEmbeddedOutput{
+				Response:   model.SubmitJobResponse{JobIdentifier: "job"},
+				JobActions: NewJobActions(client, "job"),
+			}, nil
+		},
+		WaitForJobCompletionFunc: func(ctx context.Context, input *WaitForJobCompletionInput, pollInterval time.Duration) (*GetJobDetailsOutput, error) {
+			return &GetJobDetailsOutput{Details: model.JobDetails{JobIdentifier: input.JobIdentifier, Status: JobStatusCompleted}}, nil
+		},
+		GetJobResultsFunc: func(ctx context.Context, input *GetJobResultsInput) (*GetJobResultsOutput, error) {
+			var out model.JobResults
+			if err := json.Unmarshal([]byte(`{"results":{"in":{"results.json":{"label":"dog"}}}}`), &out); err != nil {
+				t.Fatalf("bad results: %v", err)
+			}
+			return &GetJobResultsOutput{Results: out}, nil
+		},
+	}
+	client.ModelsFunc = func() ModelsClient { return models }
+	client.JobsFunc = func() JobsClient { return jobs }
+
+	report, err := SmokeTestModelVersion(context.TODO(), client, &SmokeTestModelVersionInput{ModelID: "abc", Version: "1.0.0"})
+	if err != nil || !report.Passed {
+		t.Errorf("Expected the embedded sample to pass, got %v %+v", err, report)
+	}
+}
+
+func TestSmokeTestModelVersionUnsupportedSample(t *testing.T) {
+	sample := `{"input":{"type":"aws-s3","sources":{"in":{"a":"b"}}}}`
+	client := &ClientFake{
+		ModelsFunc: func() ModelsClient {
+			return &ModelsClientFake{
+				GetModelVersionSampleInputFunc: func(ctx context.Context, input *GetModelVersionSampleInputInput) (*GetModelVersionSampleInputOutput, error) {
+					return &GetModelVersionSampleInputOutput{Sample: sample}, nil
+				},
+				GetModelVersionSampleOutputFunc: func(ctx context.Context, input *GetModelVersionSampleOutputInput) (*GetModelVersionSampleOutputOutput, error) {
+					return &GetModelVersionSampleOutputOutput{Sample: `{}`}, nil
+				},
+			}
+		},
+		JobsFunc: func() JobsClient { return &JobsClientFake{} },
+	}
+	_, err := SmokeTestModelVersion(context.TODO(), client, &SmokeTestModelVersionInput{ModelID: "abc", Version: "1.0.0"})
+	if errors.Cause(err) != ErrNotImplemented {
+		t.Errorf("Expected ErrNotImplemented, got %v", err)
+	}
+
+	sample = `"just text"`
+	if _, err := SmokeTestModelVersion(context.TODO(), client, &SmokeTestModelVersionInput{ModelID: "abc", Version: "1.0.0"}); err == nil {
+		t.Errorf("Expected an error for a sample input that is not a job request")
+	}
+}
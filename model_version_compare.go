@@ -0,0 +1,255 @@
+package modzy
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+// ModelVersionChangeImpact classifies whether a change can break existing callers
+type ModelVersionChangeImpact string
+
+const (
+	ModelVersionChangeBreaking    ModelVersionChangeImpact = "BREAKING"
+	ModelVersionChangeNonBreaking ModelVersionChangeImpact = "NON_BREAKING"
+)
+
+// ModelVersionChange is a single difference between two model versions
+type ModelVersionChange struct {
+	// Field identifies what changed, such as "inputs[input.txt].acceptedMediaTypes"
+	Field       string                   `json:"field"`
+	Old         string                   `json:"old"`
+	New         string                   `json:"new"`
+	Impact      ModelVersionChangeImpact `json:"impact"`
+	Description string                   `json:"description"`
+}
+
+type ModelVersionComparison struct {
+	ModelID    string               `json:"modelId"`
+	OldVersion string               `json:"oldVersion"`
+	NewVersion string               `json:"newVersion"`
+	Changes    []ModelVersionChange `json:"changes"`
+}
+
+// Breaking is true if the new version is not a drop-in replacement for the old one
+func (c *ModelVersionComparison) Breaking() bool {
+	return len(c.BreakingChanges()) > 0
+}
+
+// BreakingChanges returns the changes that can break existing callers
+func (c *ModelVersionComparison) BreakingChanges() []ModelVersionChange {
+	breaking := []ModelVersionChange{}
+	for _, change := range c.Changes {
+		if change.Impact == ModelVersionChangeBreaking {
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
+
+// CompareModelVersions reports the differences between two versions of a model.  A change is breaking if a caller of
+// the old version could fail against the new one: inputs that are added or removed, media types or sizes that are no
+// longer accepted, outputs that are removed or change type, shorter timeouts, or a version that is no longer active
+// and available.
+func CompareModelVersions(oldVersion model.ModelVersionDetails, newVersion model.ModelVersionDetails) *ModelVersionComparison {
+	modelID := newVersion.ModelID
+	if modelID == "" {
+		modelID = oldVersion.ModelID
+	}
+	c := &versionComparer{out: &ModelVersionComparison{
+		ModelID:    modelID,
+		OldVersion: oldVersion.Version,
+		NewVersion: newVersion.Version,
+		Changes:    []ModelVersionChange{},
+	}}
+	c.compareInputs(oldVersion.Inputs, newVersion.Inputs)
+	c.compareOutputs(oldVersion.Outputs, newVersion.Outputs)
+	c.lowerIsBreaking("timeout.status", oldVersion.Timeout.Status, newVersion.Timeout.Status, "the status timeout")
+	c.lowerIsBreaking("timeout.run", oldVersion.Timeout.Run, newVersion.Timeout.Run, "the run timeout")
+	c.compareInt("processing.minimumParallelCapacity", oldVersion.Processing.MinimumParallelCapacity, newVersion.Processing.MinimumParallelCapacity, "the minimum parallel capacity")
+	c.compareInt("processing.maximumParallelCapacity", oldVersion.Processing.MaximumParallelCapacity, newVersion.Processing.MaximumParallelCapacity, "the maximum parallel capacity")
+	c.compareStatistics(oldVersion.Statistics, newVersion.Statistics)
+	c.compareState("isActive", oldVersion.IsActive, newVersion.IsActive, "active")
+	c.compareState("isAvailable", oldVersion.IsAvailable, newVersion.IsAvailable, "available")
+	return c.out
+}
+
+type versionComparer struct {
+	out *ModelVersionComparison
+}
+
+func (c *versionComparer) add(field string, o string, n string, impact ModelVersionChangeImpact, description string) {
+	c.out.Changes = append(c.out.Changes, ModelVersionChange{Field: field, Old: o, New: n, Impact: impact, Description: description})
+}
+
+func (c *versionComparer) compareInputs(oldVersion []model.ModelVersionDetailsInput, newVersion []model.ModelVersionDetailsInput) {
+	oldByName, newByName := map[string]model.ModelVersionDetailsInput{}, map[string]model.ModelVersionDetailsInput{}
+	for _, input := range oldVersion {
+		oldByName[input.Name] = input
+	}
+	for _, input := range newVersion {
+		newByName[input.Name] = input
+	}
+	for _, name := range unionKeys(oldByName, newByName) {
+		field := fmt.Sprintf("inputs[%s]", name)
+		o, inOld := oldByName[name]
+		n, inNew := newByName[name]
+		switch {
+		case !inNew:
+			c.add(field, name, "", ModelVersionChangeBreaking, "input was removed")
+		case !inOld:
+			// every input of a model is required, so existing callers do not send the new one
+			c.add(field, "", name, ModelVersionChangeBreaking, "input was added")
+		default:
+			removed, added := diffMediaTypes(o.AcceptedMediaTypes, n.AcceptedMediaTypes)
+			if len(removed) > 0 {
+				c.add(field+".acceptedMediaTypes", o.AcceptedMediaTypes, n.AcceptedMediaTypes, ModelVersionChangeBreaking, "no longer accepts "+strings.Join(removed, ", "))
+			} else if len(added) > 0 {
+				c.add(field+".acceptedMediaTypes", o.AcceptedMediaTypes, n.AcceptedMediaTypes, ModelVersionChangeNonBreaking, "now also accepts "+strings.Join(added, ", "))
+			}
+			c.smallerSizeIsBreaking(field+".maximumSize", o.MaximumSize, n.MaximumSize)
+			if o.Description != n.Description {
+				c.add(field+".description", o.Description, n.Description, ModelVersionChangeNonBreaking, "description changed")
+			}
+		}
+	}
+}
+
+func (c *versionComparer) compareOutputs(oldVersion []model.ModelVersionDetailsOutput, newVersion []model.ModelVersionDetailsOutput) {
+	oldByName, newByName := map[string]model.ModelVersionDetailsOutput{}, map[string]model.ModelVersionDetailsOutput{}
+	for _, output := range oldVersion {
+		oldByName[output.Name] = output
+	}
+	for _, output := range newVersion {
+		newByName[output.Name] = output
+	}
+	for _, name := range unionKeys(oldByName, newByName) {
+		field := fmt.Sprintf("outputs[%s]", name)
+		o, inOld := oldByName[name]
+		n, inNew := newByName[name]
+		switch {
+		case !inNew:
+			c.add(field, name, "", ModelVersionChangeBreaking, "output was removed")
+		case !inOld:
+			c.add(field, "", name, ModelVersionChangeNonBreaking, "output was added")
+		default:
+			if o.MediaType != n.MediaType {
+				c.add(field+".mediaType", o.MediaType, n.MediaType, ModelVersionChangeBreaking, "output media type changed")
+			}
+			if o.MaximumSize != n.MaximumSize {
+				c.add(field+".maximumSize", formatInt64(o.MaximumSize), formatInt64(n.MaximumSize), ModelVersionChangeNonBreaking, "output maximum size changed")
+			}
+			if o.Description != n.Description {
+				c.add(field+".description", o.Description, n.Description, ModelVersionChangeNonBreaking, "description changed")
+			}
+		}
+	}
+}
+
+func (c *versionComparer) compareStatistics(oldVersion []model.ModelVersionDetailsStatistic, newVersion []model.ModelVersionDetailsStatistic) {
+	oldByLabel, newByLabel := map[string]model.ModelVersionDetailsStatistic{}, map[string]model.ModelVersionDetailsStatistic{}
+	for _, statistic := range oldVersion {
+		oldByLabel[statistic.Label] = statistic
+	}
+	for _, statistic := range newVersion {
+		newByLabel[statistic.Label] = statistic
+	}
+	for _, label := range unionKeys(oldByLabel, newByLabel) {
+		field := fmt.Sprintf("statistics[%s]", label)
+		o, inOld := oldByLabel[label]
+		n, inNew := newByLabel[label]
+		switch {
+		case !inNew:
+			c.add(field, formatFloat(o.Value), "", ModelVersionChangeNonBreaking, "statistic was removed")
+		case !inOld:
+			c.add(field, "", formatFloat(n.Value), ModelVersionChangeNonBreaking, "statistic was added")
+		case o.Value != n.Value:
+			c.add(field, formatFloat(o.Value), formatFloat(n.Value), ModelVersionChangeNonBreaking, "statistic changed")
+		}
+	}
+}
+
+// smallerSizeIsBreaking treats a maximum size of zero as unlimited
+func (c *versionComparer) smallerSizeIsBreaking(field string, o int64, n int64) {
+	if o == n {
+		return
+	}
+	if n != 0 && (o == 0 || n < o) {
+		c.add(field, formatInt64(o), formatInt64(n), ModelVersionChangeBreaking, "maximum size was reduced")
+		return
+	}
+	c.add(field, formatInt64(o), formatInt64(n), ModelVersionChangeNonBreaking, "maximum size was increased")
+}
+
+func (c *versionComparer) lowerIsBreaking(field string, o int, n int, what string) {
+	if n < o {
+		c.add(field, strconv.Itoa(o), strconv.Itoa(n), ModelVersionChangeBreaking, what+" was reduced")
+	} else if n > o {
+		c.add(field, strconv.Itoa(o), strconv.Itoa(n), ModelVersionChangeNonBreaking, what+" was increased")
+	}
+}
+
+func (c *versionComparer) compareInt(field string, o int, n int, what string) {
+	if o != n {
+		c.add(field, strconv.Itoa(o), strconv.Itoa(n), ModelVersionChangeNonBreaking, what+" changed")
+	}
+}
+
+func (c *versionComparer) compareState(field string, o bool, n bool, what string) {
+	if o == n {
+		return
+	}
+	if n {
+		c.add(field, "false", "true", ModelVersionChangeNonBreaking, "version became "+what)
+		return
+	}
+	c.add(field, "true", "false", ModelVersionChangeBreaking, "version is no longer "+what)
+}
+
+// diffMediaTypes compares comma separated media type lists
+func diffMediaTypes(o string, n string) (removed []string, added []string) {
+	oldTypes, newTypes := mediaTypeSet(o), mediaTypeSet(n)
+	for _, t := range unionKeys(oldTypes, newTypes) {
+		if !newTypes[t] {
+			removed = append(removed, t)
+		} else if !oldTypes[t] {
+			added = append(added, t)
+		}
+	}
+	return removed, added
+}
+
+func mediaTypeSet(mediaTypes string) map[string]bool {
+	set := map[string]bool{}
+	for _, t := range strings.Split(mediaTypes, ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			set[t] = true
+		}
+	}
+	return set
+}
+
+func unionKeys[V any](a map[string]V, b map[string]V) []string {
+	keys := []string{}
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatInt64(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,150 @@
+package modzy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+func TestCompareModelVersionsSame(t *testing.T) {
+	version := model.ModelVersionDetails{
+		ModelID: "abc",
+		Version: "1.0.0",
+		Inputs: []model.ModelVersionDetailsInput{
+			{Name: "input.txt", AcceptedMediaTypes: "text/plain, application/json", MaximumSize: 1000},
+			{Name: "config.json", AcceptedMediaTypes: "application/json"},
+		},
+		Outputs: []model.ModelVersionDetailsOutput{
+			{Name: "results.json", MediaType: "application/json"},
+			{Name: "debug.txt", MediaType: "text/plain"},
+		},
+		Timeout:     model.ModelDetailsTimeout{Status: 60, Run: 600},
+		Processing:  model.ModelVersionDetailsProcessing{MinimumParallelCapacity: 0, MaximumParallelCapacity: 1},
+		Statistics:  []model.ModelVersionDetailsStatistic{{Label: "F1", Value: 0.8}},
+		IsActive:    true,
+		IsAvailable: true,
+	}
+	comparison := CompareModelVersions(version, version)
+	if len(comparison.Changes) != 0 || comparison.Breaking() {
+		t.Errorf("Expected no changes, got %+v", comparison.Changes)
+	}
+}
+
+func TestCompareModelVersionsNonBreaking(t *testing.T) {
+	oldVersion := model.ModelVersionDetails{
+		ModelID: "abc",
+		Version: "1.0.0",
+		Inputs: []model.ModelVersionDetailsInput{
+			{Name: "input.txt", AcceptedMediaTypes: "text/plain, application/json", MaximumSize: 1000},
+			{Name: "config.json", AcceptedMediaTypes: "application/json"},
+		},
+		Outputs: []model.ModelVersionDetailsOutput{
+			{Name: "results.json", MediaType: "application/json"},
+			{Name: "debug.txt", MediaType: "text/plain"},
+		},
+		Timeout:     model.ModelDetailsTimeout{Status: 60, Run: 600},
+		Processing:  model.ModelVersionDetailsProcessing{MinimumParallelCapacity: 0, MaximumParallelCapacity: 1},
+		Statistics:  []model.ModelVersionDetailsStatistic{{Label: "F1", Value: 0.8}},
+		IsActive:    true,
+		IsAvailable: true,
+	}
+	newVersion := oldVersion
+	newVersion.Version = "1.1.0"
+	newVersion.Inputs = []model.ModelVersionDetailsInput{
+		{Name: "input.txt", AcceptedMediaTypes: "text/plain,application/json,text/csv"},
+		{Name: "config.json", AcceptedMediaTypes: "application/json"},
+	}
+	newVersion.Outputs = []model.ModelVersionDetailsOutput{
+		{Name: "results.json", MediaType: "application/json"},
+		{Name: "debug.txt", MediaType: "text/plain"},
+		{Name: "extra.json", MediaType: "application/json"},
+	}
+	newVersion.Timeout.Run = 900
+	newVersion.Processing.MaximumParallelCapacity = 4
+	newVersion.Statistics = []model.ModelVersionDetailsStatistic{{Label: "F1", Value: 0.85}}
+
+	comparison := CompareModelVersions(oldVersion, newVersion)
+	if comparison.Breaking() {
+		t.Errorf("Expected no breaking changes, got %+v", comparison.BreakingChanges())
+	}
+	fields := []string{}
+	for _, change := range comparison.Changes {
+		fields = append(fields, change.Field)
+	}
+	expected := []string{
+		"inputs[input.txt].acceptedMediaTypes",
+		"inputs[input.txt].maximumSize",
+		"outputs[extra.json]",
+		"timeout.run",
+		"processing.maximumParallelCapacity",
+		"statistics[F1]",
+	}
+	if len(fields) != len(expected) {
+		t.Fatalf("Expected changes %v, got %v", expected, fields)
+	}
+	for i := range expected {
+		if fields[i] != expected[i] {
+			t.Errorf("Expected change %d to be %s, got %s", i, expected[i], fields[i])
+		}
+	}
+}
+
+func TestCompareModelVersionsBreaking(t *testing.T) {
+	oldVersion := model.ModelVersionDetails{
+		ModelID: "abc",
+		Version: "1.0.0",
+		Inputs: []model.ModelVersionDetailsInput{
+			{Name: "input.txt", AcceptedMediaTypes: "text/plain, application/json", MaximumSize: 1000},
+			{Name: "config.json", AcceptedMediaTypes: "application/json"},
+		},
+		Outputs: []model.ModelVersionDetailsOutput{
+			{Name: "results.json", MediaType: "application/json"},
+			{Name: "debug.txt", MediaType: "text/plain"},
+		},
+		Timeout:     model.ModelDetailsTimeout{Status: 60, Run: 600},
+		Processing:  model.ModelVersionDetailsProcessing{MinimumParallelCapacity: 0, MaximumParallelCapacity: 1},
+		Statistics:  []model.ModelVersionDetailsStatistic{{Label: "F1", Value: 0.8}},
+		IsActive:    true,
+		IsAvailable: true,
+	}
+	newVersion := oldVersion
+	newVersion.Version = "2.0.0"
+	newVersion.Inputs = []model.ModelVersionDetailsInput{
+		{Name: "input.txt", AcceptedMediaTypes: "text/plain", MaximumSize: 500},
+		{Name: "image.png", AcceptedMediaTypes: "image/png"},
+	}
+	newVersion.Outputs = []model.ModelVersionDetailsOutput{{Name: "results.json", MediaType: "text/plain"}}
+	newVersion.Timeout.Status = 30
+	newVersion.IsAvailable = false
+
+	comparison := CompareModelVersions(oldVersion, newVersion)
+	breaking := map[string]string{}
+	for _, change := range comparison.BreakingChanges() {
+		breaking[change.Field] = change.Description
+	}
+	expected := map[string]string{
+		"inputs[config.json]":                  "input was removed",
+		"inputs[image.png]":                    "input was added",
+		"inputs[input.txt].acceptedMediaTypes": "no longer accepts application/json",
+		"inputs[input.txt].maximumSize":        "maximum size was reduced",
+		"outputs[debug.txt]":                   "output was removed",
+		"outputs[results.json].mediaType":      "output media type changed",
+		"timeout.status":                       "the status timeout was reduced",
+		"isAvailable":                          "version is no longer available",
+	}
+	if len(breaking) != len(expected) {
+		t.Errorf("Expected %d breaking changes, got %v", len(expected), breaking)
+	}
+	for field, description := range expected {
+		if breaking[field] != description {
+			t.Errorf("Expected %s to be %q, got %q", field, description, breaking[field])
+		}
+	}
+
+	encoded, _ := json.Marshal(comparison)
+	var decoded ModelVersionComparison
+	if err := json.Unmarshal(encoded, &decoded); err != nil || decoded.OldVersion != "1.0.0" || decoded.NewVersion != "2.0.0" || !decoded.Breaking() {
+		t.Errorf("Expected the comparison to round trip as json, got %s", encoded)
+	}
+}
@@ -1,14 +1,23 @@
 package modzy
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
+// defaultContainerImageChunkSize is the size of each part when uploading a container image
+const defaultContainerImageChunkSize = 100 * 1024 * 1024
+
 type ModelsClient interface {
 	// ListModels lists all models.  This supports paging and filtering.
 	ListModels(ctx context.Context, input *ListModelsInput) (*ListModelsOutput, error)
@@ -36,10 +45,28 @@ type ModelsClient interface {
 	GetTags(ctx context.Context) (*GetTagsOutput, error)
 	// GetTagModels returns models that match the provided tags
 	GetTagModels(ctx context.Context, input *GetTagModelsInput) (*GetTagModelsOutput, error)
+	// CreateModel creates a new model along with its first version
+	CreateModel(ctx context.Context, input *CreateModelInput) (*CreateModelOutput, error)
+	// CreateModelVersion adds a new version to an existing model
+	CreateModelVersion(ctx context.Context, input *CreateModelVersionInput) (*CreateModelVersionOutput, error)
+	// UploadModelContainerImage uploads the container image of a model version in parts.  If a part fails, the returned
+	// *ContainerImageUploadError can be used to resume the upload.
+	UploadModelContainerImage(ctx context.Context, input *UploadModelContainerImageInput) (*UploadModelContainerImageOutput, error)
+	// UpdateModelVersion sets the input and output specs, timeouts and metadata of a model version, and can publish it
+	UpdateModelVersion(ctx context.Context, input *UpdateModelVersionInput) (*UpdateModelVersionOutput, error)
+	// StartModelVersionLoad starts validating that the uploaded container image loads
+	StartModelVersionLoad(ctx context.Context, input *StartModelVersionLoadInput) (*StartModelVersionLoadOutput, error)
+	// WaitForModelVersionLoad will poll until the container image has loaded or failed to load
+	WaitForModelVersionLoad(ctx context.Context, input *WaitForModelVersionValidationInput, pollInterval time.Duration) (*GetModelVersionDetailsOutput, error)
+	// StartModelVersionRun starts validating that the loaded model processes its test inputs
+	StartModelVersionRun(ctx context.Context, input *StartModelVersionRunInput) (*StartModelVersionRunOutput, error)
+	// WaitForModelVersionRun will poll until the test run has succeeded or failed
+	WaitForModelVersionRun(ctx context.Context, input *WaitForModelVersionValidationInput, pollInterval time.Duration) (*GetModelVersionDetailsOutput, error)
 }
 
 type standardModelsClient struct {
-	baseClient *standardClient
+	baseClient         *standardClient
+	licenseEnforcement LicenseEnforcement
 }
 
 var _ ModelsClient = &standardModelsClient{}
@@ -182,10 +209,11 @@ func (c *standardModelsClient) ListModelVersions(ctx context.Context, input *Lis
 	}
 
 	// decide if we have a next page (the next link is not always accurate?)
-	var nextPage *ListModelsInput
+	var nextPage *ListModelVersionsInput
 	if _, hasNextLink := links["next"]; len(items) == input.Paging.PerPage && hasNextLink {
-		nextPage = &ListModelsInput{
-			Paging: input.Paging.Next(),
+		nextPage = &ListModelVersionsInput{
+			ModelID: input.ModelID,
+			Paging:  input.Paging.Next(),
 		}
 	}
 
@@ -196,17 +224,23 @@ func (c *standardModelsClient) ListModelVersions(ctx context.Context, input *Lis
 }
 
 func (c *standardModelsClient) UpdateModelProcessingEngines(ctx context.Context, input *UpdateModelProcessingEnginesInput) (*UpdateModelProcessingEnginesOutput, error) {
+	if err := c.enforceLicense(ctx, input); err != nil {
+		return nil, err
+	}
+
 	isAdmin, err := c.baseClient.Accounting().HasEntitlement(ctx, "CAN_PATCH_PROCESSING_MODEL_VERSION")
 	if err != nil {
 		return nil, err
 	}
-	url := fmt.Sprintf("/api/models/%s/versions/%s", input.ModelID, input.Version)
-	if isAdmin {
-		url = url + "/processing"
-	}
 
 	var out model.ModelVersionDetails
-	_, err = c.baseClient.requestor.Patch(ctx, url, input, &out)
+	_, err = c.baseClient.requestor.Patch(ctx, processingEnginesURL(input, isAdmin), input, &out)
+	if errors.Cause(err) == ErrForbidden {
+		// the forbidden response dropped the cached entitlements; if they have changed since, the other endpoint applies
+		if nowAdmin, entitlementErr := c.baseClient.Accounting().HasEntitlement(ctx, "CAN_PATCH_PROCESSING_MODEL_VERSION"); entitlementErr == nil && nowAdmin != isAdmin {
+			_, err = c.baseClient.requestor.Patch(ctx, processingEnginesURL(input, nowAdmin), input, &out)
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -215,6 +249,50 @@ func (c *standardModelsClient) UpdateModelProcessingEngines(ctx context.Context,
 	}, nil
 }
 
+// enforceLicense checks the new minimum capacity against the capacity budget, as configured by WithLicenseEnforcement.
+// Only exceeding the license refuses a change; if the budget can not be read the check is skipped with a warning.
+func (c *standardModelsClient) enforceLicense(ctx context.Context, input *UpdateModelProcessingEnginesInput) error {
+	if c.licenseEnforcement == LicenseEnforcementOff {
+		return nil
+	}
+	err := c.checkLicense(ctx, input)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Cause(err) != ErrLicenseExceeded:
+		err = errors.WithMessage(err, "license not checked")
+	case c.licenseEnforcement == LicenseEnforcementRefuse:
+		return err
+	}
+	logrus.WithFields(logrus.Fields{
+		"model":   input.ModelID,
+		"version": input.Version,
+		"engines": input.MinimumParallelCapacity,
+	}).Warn(err.Error())
+	return nil
+}
+
+func (c *standardModelsClient) checkLicense(ctx context.Context, input *UpdateModelProcessingEnginesInput) error {
+	budget, err := ReadCapacityBudget(ctx, c.baseClient)
+	if err != nil {
+		return err
+	}
+	details, err := c.GetModelVersionDetails(ctx, &GetModelVersionDetailsInput{ModelID: input.ModelID, Version: input.Version})
+	if err != nil {
+		return errors.WithMessage(err, "failed to read the model version")
+	}
+	budget.RecordReserved(input.ModelID, input.Version, details.Details.Processing.MinimumParallelCapacity)
+	return budget.CheckCapacity(input.ModelID, input.Version, input.MinimumParallelCapacity)
+}
+
+func processingEnginesURL(input *UpdateModelProcessingEnginesInput, isAdmin bool) string {
+	url := fmt.Sprintf("/api/models/%s/versions/%s", input.ModelID, input.Version)
+	if isAdmin {
+		url = url + "/processing"
+	}
+	return url
+}
+
 func (c *standardModelsClient) GetModelVersionSampleInput(ctx context.Context, input *GetModelVersionSampleInputInput) (*GetModelVersionSampleInputOutput, error) {
 	var out interface{}
 	url := fmt.Sprintf("/api/models/%s/versions/%s/sample-input", input.ModelID, input.Version)
@@ -242,3 +320,187 @@ func (c *standardModelsClient) GetModelVersionSampleOutput(ctx context.Context,
 		Sample: string(jsonB),
 	}, err
 }
+
+func (c *standardModelsClient) CreateModel(ctx context.Context, input *CreateModelInput) (*CreateModelOutput, error) {
+	var out CreateModelOutput
+	_, err := c.baseClient.requestor.Post(ctx, "/api/models", input, &out)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *standardModelsClient) CreateModelVersion(ctx context.Context, input *CreateModelVersionInput) (*CreateModelVersionOutput, error) {
+	var out model.ModelVersionDetails
+	url := fmt.Sprintf("/api/models/%s/versions", input.ModelID)
+	_, err := c.baseClient.requestor.Post(ctx, url, input, &out)
+	if err != nil {
+		return nil, err
+	}
+	out.ModelID = input.ModelID
+	return &CreateModelVersionOutput{
+		Details: out,
+	}, nil
+}
+
+func (c *standardModelsClient) UploadModelContainerImage(ctx context.Context, input *UploadModelContainerImageInput) (*UploadModelContainerImageOutput, error) {
+	chunkSize := input.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultContainerImageChunkSize
+	}
+	size, err := input.Image.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read the size of the container image")
+	}
+	baseURL := fmt.Sprintf("/api/models/%s/versions/%s", input.ModelID, input.Version)
+
+	uploadID, part := input.UploadID, input.NextPart
+	if uploadID == "" {
+		var started struct {
+			UploadID string `json:"uploadId"`
+		}
+		if _, err := c.baseClient.requestor.Post(ctx, baseURL+"/upload-container-image", map[string]int64{"size": size}, &started); err != nil {
+			return nil, errors.WithMessage(err, "failed to start the container image upload")
+		}
+		uploadID, part = started.UploadID, 1
+	}
+	if part < 1 {
+		part = 1
+	}
+
+	uploaded := int64(part-1) * chunkSize
+	if _, err := input.Image.Seek(min(uploaded, size), io.SeekStart); err != nil {
+		return nil, &ContainerImageUploadError{UploadID: uploadID, NextPart: part, Err: errors.WithMessage(err, "failed to seek to the next part")}
+	}
+	buf := make([]byte, chunkSize)
+	for uploaded < size {
+		n, err := io.ReadFull(input.Image, buf)
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return nil, &ContainerImageUploadError{UploadID: uploadID, NextPart: part, Err: errors.WithMessage(err, "failed to read a part of the container image")}
+		}
+		partURL := fmt.Sprintf("%s/appendable-container-image?upload_id=%s&part_number=%d", baseURL, url.QueryEscape(uploadID), part)
+		if _, err := c.baseClient.requestor.PostMultipart(ctx, partURL, map[string]io.Reader{"file": bytes.NewReader(buf[:n])}, nil); err != nil {
+			return nil, &ContainerImageUploadError{UploadID: uploadID, NextPart: part, Err: errors.WithMessagef(err, "failed to upload part %d", part)}
+		}
+		uploaded += int64(n)
+		part++
+		if input.Progress != nil {
+			input.Progress(uploaded, size)
+		}
+	}
+
+	completeURL := fmt.Sprintf("%s/appendable-container-image?upload_id=%s", baseURL, url.QueryEscape(uploadID))
+	if _, err := c.baseClient.requestor.Patch(ctx, completeURL, nil, nil); err != nil {
+		return nil, &ContainerImageUploadError{UploadID: uploadID, NextPart: part, Err: errors.WithMessage(err, "failed to complete the upload")}
+	}
+	return &UploadModelContainerImageOutput{
+		UploadID: uploadID,
+		Parts:    part - 1,
+		Size:     size,
+	}, nil
+}
+
+func (c *standardModelsClient) UpdateModelVersion(ctx context.Context, input *UpdateModelVersionInput) (*UpdateModelVersionOutput, error) {
+	var out model.ModelVersionDetails
+	url := fmt.Sprintf("/api/models/%s/versions/%s", input.ModelID, input.Version)
+	_, err := c.baseClient.requestor.Patch(ctx, url, input, &out)
+	if err != nil {
+		return nil, err
+	}
+	out.ModelID = input.ModelID
+	return &UpdateModelVersionOutput{
+		Details: out,
+	}, nil
+}
+
+func (c *standardModelsClient) StartModelVersionLoad(ctx context.Context, input *StartModelVersionLoadInput) (*StartModelVersionLoadOutput, error) {
+	out := model.ModelVersionDetails{Version: input.Version}
+	url := fmt.Sprintf("/api/models/%s/versions/%s/load-process", input.ModelID, input.Version)
+	// the process may be started without returning any details
+	_, err := c.baseClient.requestor.Post(ctx, url, nil, &out)
+	if err != nil && errors.Cause(err) != io.EOF {
+		return nil, err
+	}
+	out.ModelID = input.ModelID
+	return &StartModelVersionLoadOutput{
+		Details: out,
+	}, nil
+}
+
+func (c *standardModelsClient) WaitForModelVersionLoad(ctx context.Context, input *WaitForModelVersionValidationInput, pollInterval time.Duration) (*GetModelVersionDetailsOutput, error) {
+	return c.waitForModelVersion(ctx, input, pollInterval, func(details model.ModelVersionDetails) (bool, error) {
+		switch details.ContainerImage.LoadStatus {
+		case ModelLoadStatusComplete:
+			return true, nil
+		case ModelLoadStatusFailed:
+			return true, errors.WithMessagef(ErrModelValidationFailed, "load failed at step %d (%s)", details.LoadStatus.Step, details.LoadStatus.StepName)
+		}
+		return false, nil
+	})
+}
+
+func (c *standardModelsClient) StartModelVersionRun(ctx context.Context, input *StartModelVersionRunInput) (*StartModelVersionRunOutput, error) {
+	baseURL := fmt.Sprintf("/api/models/%s/versions/%s", input.ModelID, input.Version)
+	if len(input.TestInputs) > 0 {
+		testInputs := map[string]io.Reader{}
+		for _, itemName := range sortedInputKeys(input.TestInputs) {
+			reader, err := input.TestInputs[itemName]()
+			if err != nil {
+				return nil, errors.WithMessagef(err, "failed to get data reader for test input %s", itemName)
+			}
+			testInputs[itemName] = reader
+		}
+		if _, err := c.baseClient.requestor.PostMultipart(ctx, baseURL+"/testInput", testInputs, nil); err != nil {
+			return nil, errors.WithMessage(err, "failed to upload the test inputs")
+		}
+	}
+
+	out := model.ModelVersionDetails{Version: input.Version}
+	// the process may be started without returning any details
+	_, err := c.baseClient.requestor.Post(ctx, baseURL+"/run-process", nil, &out)
+	if err != nil && errors.Cause(err) != io.EOF {
+		return nil, err
+	}
+	out.ModelID = input.ModelID
+	return &StartModelVersionRunOutput{
+		Details: out,
+	}, nil
+}
+
+func (c *standardModelsClient) WaitForModelVersionRun(ctx context.Context, input *WaitForModelVersionValidationInput, pollInterval time.Duration) (*GetModelVersionDetailsOutput, error) {
+	return c.waitForModelVersion(ctx, input, pollInterval, func(details model.ModelVersionDetails) (bool, error) {
+		switch details.RunStatus.Result.Status {
+		case ModelRunStatusSuccessful:
+			return true, nil
+		case ModelRunStatusFailed:
+			return true, errors.WithMessagef(ErrModelValidationFailed, "run failed at step %d (%s)", details.RunStatus.Step, details.RunStatus.StepName)
+		}
+		return false, nil
+	})
+}
+
+// waitForModelVersion polls the version details until done reports that validation has finished
+func (c *standardModelsClient) waitForModelVersion(ctx context.Context, input *WaitForModelVersionValidationInput, pollInterval time.Duration, done func(details model.ModelVersionDetails) (bool, error)) (*GetModelVersionDetailsOutput, error) {
+	timer := time.NewTimer(pollInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for model version validation was canceled due to provided context being canceled")
+		case <-timer.C:
+			version, err := c.GetModelVersionDetails(ctx, &GetModelVersionDetailsInput{ModelID: input.ModelID, Version: input.Version})
+			if err != nil {
+				return nil, err
+			}
+			finished, err := done(version.Details)
+			if err != nil {
+				return nil, err
+			}
+			if finished {
+				return version, nil
+			}
+			timer.Reset(pollInterval)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package modzy
 
 import (
 	"context"
+	"time"
 )
 
 // ModelsClientFake is meant to help in mocking the ModelsClient interface easily for unit testing.
@@ -19,6 +20,14 @@ type ModelsClientFake struct {
 	GetModelVersionSampleOutputFunc  func(ctx context.Context, input *GetModelVersionSampleOutputInput) (*GetModelVersionSampleOutputOutput, error)
 	GetTagsFunc                      func(ctx context.Context) (*GetTagsOutput, error)
 	GetTagModelsFunc                 func(ctx context.Context, input *GetTagModelsInput) (*GetTagModelsOutput, error)
+	CreateModelFunc                  func(ctx context.Context, input *CreateModelInput) (*CreateModelOutput, error)
+	CreateModelVersionFunc           func(ctx context.Context, input *CreateModelVersionInput) (*CreateModelVersionOutput, error)
+	UploadModelContainerImageFunc    func(ctx context.Context, input *UploadModelContainerImageInput) (*UploadModelContainerImageOutput, error)
+	UpdateModelVersionFunc           func(ctx context.Context, input *UpdateModelVersionInput) (*UpdateModelVersionOutput, error)
+	StartModelVersionLoadFunc        func(ctx context.Context, input *StartModelVersionLoadInput) (*StartModelVersionLoadOutput, error)
+	WaitForModelVersionLoadFunc      func(ctx context.Context, input *WaitForModelVersionValidationInput, pollInterval time.Duration) (*GetModelVersionDetailsOutput, error)
+	StartModelVersionRunFunc         func(ctx context.Context, input *StartModelVersionRunInput) (*StartModelVersionRunOutput, error)
+	WaitForModelVersionRunFunc       func(ctx context.Context, input *WaitForModelVersionValidationInput, pollInterval time.Duration) (*GetModelVersionDetailsOutput, error)
 }
 
 var _ ModelsClient = &ModelsClientFake{}
@@ -74,3 +83,35 @@ func (c *ModelsClientFake) GetTags(ctx context.Context) (*GetTagsOutput, error)
 func (c *ModelsClientFake) GetTagModels(ctx context.Context, input *GetTagModelsInput) (*GetTagModelsOutput, error) {
 	return c.GetTagModelsFunc(ctx, input)
 }
+
+func (c *ModelsClientFake) CreateModel(ctx context.Context, input *CreateModelInput) (*CreateModelOutput, error) {
+	return c.CreateModelFunc(ctx, input)
+}
+
+func (c *ModelsClientFake) CreateModelVersion(ctx context.Context, input *CreateModelVersionInput) (*CreateModelVersionOutput, error) {
+	return c.CreateModelVersionFunc(ctx, input)
+}
+
+func (c *ModelsClientFake) UploadModelContainerImage(ctx context.Context, input *UploadModelContainerImageInput) (*UploadModelContainerImageOutput, error) {
+	return c.UploadModelContainerImageFunc(ctx, input)
+}
+
+func (c *ModelsClientFake) UpdateModelVersion(ctx context.Context, input *UpdateModelVersionInput) (*UpdateModelVersionOutput, error) {
+	return c.UpdateModelVersionFunc(ctx, input)
+}
+
+func (c *ModelsClientFake) StartModelVersionLoad(ctx context.Context, input *StartModelVersionLoadInput) (*StartModelVersionLoadOutput, error) {
+	return c.StartModelVersionLoadFunc(ctx, input)
+}
+
+func (c *ModelsClientFake) WaitForModelVersionLoad(ctx context.Context, input *WaitForModelVersionValidationInput, pollInterval time.Duration) (*GetModelVersionDetailsOutput, error) {
+	return c.WaitForModelVersionLoadFunc(ctx, input, pollInterval)
+}
+
+func (c *ModelsClientFake) StartModelVersionRun(ctx context.Context, input *StartModelVersionRunInput) (*StartModelVersionRunOutput, error) {
+	return c.StartModelVersionRunFunc(ctx, input)
+}
+
+func (c *ModelsClientFake) WaitForModelVersionRun(ctx context.Context, input *WaitForModelVersionValidationInput, pollInterval time.Duration) (*GetModelVersionDetailsOutput, error) {
+	return c.WaitForModelVersionRunFunc(ctx, input, pollInterval)
+}
@@ -4,6 +4,7 @@ package modzy
 import (
 	"context"
 	"testing"
+	"time"
 )
 
 func TestModelsClientFake(t *testing.T) {
@@ -132,6 +133,92 @@ func TestModelsClientFake(t *testing.T) {
 			}
 			return nil, nil
 		},
+		CreateModelFunc: func(ctx context.Context, input *CreateModelInput) (*CreateModelOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		CreateModelVersionFunc: func(ctx context.Context, input *CreateModelVersionInput) (*CreateModelVersionOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		UploadModelContainerImageFunc: func(ctx context.Context, input *UploadModelContainerImageInput) (*UploadModelContainerImageOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		UpdateModelVersionFunc: func(ctx context.Context, input *UpdateModelVersionInput) (*UpdateModelVersionOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		StartModelVersionLoadFunc: func(ctx context.Context, input *StartModelVersionLoadInput) (*StartModelVersionLoadOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		WaitForModelVersionLoadFunc: func(ctx context.Context, input *WaitForModelVersionValidationInput, pollInterval time.Duration) (*GetModelVersionDetailsOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			if pollInterval != time.Second {
+				t.Errorf("pollInterval was not passed through")
+			}
+			return nil, nil
+		},
+		StartModelVersionRunFunc: func(ctx context.Context, input *StartModelVersionRunInput) (*StartModelVersionRunOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			return nil, nil
+		},
+		WaitForModelVersionRunFunc: func(ctx context.Context, input *WaitForModelVersionValidationInput, pollInterval time.Duration) (*GetModelVersionDetailsOutput, error) {
+			calls++
+			if ctx != expectedCtx {
+				t.Errorf("not expected ctx")
+			}
+			if input == nil {
+				t.Errorf("input was not passed through")
+			}
+			if pollInterval != time.Second {
+				t.Errorf("pollInterval was not passed through")
+			}
+			return nil, nil
+		},
 	}
 
 	fake.ListModels(expectedCtx, &ListModelsInput{})
@@ -147,8 +234,16 @@ func TestModelsClientFake(t *testing.T) {
 	fake.GetModelVersionSampleOutput(expectedCtx, &GetModelVersionSampleOutputInput{})
 	fake.GetTags(expectedCtx)
 	fake.GetTagModels(expectedCtx, &GetTagModelsInput{})
+	fake.CreateModel(expectedCtx, &CreateModelInput{})
+	fake.CreateModelVersion(expectedCtx, &CreateModelVersionInput{})
+	fake.UploadModelContainerImage(expectedCtx, &UploadModelContainerImageInput{})
+	fake.UpdateModelVersion(expectedCtx, &UpdateModelVersionInput{})
+	fake.StartModelVersionLoad(expectedCtx, &StartModelVersionLoadInput{})
+	fake.WaitForModelVersionLoad(expectedCtx, &WaitForModelVersionValidationInput{}, time.Second)
+	fake.StartModelVersionRun(expectedCtx, &StartModelVersionRunInput{})
+	fake.WaitForModelVersionRun(expectedCtx, &WaitForModelVersionValidationInput{}, time.Second)
 
-	if calls != 13 {
+	if calls != 21 {
 		t.Errorf("Did not call all of the funcs: %d", calls)
 	}
 }
@@ -3,9 +3,15 @@ package modzy
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
 )
 
 func TestGetModelVersionDetailsHTTPError(t *testing.T) {
@@ -448,6 +454,9 @@ func TestListModelVersions(t *testing.T) {
 	if out.NextPage.Paging.Page != 8 {
 		t.Errorf("expected NextPage to be next")
 	}
+	if out.NextPage.ModelID != "modelID" {
+		t.Errorf("expected NextPage to keep the model")
+	}
 }
 
 func TestUpdateModelProcessingEnginesEntitlementError(t *testing.T) {
@@ -548,6 +557,180 @@ func TestUpdateModelProcessingEnginesAdmin(t *testing.T) {
 	}
 }
 
+func TestUpdateModelProcessingEnginesEntitlementChanged(t *testing.T) {
+	var paths []string
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.RequestURI)
+		switch len(paths) {
+		case 1:
+			w.Write([]byte(`[{"identifier": "CAN_PATCH_PROCESSING_MODEL_VERSION"}]`))
+		case 2:
+			w.WriteHeader(403)
+			w.Write([]byte(`{"statusCode":403,"message":"forbidden"}`))
+		case 3:
+			w.Write([]byte(`[]`))
+		case 4:
+			w.Write([]byte(`{"sampleOutput": "some-sample-out"}`))
+		}
+	}))
+	defer serv.Close()
+
+	client := NewClient(serv.URL)
+	out, err := client.Models().UpdateModelProcessingEngines(context.TODO(), &UpdateModelProcessingEnginesInput{
+		ModelID: "modelID",
+		Version: "version",
+	})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.Details.SampleOutput != "some-sample-out" {
+		t.Errorf("response not parsed")
+	}
+	if len(paths) != 4 || paths[1] != "/api/models/modelID/versions/version/processing" || paths[3] != "/api/models/modelID/versions/version" {
+		t.Errorf("Expected a retry on the other endpoint, got %v", paths)
+	}
+}
+
+func TestUpdateModelProcessingEnginesForbidden(t *testing.T) {
+	calls := 0
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.RequestURI == "/api/accounting/entitlements" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.WriteHeader(403)
+		w.Write([]byte(`{"statusCode":403,"message":"forbidden"}`))
+	}))
+	defer serv.Close()
+
+	client := NewClient(serv.URL)
+	_, err := client.Models().UpdateModelProcessingEngines(context.TODO(), &UpdateModelProcessingEnginesInput{})
+	if errors.Cause(err) != ErrForbidden {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected no retry when the entitlements are unchanged, got %d calls", calls)
+	}
+}
+
+func TestUpdateModelProcessingEnginesLicenseRefused(t *testing.T) {
+	patched := false
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/api/license":
+			w.Write([]byte(`{"processingEngines": "3"}`))
+		case "/api/models/processing-engines":
+			w.Write([]byte(`{"minimumProcessingEnginesSum": 2}`))
+		case "/api/resources/processing/models":
+			w.Write([]byte(`[{"identifier": "modelID", "version": "version", "engines": [{"name": "e1"}, {"name": "e2"}]}]`))
+		case "/api/accounting/entitlements":
+			w.Write([]byte(`[]`))
+		case "/api/models/modelID/versions/version":
+			if r.Method == http.MethodGet {
+				w.Write([]byte(`{"processing": {"minimumParallelCapacity": 2}}`))
+				return
+			}
+			patched = true
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("url not expected: %s", r.RequestURI)
+		}
+	}))
+	defer serv.Close()
+
+	client := NewClient(serv.URL, WithLicenseEnforcement(LicenseEnforcementRefuse))
+	_, err := client.Models().UpdateModelProcessingEngines(context.TODO(), &UpdateModelProcessingEnginesInput{
+		ModelID:                 "modelID",
+		Version:                 "version",
+		MinimumParallelCapacity: 4,
+		MaximumParallelCapacity: 4,
+	})
+	if errors.Cause(err) != ErrLicenseExceeded {
+		t.Errorf("Expected ErrLicenseExceeded, got %v", err)
+	}
+	if patched {
+		t.Errorf("Expected the change to not be made")
+	}
+
+	_, err = client.Models().UpdateModelProcessingEngines(context.TODO(), &UpdateModelProcessingEnginesInput{
+		ModelID:                 "modelID",
+		Version:                 "version",
+		MinimumParallelCapacity: 3,
+		MaximumParallelCapacity: 4,
+	})
+	if err != nil || !patched {
+		t.Errorf("Expected a change within the license to be made, got %v", err)
+	}
+}
+
+func TestUpdateModelProcessingEnginesLicenseUnreadable(t *testing.T) {
+	patched := false
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/api/license":
+			w.WriteHeader(500)
+			w.Write([]byte(`{"statusCode":500,"message":"nope"}`))
+		case "/api/accounting/entitlements":
+			w.Write([]byte(`[]`))
+		case "/api/models/modelID/versions/version":
+			patched = true
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("url not expected: %s", r.RequestURI)
+		}
+	}))
+	defer serv.Close()
+
+	client := NewClient(serv.URL, WithLicenseEnforcement(LicenseEnforcementRefuse))
+	_, err := client.Models().UpdateModelProcessingEngines(context.TODO(), &UpdateModelProcessingEnginesInput{
+		ModelID:                 "modelID",
+		Version:                 "version",
+		MinimumParallelCapacity: 4,
+		MaximumParallelCapacity: 4,
+	})
+	if err != nil || !patched {
+		t.Errorf("Expected the change to be made when the license can not be read, got %v", err)
+	}
+}
+
+func TestUpdateModelProcessingEnginesLicenseWarned(t *testing.T) {
+	patched := false
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/api/license":
+			w.Write([]byte(`{"processingEngines": "3"}`))
+		case "/api/models/processing-engines":
+			w.Write([]byte(`{"minimumProcessingEnginesSum": 2}`))
+		case "/api/resources/processing/models":
+			w.Write([]byte(`[{"identifier": "modelID", "version": "version", "engines": [{"name": "e1"}, {"name": "e2"}]}]`))
+		case "/api/accounting/entitlements":
+			w.Write([]byte(`[]`))
+		case "/api/models/modelID/versions/version":
+			if r.Method == http.MethodGet {
+				w.Write([]byte(`{"processing": {"minimumParallelCapacity": 2}}`))
+				return
+			}
+			patched = true
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("url not expected: %s", r.RequestURI)
+		}
+	}))
+	defer serv.Close()
+
+	client := NewClient(serv.URL, WithLicenseEnforcement(LicenseEnforcementWarn))
+	_, err := client.Models().UpdateModelProcessingEngines(context.TODO(), &UpdateModelProcessingEnginesInput{
+		ModelID:                 "modelID",
+		Version:                 "version",
+		MinimumParallelCapacity: 4,
+		MaximumParallelCapacity: 4,
+	})
+	if err != nil || !patched {
+		t.Errorf("Expected the change to be made with a warning, got %v", err)
+	}
+}
+
 func TestGetModelVersionSampleInputHTTPError(t *testing.T) {
 	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(500)
@@ -629,3 +812,244 @@ func TestGetModelVersionSampleOutput(t *testing.T) {
 		t.Errorf("response not parsed: %s", out.Sample)
 	}
 }
+
+func TestCreateModel(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("expected method to be POST, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/models" {
+			t.Errorf("post url not expected: %s", r.RequestURI)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name":"My Model","version":"0.0.1"}` {
+			t.Errorf("body not expected: %s", body)
+		}
+		w.Write([]byte(`{"identifier": "abc", "version": "0.0.1"}`))
+	}))
+	defer serv.Close()
+
+	client := NewClient(serv.URL)
+	out, err := client.Models().CreateModel(context.TODO(), &CreateModelInput{Name: "My Model", Version: "0.0.1"})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.ModelID != "abc" || out.Version != "0.0.1" {
+		t.Errorf("response not parsed: %+v", out)
+	}
+}
+
+func TestCreateModelVersion(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("expected method to be POST, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/models/abc/versions" {
+			t.Errorf("post url not expected: %s", r.RequestURI)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"version":"0.0.2"}` {
+			t.Errorf("body not expected: %s", body)
+		}
+		w.Write([]byte(`{"version": "0.0.2"}`))
+	}))
+	defer serv.Close()
+
+	client := NewClient(serv.URL)
+	out, err := client.Models().CreateModelVersion(context.TODO(), &CreateModelVersionInput{ModelID: "abc", Version: "0.0.2"})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.Details.ModelID != "abc" || out.Details.Version != "0.0.2" {
+		t.Errorf("response not parsed: %+v", out.Details)
+	}
+}
+
+func TestUploadModelContainerImageResume(t *testing.T) {
+	parts := map[string]string{}
+	failPart := "2"
+	completed := false
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/models/abc/versions/0.0.1/upload-container-image":
+			w.Write([]byte(`{"uploadId": "upload 1"}`))
+		case r.Method == "POST" && r.URL.Path == "/api/models/abc/versions/0.0.1/appendable-container-image":
+			if r.URL.Query().Get("upload_id") != "upload 1" {
+				t.Errorf("upload id not expected: %s", r.RequestURI)
+			}
+			part := r.URL.Query().Get("part_number")
+			if part == failPart {
+				failPart = ""
+				w.WriteHeader(500)
+				w.Write([]byte(`{"statusCode":500,"message":"try again"}`))
+				return
+			}
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				t.Fatalf("part not sent as a file: %v", err)
+			}
+			data, _ := io.ReadAll(file)
+			parts[part] = string(data)
+		case r.Method == "PATCH" && r.URL.Path == "/api/models/abc/versions/0.0.1/appendable-container-image":
+			completed = true
+		default:
+			t.Errorf("request not expected: %s %s", r.Method, r.RequestURI)
+		}
+	}))
+	defer serv.Close()
+
+	client := NewClient(serv.URL)
+	input := &UploadModelContainerImageInput{
+		ModelID:   "abc",
+		Version:   "0.0.1",
+		Image:     strings.NewReader("0123456789"),
+		ChunkSize: 4,
+	}
+	_, err := client.Models().UploadModelContainerImage(context.TODO(), input)
+	uploadErr, ok := err.(*ContainerImageUploadError)
+	if !ok {
+		t.Fatalf("expected a ContainerImageUploadError, got %v", err)
+	}
+	if uploadErr.UploadID != "upload 1" || uploadErr.NextPart != 2 || errors.Cause(err) != ErrInternalServer {
+		t.Errorf("error not expected: %+v", uploadErr)
+	}
+
+	progress := []int64{}
+	resumed := uploadErr.Resume(input)
+	resumed.Progress = func(uploaded int64, total int64) {
+		progress = append(progress, uploaded)
+	}
+	out, err := client.Models().UploadModelContainerImage(context.TODO(), resumed)
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.Parts != 3 || out.Size != 10 || !completed {
+		t.Errorf("output not expected: %+v", out)
+	}
+	if parts["1"] != "0123" || parts["2"] != "4567" || parts["3"] != "89" {
+		t.Errorf("parts not expected: %v", parts)
+	}
+	if len(progress) != 2 || progress[1] != 10 {
+		t.Errorf("progress not expected: %v", progress)
+	}
+}
+
+func TestUpdateModelVersion(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" {
+			t.Errorf("expected method to be PATCH, got %s", r.Method)
+		}
+		if r.RequestURI != "/api/models/abc/versions/0.0.1" {
+			t.Errorf("patch url not expected: %s", r.RequestURI)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"timeout":{"status":60,"run":600},"longDescription":"long","isActive":true}` {
+			t.Errorf("body not expected: %s", body)
+		}
+		w.Write([]byte(`{"version": "0.0.1", "isActive": true}`))
+	}))
+	defer serv.Close()
+
+	client := NewClient(serv.URL)
+	active := true
+	out, err := client.Models().UpdateModelVersion(context.TODO(), &UpdateModelVersionInput{
+		ModelID:         "abc",
+		Version:         "0.0.1",
+		Timeout:         &model.ModelDetailsTimeout{Status: 60, Run: 600},
+		LongDescription: "long",
+		IsActive:        &active,
+	})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if !out.Details.IsActive || out.Details.ModelID != "abc" {
+		t.Errorf("response not parsed: %+v", out.Details)
+	}
+}
+
+func TestModelVersionLoadValidation(t *testing.T) {
+	polls := 0
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.RequestURI {
+		case "POST /api/models/abc/versions/0.0.1/load-process":
+			// started without a body
+		case "GET /api/models/abc/versions/0.0.1":
+			polls++
+			if polls == 1 {
+				w.Write([]byte(`{"containerImage": {"loadStatus": "IN_PROGRESS"}}`))
+				return
+			}
+			w.Write([]byte(`{"containerImage": {"loadStatus": "FAILED"}, "loadStatus": {"step": 2, "stepName": "Loading"}}`))
+		default:
+			t.Errorf("request not expected: %s %s", r.Method, r.RequestURI)
+		}
+	}))
+	defer serv.Close()
+
+	client := NewClient(serv.URL)
+	started, err := client.Models().StartModelVersionLoad(context.TODO(), &StartModelVersionLoadInput{ModelID: "abc", Version: "0.0.1"})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if started.Details.ModelID != "abc" || started.Details.Version != "0.0.1" {
+		t.Errorf("details not expected: %+v", started.Details)
+	}
+	_, err = client.Models().WaitForModelVersionLoad(context.TODO(), &WaitForModelVersionValidationInput{ModelID: "abc", Version: "0.0.1"}, time.Millisecond)
+	if errors.Cause(err) != ErrModelValidationFailed || !strings.Contains(err.Error(), "step 2 (Loading)") {
+		t.Errorf("expected a validation failure, got %v", err)
+	}
+	if polls != 2 {
+		t.Errorf("expected 2 polls, got %d", polls)
+	}
+}
+
+func TestModelVersionRunValidation(t *testing.T) {
+	testInput := ""
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.RequestURI {
+		case "POST /api/models/abc/versions/0.0.1/testInput":
+			file, _, err := r.FormFile("input.txt")
+			if err != nil {
+				t.Fatalf("test input not sent as a file: %v", err)
+			}
+			data, _ := io.ReadAll(file)
+			testInput = string(data)
+		case "POST /api/models/abc/versions/0.0.1/run-process":
+			w.Write([]byte(`{"version": "0.0.1", "runStatus": {"step": 1}}`))
+		case "GET /api/models/abc/versions/0.0.1":
+			w.Write([]byte(`{"runStatus": {"result": {"status": "SUCCESSFUL"}}}`))
+		default:
+			t.Errorf("request not expected: %s %s", r.Method, r.RequestURI)
+		}
+	}))
+	defer serv.Close()
+
+	client := NewClient(serv.URL)
+	started, err := client.Models().StartModelVersionRun(context.TODO(), &StartModelVersionRunInput{
+		ModelID:    "abc",
+		Version:    "0.0.1",
+		TestInputs: FileInputItem{"input.txt": FileInputReader(strings.NewReader("hello"))},
+	})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if testInput != "hello" || started.Details.RunStatus.Step != 1 {
+		t.Errorf("run not started as expected: %q %+v", testInput, started.Details.RunStatus)
+	}
+	out, err := client.Models().WaitForModelVersionRun(context.TODO(), &WaitForModelVersionValidationInput{ModelID: "abc", Version: "0.0.1"}, time.Millisecond)
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if out.Details.RunStatus.Result.Status != ModelRunStatusSuccessful {
+		t.Errorf("response not parsed: %+v", out.Details.RunStatus)
+	}
+}
+
+func TestWaitForModelVersionRunCanceled(t *testing.T) {
+	client := NewClient("http://localhost:0")
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	if _, err := client.Models().WaitForModelVersionRun(ctx, &WaitForModelVersionValidationInput{}, time.Hour); err == nil {
+		t.Errorf("Expected error")
+	}
+}
@@ -1,6 +1,9 @@
 package modzy
 
 import (
+	"encoding/json"
+	"io"
+
 	"github.com/modzy/sdk-go/model"
 )
 
@@ -137,8 +140,8 @@ func (i *ListModelVersionsInput) WithFilterOr(field ListModelVersionsFilterField
 }
 
 type ListModelVersionsOutput struct {
-	Versions []model.ModelVersion `json:"versions"`
-	NextPage *ListModelsInput     `json:"nextPage"`
+	Versions []model.ModelVersion    `json:"versions"`
+	NextPage *ListModelVersionsInput `json:"nextPage"`
 }
 
 type UpdateModelProcessingEnginesInput struct {
@@ -173,3 +176,92 @@ type GetModelVersionSampleOutputOutput struct {
 type GetLatestModelsOutput struct {
 	Models []model.ModelDetails `json:"models"`
 }
+
+type CreateModelInput struct {
+	Name string `json:"name"`
+	// Version is the first version of the model
+	Version string `json:"version"`
+}
+
+type CreateModelOutput struct {
+	ModelID string `json:"identifier"`
+	Version string `json:"version"`
+}
+
+type CreateModelVersionInput struct {
+	ModelID string `json:"-"`
+	Version string `json:"version"`
+}
+
+type CreateModelVersionOutput struct {
+	Details model.ModelVersionDetails `json:"details"`
+}
+
+type UploadModelContainerImageInput struct {
+	ModelID string
+	Version string
+	// Image is the container image tarball, such as the output of "docker save"
+	Image io.ReadSeeker
+	// ChunkSize is the size of each uploaded part; defaults to 100MB
+	ChunkSize int64
+	// UploadID and NextPart continue an upload that failed part way; see ContainerImageUploadError.Resume
+	UploadID string
+	NextPart int
+	// Progress, if provided, is called after each part with the bytes uploaded so far
+	Progress func(uploaded int64, total int64)
+}
+
+type UploadModelContainerImageOutput struct {
+	UploadID string
+	Parts    int
+	Size     int64
+}
+
+// UpdateModelVersionInput only sends the fields that are set
+type UpdateModelVersionInput struct {
+	ModelID               string                               `json:"-"`
+	Version               string                               `json:"-"`
+	Inputs                []model.ModelVersionDetailsInput     `json:"inputs,omitempty"`
+	Outputs               []model.ModelVersionDetailsOutput    `json:"outputs,omitempty"`
+	InputValidationSchema json.RawMessage                      `json:"inputValidationSchema,omitempty"`
+	Timeout               *model.ModelDetailsTimeout           `json:"timeout,omitempty"`
+	Requirement           json.RawMessage                      `json:"requirement,omitempty"`
+	Statistics            []model.ModelVersionDetailsStatistic `json:"statistics,omitempty"`
+	LongDescription       string                               `json:"longDescription,omitempty"`
+	TechnicalDetails      string                               `json:"technicalDetails,omitempty"`
+	PerformanceSummary    string                               `json:"performanceSummary,omitempty"`
+	VersionHistory        string                               `json:"versionHistory,omitempty"`
+	SourceType            string                               `json:"sourceType,omitempty"`
+	// IsActive and IsAvailable publish the version once it has passed validation
+	IsActive    *bool `json:"isActive,omitempty"`
+	IsAvailable *bool `json:"isAvailable,omitempty"`
+}
+
+type UpdateModelVersionOutput struct {
+	Details model.ModelVersionDetails `json:"details"`
+}
+
+type StartModelVersionLoadInput struct {
+	ModelID string
+	Version string
+}
+
+type StartModelVersionRunInput struct {
+	ModelID string
+	Version string
+	// TestInputs, if provided, are uploaded as the sample that the run validation processes
+	TestInputs FileInputItem
+}
+
+type WaitForModelVersionValidationInput struct {
+	ModelID string
+	Version string
+}
+
+type StartModelVersionLoadOutput struct {
+	Details model.ModelVersionDetails `json:"details"`
+}
+
+type StartModelVersionRunOutput struct {
+	Details model.ModelVersionDetails `json:"details"`
+}
@@ -0,0 +1,88 @@
+package modzy
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// OpenJobActions add inputs to a job created with Jobs().OpenJob(...).  The job is not processed until it is closed.
+//
+// AddInput may be called concurrently; Close and Abort wait for any inputs that are still being added.
+type OpenJobActions interface {
+	JobActions
+	// JobIdentifier is the identifier of the open job
+	JobIdentifier() string
+	// AddInput uploads the items of a single input, chunking the data as necessary
+	AddInput(ctx context.Context, inputKey string, items FileInputItem) error
+	// Close will stop accepting inputs and start processing the job
+	Close(ctx context.Context) (*CloseJobOutput, error)
+	// Abort will cancel the job without processing any of the inputs.  Cancel does the same.
+	Abort(ctx context.Context) (*CancelJobOutput, error)
+}
+
+type standardOpenJobActions struct {
+	JobActions
+	jobs          *standardJobsClient
+	jobIdentifier string
+	chunkSize     int64
+
+	lock sync.RWMutex
+	done bool
+}
+
+func newOpenJobActions(client Client, jobs *standardJobsClient, jobIdentifier string, chunkSize int64) OpenJobActions {
+	return &standardOpenJobActions{
+		JobActions:    NewJobActions(client, jobIdentifier),
+		jobs:          jobs,
+		jobIdentifier: jobIdentifier,
+		chunkSize:     chunkSize,
+	}
+}
+
+func (j *standardOpenJobActions) JobIdentifier() string {
+	return j.jobIdentifier
+}
+
+func (j *standardOpenJobActions) AddInput(ctx context.Context, inputKey string, items FileInputItem) error {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	if j.done {
+		return errors.WithMessagef(ErrJobNotOpen, "can not add input %s", inputKey)
+	}
+	return j.jobs.postInputChunks(ctx, j.jobIdentifier, j.chunkSize, inputKey, items)
+}
+
+func (j *standardOpenJobActions) Close(ctx context.Context) (*CloseJobOutput, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.done {
+		return nil, errors.WithMessage(ErrJobNotOpen, "can not close the job")
+	}
+	out, err := j.jobs.CloseJob(ctx, &CloseJobInput{JobIdentifier: j.jobIdentifier})
+	if err != nil {
+		return nil, err
+	}
+	j.done = true
+	return out, nil
+}
+
+func (j *standardOpenJobActions) Abort(ctx context.Context) (*CancelJobOutput, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.done {
+		return nil, errors.WithMessage(ErrJobNotOpen, "can not abort the job")
+	}
+	out, err := j.JobActions.Cancel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	j.done = true
+	return out, nil
+}
+
+// Cancel is the same as Abort, so that inputs are no longer accepted once the job is canceled
+func (j *standardOpenJobActions) Cancel(ctx context.Context) (*CancelJobOutput, error) {
+	return j.Abort(ctx)
+}
@@ -0,0 +1,144 @@
+package modzy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestOpenJobAddInputAndClose(t *testing.T) {
+	var lock sync.Mutex
+	requested := []string{}
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requested = append(requested, r.Method+" "+r.URL.String())
+		lock.Unlock()
+		switch r.URL.String() {
+		case "/api/jobs/features":
+			w.Write([]byte(`{"inputChunkMaximumSize":"1M"}`))
+		case "/api/jobs":
+			w.Write([]byte(`{"jobIdentifier":"openJobID"}`))
+		case "/api/jobs/openJobID/input-1/item", "/api/jobs/openJobID/input-2/item":
+			// posting a chunk is fine
+		case "/api/jobs/openJobID/close":
+			w.Write([]byte(`{"jobIdentifier":"openJobID","status":"SUBMITTED"}`))
+		default:
+			t.Errorf("An unexpected url was requested: %s", r.URL.String())
+		}
+	}))
+	defer serv.Close()
+
+	client := NewClient(serv.URL)
+	open, err := client.Jobs().OpenJob(context.TODO(), &OpenJobInput{ModelIdentifier: "model", ModelVersion: "1.0.0"})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if open.JobIdentifier() != "openJobID" || open.Response.JobIdentifier != "openJobID" {
+		t.Errorf("job identifier not expected: %s", open.JobIdentifier())
+	}
+
+	var wg sync.WaitGroup
+	for _, key := range []string{"input-1", "input-2"} {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			if err := open.AddInput(context.TODO(), key, FileInputItem{"item": FileInputReader(strings.NewReader("abc"))}); err != nil {
+				t.Errorf("err not nil: %v", err)
+			}
+		}(key)
+	}
+	wg.Wait()
+
+	closed, err := open.Close(context.TODO())
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if closed.Details.Status != JobStatusSubmitted {
+		t.Errorf("close response not parsed: %+v", closed.Details)
+	}
+
+	if err := open.AddInput(context.TODO(), "late", FileInputItem{}); errors.Cause(err) != ErrJobNotOpen {
+		t.Errorf("expected ErrJobNotOpen, got %v", err)
+	}
+	if _, err := open.Close(context.TODO()); errors.Cause(err) != ErrJobNotOpen {
+		t.Errorf("expected ErrJobNotOpen, got %v", err)
+	}
+	if _, err := open.Abort(context.TODO()); errors.Cause(err) != ErrJobNotOpen {
+		t.Errorf("expected ErrJobNotOpen, got %v", err)
+	}
+	if requested[len(requested)-1] != "POST /api/jobs/openJobID/close" {
+		t.Errorf("requests after closing not expected: %v", requested)
+	}
+}
+
+func TestOpenJobAbort(t *testing.T) {
+	requested := []string{}
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.Method+" "+r.URL.String())
+		switch r.URL.String() {
+		case "/api/jobs/features":
+			w.Write([]byte(`{"inputChunkMaximumSize":"1M"}`))
+		case "/api/jobs":
+			w.Write([]byte(`{"jobIdentifier":"openJobID"}`))
+		case "/api/jobs/openJobID":
+			w.Write([]byte(`{"jobIdentifier":"openJobID","status":"CANCELED"}`))
+		default:
+			t.Errorf("An unexpected url was requested: %s", r.URL.String())
+		}
+	}))
+	defer serv.Close()
+
+	client := NewClient(serv.URL)
+	open, err := client.Jobs().OpenJob(context.TODO(), &OpenJobInput{})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	aborted, err := open.Abort(context.TODO())
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if aborted.Details.Status != JobStatusCanceled {
+		t.Errorf("abort response not parsed: %+v", aborted.Details)
+	}
+	if _, err := open.Close(context.TODO()); errors.Cause(err) != ErrJobNotOpen {
+		t.Errorf("expected ErrJobNotOpen, got %v", err)
+	}
+	if requested[len(requested)-1] != "DELETE /api/jobs/openJobID" {
+		t.Errorf("abort request not expected: %v", requested)
+	}
+
+	// canceling through the embedded job actions also stops the job accepting inputs
+	open, err = client.Jobs().OpenJob(context.TODO(), &OpenJobInput{})
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if _, err := open.Cancel(context.TODO()); err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if err := open.AddInput(context.TODO(), "input-1", FileInputItem{}); errors.Cause(err) != ErrJobNotOpen {
+		t.Errorf("expected ErrJobNotOpen, got %v", err)
+	}
+	if _, err := open.Abort(context.TODO()); errors.Cause(err) != ErrJobNotOpen {
+		t.Errorf("expected ErrJobNotOpen, got %v", err)
+	}
+	if deletes := strings.Count(strings.Join(requested, ","), "DELETE"); deletes != 2 {
+		t.Errorf("expected one cancel request per job, got %v", requested)
+	}
+}
+
+func TestOpenJobHTTPError(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	}))
+	defer serv.Close()
+
+	client := NewClient(serv.URL)
+	if _, err := client.Jobs().OpenJob(context.TODO(), &OpenJobInput{}); err == nil {
+		t.Errorf("Expected error")
+	}
+}
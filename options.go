@@ -1,7 +1,9 @@
 package modzy
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 )
 
 type ClientOption func(*standardClient)
@@ -22,3 +24,44 @@ func WithHTTPDebugging(request bool, response bool) ClientOption {
 		c.requestor.responseDebugging = response
 	}
 }
+
+// WithAPIKeySource authenticates every request with the current key of the source, so that a key can be rotated while
+// the client is in use.  Use this instead of WithAPIKey or WithTeamKey.
+func WithAPIKeySource(source *APIKeySource) ClientOption {
+	return func(c *standardClient) {
+		c.requestor.authorizationDecorator = func(req *http.Request) *http.Request {
+			req.Header.Add("Authorization", fmt.Sprintf("ApiKey %s", source.APIKey()))
+			return req
+		}
+	}
+}
+
+// WithEntitlementCacheTTL sets how long the entitlements of your key are cached for HasEntitlement and
+// RequireEntitlements.  The default is five minutes, and a ttl of zero or less reads them every time.
+func WithEntitlementCacheTTL(ttl time.Duration) ClientOption {
+	return func(c *standardClient) {
+		c.accountingClient.entitlements.setTTL(ttl)
+	}
+}
+
+// LicenseEnforcement is what UpdateModelProcessingEngines does when a change would use more processing engines than the
+// license allows
+type LicenseEnforcement int
+
+const (
+	// LicenseEnforcementOff leaves the check to the API
+	LicenseEnforcementOff LicenseEnforcement = iota
+	// LicenseEnforcementWarn logs a warning and makes the change anyway
+	LicenseEnforcementWarn
+	// LicenseEnforcementRefuse returns an error wrapping ErrLicenseExceeded without making the change.  If the license or
+	// the engines in use can not be read, a warning is logged and the change is made.
+	LicenseEnforcementRefuse
+)
+
+// WithLicenseEnforcement checks changes made by UpdateModelProcessingEngines against the capacity budget.  This costs
+// four extra requests per change.
+func WithLicenseEnforcement(enforcement LicenseEnforcement) ClientOption {
+	return func(c *standardClient) {
+		c.modelsClient.licenseEnforcement = enforcement
+	}
+}
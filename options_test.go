@@ -3,6 +3,7 @@ package modzy
 import (
 	"net/http"
 	"testing"
+	"time"
 )
 
 func TestClientOptionWithHTTPClient(t *testing.T) {
@@ -39,3 +40,28 @@ func TestClientOptionWithHTTPDebugging(t *testing.T) {
 		t.Errorf("Expected requestDebugging to be true")
 	}
 }
+
+func TestClientOptionWithAPIKeySource(t *testing.T) {
+	client := &standardClient{
+		requestor: &requestor{},
+	}
+	source := NewAPIKeySource("first")
+	WithAPIKeySource(source)(client)
+
+	req := client.requestor.authorizationDecorator(&http.Request{Header: http.Header{}})
+	if req.Header.Get("Authorization") != "ApiKey first" {
+		t.Errorf("Expected the first key, got %s", req.Header.Get("Authorization"))
+	}
+	source.SetAPIKey("second")
+	req = client.requestor.authorizationDecorator(&http.Request{Header: http.Header{}})
+	if req.Header.Get("Authorization") != "ApiKey second" {
+		t.Errorf("Expected the second key, got %s", req.Header.Get("Authorization"))
+	}
+}
+
+func TestClientOptionWithEntitlementCacheTTL(t *testing.T) {
+	client := NewClient("https://example", WithEntitlementCacheTTL(time.Hour)).(*standardClient)
+	if client.accountingClient.entitlements.ttl != time.Hour {
+		t.Errorf("Expected the ttl to be set, got %v", client.accountingClient.entitlements.ttl)
+	}
+}
@@ -0,0 +1,222 @@
+package modzy
+
+import (
+	"context"
+	"iter"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+// Pager walks every page of a List endpoint so that callers do not need to follow NextPage by hand.
+//
+// Iteration stops when a page is not followed by a NextPage, when a page comes back empty (the next link is not always
+// accurate, so a full last page may be followed by an empty one), on the first error, or when the context is done.
+//
+//	for job, err := range modzy.NewListJobsHistoryPager(client.Jobs(), input).All(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(job.JobIdentifier)
+//	}
+type Pager[T any] struct {
+	first    func(ctx context.Context) pageFetcher[T]
+	prefetch bool
+}
+
+// pageFetcher returns the next page each time it is called; more is false once there are no pages left.
+type pageFetcher[T any] func() (items []T, more bool, err error)
+
+// newPager creates a Pager for a List endpoint whose output links to the next input.  The input is copied so that the
+// Pager can be iterated more than once.
+func newPager[I any, T any](input *I, list func(ctx context.Context, input *I) ([]T, *I, error)) *Pager[T] {
+	return &Pager[T]{
+		first: func(ctx context.Context) pageFetcher[T] {
+			next := *input
+			current := &next
+			return func() ([]T, bool, error) {
+				items, nextInput, err := list(ctx, current)
+				if err != nil {
+					return nil, false, err
+				}
+				current = nextInput
+				return items, nextInput != nil && len(items) > 0, nil
+			}
+		},
+	}
+}
+
+// NewListJobsHistoryPager pages through ListJobsHistory starting at the provided input.
+func NewListJobsHistoryPager(client JobsClient, input *ListJobsHistoryInput) *Pager[model.JobDetails] {
+	return newPager(input, func(ctx context.Context, input *ListJobsHistoryInput) ([]model.JobDetails, *ListJobsHistoryInput, error) {
+		out, err := client.ListJobsHistory(ctx, input)
+		if err != nil {
+			return nil, nil, err
+		}
+		return out.Jobs, out.NextPage, nil
+	})
+}
+
+// NewListModelsPager pages through ListModels starting at the provided input.
+func NewListModelsPager(client ModelsClient, input *ListModelsInput) *Pager[model.ModelVersionSummary] {
+	return newPager(input, func(ctx context.Context, input *ListModelsInput) ([]model.ModelVersionSummary, *ListModelsInput, error) {
+		out, err := client.ListModels(ctx, input)
+		if err != nil {
+			return nil, nil, err
+		}
+		return out.Models, out.NextPage, nil
+	})
+}
+
+// NewListModelVersionsPager pages through ListModelVersions starting at the provided input.
+func NewListModelVersionsPager(client ModelsClient, input *ListModelVersionsInput) *Pager[model.ModelVersion] {
+	return newPager(input, func(ctx context.Context, input *ListModelVersionsInput) ([]model.ModelVersion, *ListModelVersionsInput, error) {
+		out, err := client.ListModelVersions(ctx, input)
+		if err != nil {
+			return nil, nil, err
+		}
+		return out.Versions, out.NextPage, nil
+	})
+}
+
+// NewListAccountingUsersPager pages through ListAccountingUsers starting at the provided input.
+func NewListAccountingUsersPager(client AccountingClient, input *ListAccountingUsersInput) *Pager[model.AccountingUser] {
+	return newPager(input, func(ctx context.Context, input *ListAccountingUsersInput) ([]model.AccountingUser, *ListAccountingUsersInput, error) {
+		out, err := client.ListAccountingUsers(ctx, input)
+		if err != nil {
+			return nil, nil, err
+		}
+		return out.Users, out.NextPage, nil
+	})
+}
+
+// NewListProjectsPager pages through ListProjects starting at the provided input.
+func NewListProjectsPager(client AccountingClient, input *ListProjectsInput) *Pager[model.AccountingProject] {
+	return newPager(input, func(ctx context.Context, input *ListProjectsInput) ([]model.AccountingProject, *ListProjectsInput, error) {
+		out, err := client.ListProjects(ctx, input)
+		if err != nil {
+			return nil, nil, err
+		}
+		return out.Projects, out.NextPage, nil
+	})
+}
+
+// NewListTeamsPager pages through ListTeams starting at the provided input.
+func NewListTeamsPager(client AccountingClient, input *ListTeamsInput) *Pager[model.AccountingTeam] {
+	return newPager(input, func(ctx context.Context, input *ListTeamsInput) ([]model.AccountingTeam, *ListTeamsInput, error) {
+		out, err := client.ListTeams(ctx, input)
+		if err != nil {
+			return nil, nil, err
+		}
+		return out.Teams, out.NextPage, nil
+	})
+}
+
+// WithPrefetch requests the next page in the background while the current page is being consumed.
+func (p *Pager[T]) WithPrefetch() *Pager[T] {
+	p.prefetch = true
+	return p
+}
+
+// Pages iterates over each non-empty page.  An error is yielded at most once, after which iteration stops.
+func (p *Pager[T]) Pages(ctx context.Context) iter.Seq2[[]T, error] {
+	return func(yield func([]T, error) bool) {
+		if p.prefetch {
+			p.prefetchPages(ctx, yield)
+			return
+		}
+		fetch := p.first(ctx)
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			items, more, err := fetch()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if len(items) > 0 && !yield(items, nil) {
+				return
+			}
+			if !more {
+				return
+			}
+		}
+	}
+}
+
+type fetchedPage[T any] struct {
+	items []T
+	err   error
+}
+
+// prefetchPages fetches pages in a goroutine which stays one page ahead of the consumer
+func (p *Pager[T]) prefetchPages(ctx context.Context, yield func([]T, error) bool) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	pages := make(chan fetchedPage[T])
+
+	go func() {
+		defer close(pages)
+		fetch := p.first(ctx)
+		for {
+			if err := ctx.Err(); err != nil {
+				pages <- fetchedPage[T]{err: err}
+				return
+			}
+			items, more, err := fetch()
+			pages <- fetchedPage[T]{items: items, err: err}
+			if err != nil || !more {
+				return
+			}
+		}
+	}()
+
+	for page := range pages {
+		if page.err != nil {
+			yield(nil, page.err)
+			break
+		}
+		if len(page.items) > 0 && !yield(page.items, nil) {
+			break
+		}
+	}
+	// stop the fetching goroutine and drain whatever it was about to hand over
+	cancel()
+	for range pages {
+	}
+}
+
+// All iterates over every item of every page.  An error is yielded at most once, after which iteration stops.
+func (p *Pager[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for items, err := range p.Pages(ctx) {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ListAll reads items until there are no pages left or the limit is reached.  A limit of zero or less reads everything.
+// The items read before an error are returned along with the error.
+func (p *Pager[T]) ListAll(ctx context.Context, limit int) ([]T, error) {
+	items := []T{}
+	for item, err := range p.All(ctx) {
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+		if limit > 0 && len(items) >= limit {
+			break
+		}
+	}
+	return items, nil
+}
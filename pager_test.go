@@ -0,0 +1,188 @@
+package modzy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+// pagedJobsClient serves jobs named after their page; the last page is full but followed by an empty page
+func pagedJobsClient(pages int, requested *[]int) *JobsClientFake {
+	return &JobsClientFake{
+		ListJobsHistoryFunc: func(ctx context.Context, input *ListJobsHistoryInput) (*ListJobsHistoryOutput, error) {
+			input.Paging = input.Paging.withDefaults()
+			*requested = append(*requested, input.Paging.Page)
+			out := &ListJobsHistoryOutput{}
+			if input.Paging.Page > pages {
+				out.NextPage = &ListJobsHistoryInput{Paging: input.Paging.Next()}
+				return out, nil
+			}
+			for i := 0; i < input.Paging.PerPage; i++ {
+				out.Jobs = append(out.Jobs, model.JobDetails{JobIdentifier: fmt.Sprintf("%d-%d", input.Paging.Page, i)})
+			}
+			out.NextPage = &ListJobsHistoryInput{Paging: input.Paging.Next()}
+			return out, nil
+		},
+	}
+}
+
+func TestPagerAll(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		requested := []int{}
+		pager := NewListJobsHistoryPager(pagedJobsClient(3, &requested), (&ListJobsHistoryInput{}).WithPaging(2, 1))
+		if prefetch {
+			pager = pager.WithPrefetch()
+		}
+		ids := []string{}
+		for job, err := range pager.All(context.TODO()) {
+			if err != nil {
+				t.Fatalf("err not nil: %v", err)
+			}
+			ids = append(ids, job.JobIdentifier)
+		}
+		if len(ids) != 6 || ids[0] != "1-0" || ids[5] != "3-1" {
+			t.Errorf("prefetch %v: items not expected: %v", prefetch, ids)
+		}
+		// the empty page ends the iteration
+		if len(requested) != 4 {
+			t.Errorf("prefetch %v: pages requested not expected: %v", prefetch, requested)
+		}
+	}
+}
+
+func TestPagerIsReusable(t *testing.T) {
+	requested := []int{}
+	input := (&ListJobsHistoryInput{}).WithPaging(2, 1)
+	pager := NewListJobsHistoryPager(pagedJobsClient(1, &requested), input)
+	first, _ := pager.ListAll(context.TODO(), 0)
+	second, _ := pager.ListAll(context.TODO(), 0)
+	if len(first) != 2 || len(second) != 2 {
+		t.Errorf("pager not reusable: %d, %d", len(first), len(second))
+	}
+	if input.Paging.Page != 1 {
+		t.Errorf("input should not be changed")
+	}
+}
+
+func TestPagerListAllLimit(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		requested := []int{}
+		pager := NewListJobsHistoryPager(pagedJobsClient(10, &requested), (&ListJobsHistoryInput{}).WithPaging(2, 1))
+		if prefetch {
+			pager = pager.WithPrefetch()
+		}
+		items, err := pager.ListAll(context.TODO(), 3)
+		if err != nil {
+			t.Fatalf("err not nil: %v", err)
+		}
+		if len(items) != 3 {
+			t.Errorf("prefetch %v: limit not respected: %d", prefetch, len(items))
+		}
+		if len(requested) > 3 {
+			t.Errorf("prefetch %v: too many pages requested: %v", prefetch, requested)
+		}
+	}
+}
+
+func TestPagerError(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		calls := 0
+		client := &JobsClientFake{
+			ListJobsHistoryFunc: func(ctx context.Context, input *ListJobsHistoryInput) (*ListJobsHistoryOutput, error) {
+				calls++
+				if calls == 2 {
+					return nil, fmt.Errorf("nope")
+				}
+				return &ListJobsHistoryOutput{
+					Jobs:     []model.JobDetails{{}},
+					NextPage: &ListJobsHistoryInput{},
+				}, nil
+			},
+		}
+		pager := NewListJobsHistoryPager(client, &ListJobsHistoryInput{})
+		if prefetch {
+			pager = pager.WithPrefetch()
+		}
+		items, err := pager.ListAll(context.TODO(), 0)
+		if err == nil {
+			t.Errorf("prefetch %v: expected an error", prefetch)
+		}
+		if len(items) != 1 {
+			t.Errorf("prefetch %v: items read before the error not returned: %d", prefetch, len(items))
+		}
+	}
+}
+
+func TestPagerContextCanceled(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		requested := []int{}
+		ctx, cancel := context.WithCancel(context.TODO())
+		pager := NewListJobsHistoryPager(pagedJobsClient(10, &requested), (&ListJobsHistoryInput{}).WithPaging(1, 1))
+		if prefetch {
+			pager = pager.WithPrefetch()
+		}
+		var lastErr error
+		count := 0
+		for _, err := range pager.All(ctx) {
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			count++
+			if count == 2 {
+				cancel()
+			}
+		}
+		if lastErr != context.Canceled {
+			t.Errorf("prefetch %v: expected context canceled, got %v", prefetch, lastErr)
+		}
+		if count > 3 {
+			t.Errorf("prefetch %v: iteration did not stop: %d", prefetch, count)
+		}
+		cancel()
+	}
+}
+
+func TestPagerConstructors(t *testing.T) {
+	ctx := context.TODO()
+	models := &ModelsClientFake{
+		ListModelsFunc: func(ctx context.Context, input *ListModelsInput) (*ListModelsOutput, error) {
+			return &ListModelsOutput{Models: []model.ModelVersionSummary{{ID: "m"}}}, nil
+		},
+		ListModelVersionsFunc: func(ctx context.Context, input *ListModelVersionsInput) (*ListModelVersionsOutput, error) {
+			if input.ModelID != "m" {
+				t.Errorf("model not passed: %s", input.ModelID)
+			}
+			return &ListModelVersionsOutput{Versions: []model.ModelVersion{{Version: "1"}}}, nil
+		},
+	}
+	accounting := &AccountingClientFake{
+		ListAccountingUsersFunc: func(ctx context.Context, input *ListAccountingUsersInput) (*ListAccountingUsersOutput, error) {
+			return &ListAccountingUsersOutput{Users: []model.AccountingUser{{}}}, nil
+		},
+		ListProjectsFunc: func(ctx context.Context, input *ListProjectsInput) (*ListProjectsOutput, error) {
+			return &ListProjectsOutput{Projects: []model.AccountingProject{{}}}, nil
+		},
+		ListTeamsFunc: func(ctx context.Context, input *ListTeamsInput) (*ListTeamsOutput, error) {
+			return &ListTeamsOutput{Teams: []model.AccountingTeam{{}}}, nil
+		},
+	}
+
+	if items, err := NewListModelsPager(models, &ListModelsInput{}).ListAll(ctx, 0); err != nil || len(items) != 1 {
+		t.Errorf("models not listed: %v, %v", items, err)
+	}
+	if items, err := NewListModelVersionsPager(models, &ListModelVersionsInput{ModelID: "m"}).ListAll(ctx, 0); err != nil || len(items) != 1 {
+		t.Errorf("versions not listed: %v, %v", items, err)
+	}
+	if items, err := NewListAccountingUsersPager(accounting, &ListAccountingUsersInput{}).ListAll(ctx, 0); err != nil || len(items) != 1 {
+		t.Errorf("users not listed: %v, %v", items, err)
+	}
+	if items, err := NewListProjectsPager(accounting, &ListProjectsInput{}).ListAll(ctx, 0); err != nil || len(items) != 1 {
+		t.Errorf("projects not listed: %v, %v", items, err)
+	}
+	if items, err := NewListTeamsPager(accounting, &ListTeamsInput{}).ListAll(ctx, 0); err != nil || len(items) != 1 {
+		t.Errorf("teams not listed: %v, %v", items, err)
+	}
+}
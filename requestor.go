@@ -27,12 +27,41 @@ type requestor struct {
 	requestDebugging       bool
 	responseDebugging      bool
 	httpClient             *http.Client
+	// onForbidden is called whenever the API answers with a 403
+	onForbidden func()
 }
 
 func (r *requestor) execute(
 	ctx context.Context,
 	path string, method string, toPostInput interface{}, into interface{},
 	reqDecorator requestDecorator,
+) (*http.Response, error) {
+	resp, err := r.send(ctx, path, method, toPostInput, reqDecorator, r.responseDebugging)
+	if err != nil {
+		return resp, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		// No Content
+		// Do not bother with response body stuff; none expected
+	} else {
+		if into != nil {
+			if err := json.NewDecoder(resp.Body).Decode(into); err != nil {
+				return resp, errors.WithMessagef(err, "failed parsing the response from %s:%s", method, path)
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// send performs the request and handles any error responses.  On success the caller is responsible for closing the response body.
+func (r *requestor) send(
+	ctx context.Context,
+	path string, method string, toPostInput interface{},
+	reqDecorator requestDecorator,
+	debugResponseBody bool,
 ) (*http.Response, error) {
 	url := fmt.Sprintf("%s%s", r.baseURL, path)
 
@@ -87,42 +116,37 @@ func (r *requestor) execute(
 	if err != nil {
 		return nil, errors.WithMessagef(err, "failed to executing request to %s:%s", method, path)
 	}
-	defer resp.Body.Close()
-
-	var toDecode io.Reader = resp.Body
 
 	if r.responseDebugging {
-		body, debugErr := ioutil.ReadAll(resp.Body)
+		bodyDebug := "stream requested, will not read"
+		if debugResponseBody {
+			body, debugErr := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+			bodyDebug = fmt.Sprintf("%v => %s", debugErr, string(body))
+		}
 		logrus.WithFields(logrus.Fields{
 			"method":     req.Method,
 			"url":        req.URL,
 			"statusCode": resp.StatusCode,
 			// "headers":    resp.Header,
-			"body": fmt.Sprintf("%v => %s", debugErr, string(body)),
+			"body": bodyDebug,
 		}).Debug("API response")
-		toDecode = bytes.NewReader(body)
 	}
 
 	if resp.StatusCode >= 400 {
 		// non OK response
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusForbidden && r.onForbidden != nil {
+			r.onForbidden()
+		}
 		apiError := &ModzyHTTPError{}
-		if err := json.NewDecoder(toDecode).Decode(apiError); err != nil {
+		if err := json.NewDecoder(resp.Body).Decode(apiError); err != nil {
 			return resp, fmt.Errorf("request to %s failed with response: %s", req.URL, resp.Status)
 		}
 		return resp, apiError
 	}
 
-	if resp.StatusCode == 204 {
-		// No Content
-		// Do not bother with response body stuff; none expected
-	} else {
-		if into != nil {
-			if err := json.NewDecoder(toDecode).Decode(into); err != nil {
-				return resp, errors.WithMessagef(err, "failed parsing the response from %s:%s", method, path)
-			}
-		}
-	}
-
 	return resp, nil
 }
 
@@ -130,6 +154,11 @@ func (r *requestor) Get(ctx context.Context, path string, into interface{}) (*ht
 	return r.execute(ctx, path, "GET", nil, into, jsonDecorator)
 }
 
+// GetStream performs a GET request and returns the response without reading the body.  The caller must close the response body.
+func (r *requestor) GetStream(ctx context.Context, path string) (*http.Response, error) {
+	return r.send(ctx, path, "GET", nil, nil, false)
+}
+
 func (r *requestor) List(ctx context.Context, path string, paging PagingInput, into interface{}) (*http.Response, link.Group, error) {
 	// append paging information to our url query
 	partialUrl, err := url.Parse(path)
@@ -180,6 +209,10 @@ func (r *requestor) Patch(ctx context.Context, path string, toPatch interface{},
 	return r.execute(ctx, path, "PATCH", toPatch, into, jsonDecorator)
 }
 
+func (r *requestor) Put(ctx context.Context, path string, toPut interface{}, into interface{}) (*http.Response, error) {
+	return r.execute(ctx, path, "PUT", toPut, into, jsonDecorator)
+}
+
 func (r *requestor) Delete(ctx context.Context, path string, into interface{}) (*http.Response, error) {
 	return r.execute(ctx, path, "DELETE", nil, into, jsonDecorator)
 }
@@ -119,6 +119,38 @@ func TestPost(t *testing.T) {
 	}
 }
 
+func TestPut(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Errorf("method not PUT: %s", r.Method)
+		}
+		if r.RequestURI != "/the/put/path" {
+			t.Errorf("put url not expected: %s", r.RequestURI)
+		}
+		var received string
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		if received != "put-data" {
+			t.Errorf("received payload not correct")
+		}
+		w.Write([]byte(`"some-response"`))
+	}))
+	defer serv.Close()
+
+	requestor := &requestor{
+		baseURL:    serv.URL,
+		httpClient: defaultHTTPClient,
+	}
+
+	var into string
+	_, err := requestor.Put(context.TODO(), "/the/put/path", "put-data", &into)
+	if err != nil {
+		t.Errorf("err not nil: %v", err)
+	}
+	if into != "some-response" {
+		t.Errorf("response not parsed into: %s", into)
+	}
+}
+
 func TestDelete(t *testing.T) {
 	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "DELETE" {
@@ -350,3 +382,47 @@ type cannotMarshal struct{}
 func (cannotMarshal) MarshalJSON() ([]byte, error) {
 	return nil, fmt.Errorf("will-not-marshal")
 }
+
+func TestGetStream(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("method not GET: %s", r.Method)
+		}
+		w.Write([]byte(`raw bytes`))
+	}))
+	defer serv.Close()
+
+	requestor := &requestor{
+		baseURL:           serv.URL,
+		httpClient:        defaultHTTPClient,
+		responseDebugging: true,
+	}
+
+	resp, err := requestor.GetStream(context.TODO(), "/the/path")
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	defer resp.Body.Close()
+	b, _ := ioutil.ReadAll(resp.Body)
+	if string(b) != "raw bytes" {
+		t.Errorf("body not left for the caller: %s", string(b))
+	}
+}
+
+func TestGetStreamWithModzyError(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+		w.Write([]byte(`{"statusCode":404,"message":"not here"}`))
+	}))
+	defer serv.Close()
+
+	requestor := &requestor{
+		baseURL:    serv.URL,
+		httpClient: defaultHTTPClient,
+	}
+
+	_, err := requestor.GetStream(context.TODO(), "/the/path")
+	if err == nil || err.Error() != "not here" {
+		t.Errorf("expected modzy error: %v", err)
+	}
+}
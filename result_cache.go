@@ -0,0 +1,258 @@
+package modzy
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+// CacheableSubmission is a job submission whose inputs can be identified by their content.
+//
+// Provided implementations are:
+//
+//	*SubmitJobTextInput
+//	*SubmitJobEmbeddedInput
+//	*SubmitJobFileInput
+type CacheableSubmission interface {
+	RetryableSubmission
+	// hashInputs reads every input and returns a copy of the submission backed by the data that was read, along with
+	// a content hash of each input keyed by input key.
+	hashInputs() (CacheableSubmission, map[string]string, error)
+	cacheScope() resultCacheScope
+}
+
+var (
+	_ CacheableSubmission = &SubmitJobTextInput{}
+	_ CacheableSubmission = &SubmitJobEmbeddedInput{}
+	_ CacheableSubmission = &SubmitJobFileInput{}
+)
+
+func (i *SubmitJobTextInput) cacheScope() resultCacheScope {
+	return resultCacheScope{
+		model:   model.ModelIdentifier{Identifier: i.ModelIdentifier, Version: i.ModelVersion},
+		explain: i.Explain,
+	}
+}
+
+func (i *SubmitJobTextInput) hashInputs() (CacheableSubmission, map[string]string, error) {
+	hashes := map[string]string{}
+	for k, v := range i.Inputs {
+		items := map[string][]byte{}
+		for innerK, innerV := range v {
+			items[innerK] = []byte(innerV)
+		}
+		hashes[k] = hashInputItems(items)
+	}
+	return i, hashes, nil
+}
+
+func (i *SubmitJobEmbeddedInput) cacheScope() resultCacheScope {
+	return resultCacheScope{
+		model:   model.ModelIdentifier{Identifier: i.ModelIdentifier, Version: i.ModelVersion},
+		explain: i.Explain,
+	}
+}
+
+func (i *SubmitJobEmbeddedInput) hashInputs() (CacheableSubmission, map[string]string, error) {
+	hashes := map[string]string{}
+	buffered := *i
+	buffered.Inputs = map[string]EmbeddedInputItem{}
+	for k, v := range i.Inputs {
+		items := map[string][]byte{}
+		bufferedItem := EmbeddedInputItem{}
+		for innerK, innerV := range v {
+			data, err := readInputItem(innerV)
+			if err != nil {
+				return nil, nil, errors.WithMessagef(err, "failed to read data for item %s/%s", k, innerK)
+			}
+			items[innerK] = data
+			bufferedItem[innerK] = URIEncodedString(string(data))
+		}
+		hashes[k] = hashInputItems(items)
+		buffered.Inputs[k] = bufferedItem
+	}
+	return &buffered, hashes, nil
+}
+
+func (i *SubmitJobFileInput) cacheScope() resultCacheScope {
+	return resultCacheScope{
+		model:   model.ModelIdentifier{Identifier: i.ModelIdentifier, Version: i.ModelVersion},
+		explain: i.Explain,
+	}
+}
+
+func (i *SubmitJobFileInput) hashInputs() (CacheableSubmission, map[string]string, error) {
+	hashes := map[string]string{}
+	buffered := *i
+	buffered.Inputs = map[string]FileInputItem{}
+	for k, v := range i.Inputs {
+		items := map[string][]byte{}
+		bufferedItem := FileInputItem{}
+		for innerK, innerV := range v {
+			data, err := readInputItem(innerV)
+			if err != nil {
+				return nil, nil, errors.WithMessagef(err, "failed to read data for item %s/%s", k, innerK)
+			}
+			items[innerK] = data
+			bufferedItem[innerK] = FileInputReader(bytes.NewReader(data))
+		}
+		hashes[k] = hashInputItems(items)
+		buffered.Inputs[k] = bufferedItem
+	}
+	return &buffered, hashes, nil
+}
+
+func readInputItem(source func() (io.Reader, error)) ([]byte, error) {
+	r, err := source()
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// hashInputItems hashes the items of a single input in a stable order
+func hashInputItems(items map[string][]byte) string {
+	names := make([]string, 0, len(items))
+	for name := range items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		// lengths are included so that item boundaries cannot be confused
+		fmt.Fprintf(h, "%d:%s:%d:", len(name), name, len(items[name]))
+		h.Write(items[name])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// resultCacheScope is everything about a submission, other than its inputs, that changes the results.
+// Timeout and ChunkSize only change how a job is run and are left out.
+type resultCacheScope struct {
+	model   model.ModelIdentifier
+	explain bool
+}
+
+// resultCacheKey combines the scope and the content hash of an input
+func resultCacheKey(scope resultCacheScope, inputHash string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s@%s:explain=%t:%s", scope.model.Identifier, scope.model.Version, scope.explain, inputHash)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ResultCache sits in front of the submit, wait and results flow.  Inputs that were already processed by the same model
+// version, with the same Explain setting, are answered from the cache, and only the remaining inputs are submitted as a job.
+// Only successful results are cached.
+type ResultCache struct {
+	client       Client
+	store        ResultCacheStore
+	ttl          time.Duration
+	pollInterval time.Duration
+}
+
+// NewResultCache creates a ResultCache backed by the provided store.  Results expire after the ttl; a ttl of zero never expires.
+func NewResultCache(client Client, store ResultCacheStore, ttl time.Duration) *ResultCache {
+	return &ResultCache{
+		client:       client,
+		store:        store,
+		ttl:          ttl,
+		pollInterval: defaultRetryPollInterval,
+	}
+}
+
+// WithPollInterval sets how often jobs are checked while waiting for the cache misses to finish processing.
+func (c *ResultCache) WithPollInterval(pollInterval time.Duration) *ResultCache {
+	c.pollInterval = pollInterval
+	return c
+}
+
+// Run answers each input of the submission from the cache if possible, submits the rest as a single job and waits for
+// it to finish, and then caches the new successful results.
+//
+// Once a job has been submitted, an error is returned along with the output so that the Job can still be followed up.
+// Its Results are only complete if the error was in caching the new results; otherwise they hold just the cached hits.
+func (c *ResultCache) Run(ctx context.Context, submission CacheableSubmission) (*ResultCacheRunOutput, error) {
+	buffered, hashes, err := submission.hashInputs()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to hash the inputs")
+	}
+	scope := submission.cacheScope()
+
+	out := &ResultCacheRunOutput{
+		Results: model.JobResults{
+			Results:  map[string]model.JobResult{},
+			Failures: map[string]model.JobResult{},
+		},
+		CachedInputs: []string{},
+	}
+
+	misses := []string{}
+	for _, inputKey := range buffered.InputKeys() {
+		cached, hit, err := c.store.Get(ctx, resultCacheKey(scope, hashes[inputKey]))
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to read the cache for input %s", inputKey)
+		}
+		if hit {
+			out.Results.Results[inputKey] = *cached
+			out.CachedInputs = append(out.CachedInputs, inputKey)
+		} else {
+			misses = append(misses, inputKey)
+		}
+	}
+
+	if len(misses) > 0 {
+		submitted, err := buffered.submitSubset(ctx, c.client, scope.model, misses)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to submit the inputs that were not cached")
+		}
+		out.Job = submitted.JobActions
+
+		if _, err := submitted.WaitForCompletion(ctx, c.pollInterval); err != nil {
+			return out, errors.WithMessage(err, "failed waiting for the inputs that were not cached")
+		}
+		jobResults, err := submitted.GetResults(ctx)
+		if err != nil {
+			return out, errors.WithMessage(err, "failed to read the results of the inputs that were not cached")
+		}
+
+		cachedResults := out.Results.Results
+		out.Results = copyJobResults(jobResults.Results)
+		for inputKey, result := range cachedResults {
+			out.Results.Results[inputKey] = result
+		}
+
+		for inputKey, result := range jobResults.Results.Results {
+			if err := c.store.Set(ctx, resultCacheKey(scope, hashes[inputKey]), result, c.ttl); err != nil {
+				countResultCacheRun(out, len(hashes))
+				return out, errors.WithMessagef(err, "failed to cache the result of input %s", inputKey)
+			}
+		}
+	}
+
+	countResultCacheRun(out, len(hashes))
+	return out, nil
+}
+
+func countResultCacheRun(out *ResultCacheRunOutput, total int) {
+	out.Results.Total = total
+	out.Results.Completed = len(out.Results.Results)
+	out.Results.Failed = len(out.Results.Failures)
+}
+
+type ResultCacheRunOutput struct {
+	// Results combine the cached results with the results of the submitted job
+	Results model.JobResults
+	// CachedInputs are the input keys that were answered from the cache
+	CachedInputs []string
+	// Job is the job submitted for the inputs that were not cached; nil if every input was cached.
+	Job JobActions
+}
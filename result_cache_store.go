@@ -0,0 +1,146 @@
+package modzy
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// ResultCacheStore holds cached results for a ResultCache.  Implementations must be safe for concurrent use.
+//
+// Provided implementations are:
+//
+//	NewMemoryResultCacheStore
+//	NewDiskResultCacheStore
+type ResultCacheStore interface {
+	// Get returns the cached result for the key, if it exists and has not expired
+	Get(ctx context.Context, key string) (*model.JobResult, bool, error)
+	// Set caches the result for the key.  A ttl of zero never expires.
+	Set(ctx context.Context, key string, result model.JobResult, ttl time.Duration) error
+	// Delete removes the key from the cache
+	Delete(ctx context.Context, key string) error
+}
+
+type cachedResult struct {
+	ExpiresAt time.Time       `json:"expiresAt"`
+	Result    model.JobResult `json:"result"`
+}
+
+func newCachedResult(result model.JobResult, ttl time.Duration) cachedResult {
+	cached := cachedResult{Result: result}
+	if ttl > 0 {
+		cached.ExpiresAt = time.Now().Add(ttl)
+	}
+	return cached
+}
+
+func (c cachedResult) expired() bool {
+	return !c.ExpiresAt.IsZero() && time.Now().After(c.ExpiresAt)
+}
+
+type memoryResultCacheStore struct {
+	sync.Mutex
+	entries map[string]cachedResult
+}
+
+// NewMemoryResultCacheStore creates a ResultCacheStore that only lives as long as the process.
+func NewMemoryResultCacheStore() ResultCacheStore {
+	return &memoryResultCacheStore{
+		entries: map[string]cachedResult{},
+	}
+}
+
+func (s *memoryResultCacheStore) Get(ctx context.Context, key string) (*model.JobResult, bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	cached, has := s.entries[key]
+	if !has {
+		return nil, false, nil
+	}
+	if cached.expired() {
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+	return &cached.Result, true, nil
+}
+
+func (s *memoryResultCacheStore) Set(ctx context.Context, key string, result model.JobResult, ttl time.Duration) error {
+	s.Lock()
+	defer s.Unlock()
+	s.entries[key] = newCachedResult(result, ttl)
+	return nil
+}
+
+func (s *memoryResultCacheStore) Delete(ctx context.Context, key string) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+type diskResultCacheStore struct {
+	directory string
+}
+
+// NewDiskResultCacheStore creates a ResultCacheStore that keeps one json file per cached result within the directory.
+// Files are written through AppFs.
+func NewDiskResultCacheStore(directory string) ResultCacheStore {
+	return &diskResultCacheStore{
+		directory: directory,
+	}
+}
+
+func (s *diskResultCacheStore) path(key string) string {
+	return filepath.Join(s.directory, key+".json")
+}
+
+func (s *diskResultCacheStore) Get(ctx context.Context, key string) (*model.JobResult, bool, error) {
+	b, err := afero.ReadFile(AppFs, s.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, errors.WithMessagef(err, "failed to read cached result %s", key)
+	}
+	var cached cachedResult
+	if err := json.Unmarshal(b, &cached); err != nil {
+		return nil, false, errors.WithMessagef(err, "failed to parse cached result %s", key)
+	}
+	if cached.expired() {
+		return nil, false, s.Delete(ctx, key)
+	}
+	return &cached.Result, true, nil
+}
+
+func (s *diskResultCacheStore) Set(ctx context.Context, key string, result model.JobResult, ttl time.Duration) error {
+	b, err := json.Marshal(newCachedResult(result, ttl))
+	if err != nil {
+		return errors.WithMessagef(err, "failed to encode cached result %s", key)
+	}
+	if err := AppFs.MkdirAll(s.directory, 0755); err != nil {
+		return errors.WithMessagef(err, "failed to create cache directory %s", s.directory)
+	}
+	// write then rename so that readers never see a partial file
+	tmp := s.path(key) + ".tmp"
+	if err := afero.WriteFile(AppFs, tmp, b, 0644); err != nil {
+		return errors.WithMessagef(err, "failed to write cached result %s", key)
+	}
+	if err := AppFs.Rename(tmp, s.path(key)); err != nil {
+		return errors.WithMessagef(err, "failed to write cached result %s", key)
+	}
+	return nil
+}
+
+func (s *diskResultCacheStore) Delete(ctx context.Context, key string) error {
+	if err := AppFs.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+		return errors.WithMessagef(err, "failed to delete cached result %s", key)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package modzy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/spf13/afero"
+)
+
+func testResultCacheStore(t *testing.T, store ResultCacheStore) {
+	ctx := context.TODO()
+
+	if _, hit, err := store.Get(ctx, "missing"); hit || err != nil {
+		t.Errorf("missing key should be a miss: %v, %v", hit, err)
+	}
+
+	if err := store.Set(ctx, "key", model.JobResult{Status: "SUCCESSFUL"}, 0); err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	result, hit, err := store.Get(ctx, "key")
+	if err != nil || !hit {
+		t.Fatalf("expected a hit: %v, %v", hit, err)
+	}
+	if result.Status != "SUCCESSFUL" {
+		t.Errorf("result not expected: %+v", result)
+	}
+
+	if err := store.Delete(ctx, "key"); err != nil {
+		t.Errorf("err not nil: %v", err)
+	}
+	if _, hit, _ := store.Get(ctx, "key"); hit {
+		t.Errorf("deleted key should be a miss")
+	}
+	if err := store.Delete(ctx, "key"); err != nil {
+		t.Errorf("deleting a missing key should not fail: %v", err)
+	}
+
+	if err := store.Set(ctx, "expiring", model.JobResult{}, time.Nanosecond); err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	if _, hit, _ := store.Get(ctx, "expiring"); hit {
+		t.Errorf("expired key should be a miss")
+	}
+}
+
+func TestMemoryResultCacheStore(t *testing.T) {
+	testResultCacheStore(t, NewMemoryResultCacheStore())
+}
+
+func TestDiskResultCacheStore(t *testing.T) {
+	AppFs = afero.NewMemMapFs()
+	testResultCacheStore(t, NewDiskResultCacheStore("cache"))
+
+	if err := NewDiskResultCacheStore("cache").Set(context.TODO(), "kept", model.JobResult{}, 0); err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if exists, _ := afero.Exists(AppFs, "cache/kept.json"); !exists {
+		t.Errorf("cached result not written to the directory")
+	}
+	if exists, _ := afero.Exists(AppFs, "cache/expiring.json"); exists {
+		t.Errorf("expired result not removed")
+	}
+}
+
+func TestDiskResultCacheStoreCorrupt(t *testing.T) {
+	AppFs = afero.NewMemMapFs()
+	afero.WriteFile(AppFs, "cache/bad.json", []byte("nope"), 0644)
+	if _, _, err := NewDiskResultCacheStore("cache").Get(context.TODO(), "bad"); err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -0,0 +1,233 @@
+package modzy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+func TestCacheableSubmissionHashInputs(t *testing.T) {
+	text := &SubmitJobTextInput{Inputs: map[string]TextInputItem{
+		"a": {"input.txt": "same"},
+		"b": {"input.txt": "same"},
+		"c": {"input.txt": "different"},
+	}}
+	_, textHashes, err := text.hashInputs()
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if textHashes["a"] != textHashes["b"] || textHashes["a"] == textHashes["c"] {
+		t.Errorf("text hashes not expected: %v", textHashes)
+	}
+
+	file := &SubmitJobFileInput{Inputs: map[string]FileInputItem{
+		"a": {"input.txt": FileInputReader(strings.NewReader("same"))},
+	}}
+	bufferedFile, fileHashes, err := file.hashInputs()
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if fileHashes["a"] != textHashes["a"] {
+		t.Errorf("same content should hash the same regardless of input type")
+	}
+	// the original reader was consumed, so the buffered copy must be readable
+	r, _ := bufferedFile.(*SubmitJobFileInput).Inputs["a"]["input.txt"]()
+	if b, _ := io.ReadAll(r); string(b) != "same" {
+		t.Errorf("buffered file input not readable: %s", b)
+	}
+
+	embedded := &SubmitJobEmbeddedInput{Inputs: map[string]EmbeddedInputItem{
+		"a": {"input.txt": URIEncodeString("same", "text/plain")},
+	}}
+	_, embeddedHashes, err := embedded.hashInputs()
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if embeddedHashes["a"] == "" {
+		t.Errorf("embedded input not hashed")
+	}
+
+	failing := &SubmitJobFileInput{Inputs: map[string]FileInputItem{
+		"a": {"input.txt": func() (io.Reader, error) { return nil, fmt.Errorf("nope") }},
+	}}
+	if _, _, err := failing.hashInputs(); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestResultCacheKey(t *testing.T) {
+	a := resultCacheKey(resultCacheScope{model: model.ModelIdentifier{Identifier: "m", Version: "1.0.0"}}, "hash")
+	b := resultCacheKey(resultCacheScope{model: model.ModelIdentifier{Identifier: "m", Version: "2.0.0"}}, "hash")
+	if a == b {
+		t.Errorf("model version should be part of the key")
+	}
+	c := resultCacheKey(resultCacheScope{model: model.ModelIdentifier{Identifier: "m", Version: "1.0.0"}, explain: true}, "hash")
+	if a == c {
+		t.Errorf("explain should be part of the key")
+	}
+}
+
+func TestResultCacheRun(t *testing.T) {
+	submitted := []*SubmitJobTextInput{}
+	client := &ClientFake{}
+	client.JobsFunc = func() JobsClient {
+		return &JobsClientFake{
+			SubmitJobTextFunc: func(ctx context.Context, input *SubmitJobTextInput) (*SubmitJobTextOutput, error) {
+				submitted = append(submitted, input)
+				return &SubmitJobTextOutput{
+					JobActions: NewJobActions(client, fmt.Sprintf("job-%d", len(submitted))),
+				}, nil
+			},
+			WaitForJobCompletionFunc: func(ctx context.Context, input *WaitForJobCompletionInput, pollInterval time.Duration) (*GetJobDetailsOutput, error) {
+				if pollInterval != time.Millisecond {
+					t.Errorf("poll interval not used: %v", pollInterval)
+				}
+				return &GetJobDetailsOutput{}, nil
+			},
+			GetJobResultsFunc: func(ctx context.Context, input *GetJobResultsInput) (*GetJobResultsOutput, error) {
+				results := model.JobResults{
+					JobIdentifier: input.JobIdentifier,
+					Results:       map[string]model.JobResult{},
+					Failures:      map[string]model.JobResult{},
+				}
+				for _, k := range submitted[len(submitted)-1].InputKeys() {
+					if k == "bad" {
+						results.Failures[k] = model.JobResult{Status: "FAILED"}
+					} else {
+						results.Results[k] = model.JobResult{Status: "SUCCESSFUL", Engine: input.JobIdentifier}
+					}
+				}
+				return &GetJobResultsOutput{Results: results}, nil
+			},
+		}
+	}
+
+	cache := NewResultCache(client, NewMemoryResultCacheStore(), 0).WithPollInterval(time.Millisecond)
+	submission := func(inputs map[string]TextInputItem) *SubmitJobTextInput {
+		return &SubmitJobTextInput{ModelIdentifier: "modelID", ModelVersion: "1.0.0", Inputs: inputs}
+	}
+
+	first, err := cache.Run(context.TODO(), submission(map[string]TextInputItem{
+		"one": {"input.txt": "1"},
+		"bad": {"input.txt": "bad"},
+	}))
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if first.Job == nil || len(first.CachedInputs) != 0 {
+		t.Errorf("first run should not use the cache: %+v", first)
+	}
+	if first.Results.Completed != 1 || first.Results.Failed != 1 || first.Results.Total != 2 {
+		t.Errorf("first run counts not expected: %+v", first.Results)
+	}
+
+	second, err := cache.Run(context.TODO(), submission(map[string]TextInputItem{
+		"renamed": {"input.txt": "1"},
+		"bad":     {"input.txt": "bad"},
+		"two":     {"input.txt": "2"},
+	}))
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if strings.Join(second.CachedInputs, ",") != "renamed" {
+		t.Errorf("cached inputs not expected: %v", second.CachedInputs)
+	}
+	if strings.Join(submitted[1].InputKeys(), ",") != "bad,two" {
+		t.Errorf("only misses should be submitted: %v", submitted[1].InputKeys())
+	}
+	if submitted[1].ModelIdentifier != "modelID" || submitted[1].ModelVersion != "1.0.0" {
+		t.Errorf("model not kept: %+v", submitted[1])
+	}
+	if second.Results.Results["renamed"].Engine != "job-1" || second.Results.Results["two"].Engine != "job-2" {
+		t.Errorf("results not merged: %+v", second.Results.Results)
+	}
+	if second.Results.Completed != 2 || second.Results.Failed != 1 || second.Results.Total != 3 {
+		t.Errorf("second run counts not expected: %+v", second.Results)
+	}
+
+	third, err := cache.Run(context.TODO(), submission(map[string]TextInputItem{
+		"two": {"input.txt": "2"},
+	}))
+	if err != nil {
+		t.Fatalf("err not nil: %v", err)
+	}
+	if third.Job != nil || len(submitted) != 2 {
+		t.Errorf("fully cached run should not submit a job")
+	}
+	if third.Results.Completed != 1 {
+		t.Errorf("third run counts not expected: %+v", third.Results)
+	}
+}
+
+func TestResultCacheRunSubmitError(t *testing.T) {
+	client := &ClientFake{
+		JobsFunc: func() JobsClient {
+			return &JobsClientFake{
+				SubmitJobTextFunc: func(ctx context.Context, input *SubmitJobTextInput) (*SubmitJobTextOutput, error) {
+					return nil, fmt.Errorf("nope")
+				},
+			}
+		},
+	}
+	_, err := NewResultCache(client, NewMemoryResultCacheStore(), time.Hour).Run(context.TODO(), &SubmitJobTextInput{
+		Inputs: map[string]TextInputItem{"a": {"input.txt": "a"}},
+	})
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+type failingSetResultCacheStore struct {
+	ResultCacheStore
+}
+
+func (s failingSetResultCacheStore) Set(ctx context.Context, key string, result model.JobResult, ttl time.Duration) error {
+	return fmt.Errorf("store is full")
+}
+
+func TestResultCacheRunSetError(t *testing.T) {
+	submission := &SubmitJobTextInput{
+		ModelIdentifier: "modelID",
+		ModelVersion:    "1.0.0",
+		Inputs: map[string]TextInputItem{
+			"hit":  {"input.txt": "hit"},
+			"miss": {"input.txt": "miss"},
+		},
+	}
+	_, hashes, _ := submission.hashInputs()
+	store := NewMemoryResultCacheStore()
+	_ = store.Set(context.TODO(), resultCacheKey(submission.cacheScope(), hashes["hit"]), model.JobResult{Status: "SUCCESSFUL", Engine: "cached"}, 0)
+
+	client := &ClientFake{}
+	client.JobsFunc = func() JobsClient {
+		return &JobsClientFake{
+			SubmitJobTextFunc: func(ctx context.Context, input *SubmitJobTextInput) (*SubmitJobTextOutput, error) {
+				return &SubmitJobTextOutput{JobActions: NewJobActions(client, "jobID")}, nil
+			},
+			WaitForJobCompletionFunc: func(ctx context.Context, input *WaitForJobCompletionInput, pollInterval time.Duration) (*GetJobDetailsOutput, error) {
+				return &GetJobDetailsOutput{}, nil
+			},
+			GetJobResultsFunc: func(ctx context.Context, input *GetJobResultsInput) (*GetJobResultsOutput, error) {
+				return &GetJobResultsOutput{Results: model.JobResults{
+					Results: map[string]model.JobResult{"miss": {Status: "SUCCESSFUL", Engine: "job"}},
+				}}, nil
+			},
+		}
+	}
+
+	out, err := NewResultCache(client, failingSetResultCacheStore{store}, 0).WithPollInterval(time.Millisecond).Run(context.TODO(), submission)
+	if err == nil || !strings.Contains(err.Error(), "failed to cache the result of input miss") {
+		t.Errorf("expected a cache error: %v", err)
+	}
+	if out == nil || out.Job == nil {
+		t.Fatalf("output should be returned with the submitted job")
+	}
+	if out.Results.Results["hit"].Engine != "cached" || out.Results.Results["miss"].Engine != "job" || out.Results.Completed != 2 {
+		t.Errorf("results not complete: %+v", out.Results)
+	}
+}
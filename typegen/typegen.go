@@ -0,0 +1,292 @@
+// Package typegen writes Go types for the outputs of a model, inferred from a sample of that output, along with a
+// helper that decodes a model.JobResult into them.
+//
+// The generated code is sorted and formatted so that regenerating it after a new model version is released shows any
+// change to the output in a code review:
+//
+//	sample, err := client.Models().GetModelVersionSampleOutput(ctx, &modzy.GetModelVersionSampleOutputInput{ModelID: "ed542963de", Version: "1.0.1"})
+//	output, err := typegen.SelectOutput([]byte(sample.Sample), "results.json")
+//	code, err := typegen.Generate(output, typegen.Options{Package: "sentiment", TypeName: "Sentiment"})
+//
+// The cmd/modzy-typegen command does the same from a go:generate directive.
+package typegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultOutputName is the output that types are generated for when no name is provided
+const DefaultOutputName = "results.json"
+
+type Options struct {
+	// Package is the name of the package of the generated file
+	Package string
+	// TypeName is the name of the type for the whole output; nested objects are named after it and their field
+	TypeName string
+	// OutputName is the output decoded by the generated helper; defaults to DefaultOutputName
+	OutputName string
+	// ModelID and Version are only used to describe the source in the generated header
+	ModelID string
+	Version string
+}
+
+// resultMetadataFields are the keys of a job result that are not outputs
+var resultMetadataFields = map[string]bool{
+	"status":      true,
+	"engine":      true,
+	"error":       true,
+	"startTime":   true,
+	"updateTime":  true,
+	"endTime":     true,
+	"elapsedTime": true,
+}
+
+// SelectOutput finds the named output within a sample output.  Samples are found either as the results of a job
+// (keyed by "results" and then by input), as the outputs of a single input (keyed by output name), or as the content
+// of the output itself.
+func SelectOutput(sample []byte, outputName string) ([]byte, error) {
+	if outputName == "" {
+		outputName = DefaultOutputName
+	}
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(sample, &top); err != nil {
+		var anything interface{}
+		if err := json.Unmarshal(sample, &anything); err != nil {
+			return nil, errors.WithMessage(err, "sample output is not json")
+		}
+		return sample, nil
+	}
+	if output, ok := top[outputName]; ok {
+		return output, nil
+	}
+	if rawResults, ok := top["results"]; ok {
+		var results map[string]map[string]json.RawMessage
+		if err := json.Unmarshal(rawResults, &results); err == nil && len(results) > 0 {
+			inputs := make([]string, 0, len(results))
+			for input := range results {
+				inputs = append(inputs, input)
+			}
+			sort.Strings(inputs)
+			if output, ok := results[inputs[0]][outputName]; ok {
+				return output, nil
+			}
+		}
+	}
+	// a result of a single input that does not have the output is most likely a mistake in the output name
+	for key := range top {
+		if !resultMetadataFields[key] && strings.Contains(key, ".") {
+			return nil, errors.Errorf("sample output does not have an output named %s", outputName)
+		}
+	}
+	return sample, nil
+}
+
+// Generate writes a formatted Go file with types for the sample output and a Decode<TypeName> helper
+func Generate(output []byte, options Options) ([]byte, error) {
+	if options.Package == "" || options.TypeName == "" {
+		return nil, errors.New("a package and type name are required")
+	}
+	if options.OutputName == "" {
+		options.OutputName = DefaultOutputName
+	}
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, errors.WithMessage(err, "sample output is not json")
+	}
+
+	g := &generator{names: map[string]bool{}}
+	if top := infer(value); top.kind == kindObject {
+		g.declareStruct(options.TypeName, top)
+	} else {
+		// the output is not an object, so any objects within it are named as items of the type
+		g.names[options.TypeName] = true
+		typ := g.goType(options.TypeName+"Item", top)
+		g.decls = append([]string{fmt.Sprintf("type %s %s\n", options.TypeName, typ)}, g.decls...)
+	}
+
+	var buf bytes.Buffer
+	source := "a sample output"
+	if options.ModelID != "" {
+		source = fmt.Sprintf("the sample output of model %s version %s", options.ModelID, options.Version)
+	}
+	fmt.Fprintf(&buf, "// Code generated by modzy-typegen from %s; DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&buf, "package %s\n\n", options.Package)
+	fmt.Fprintf(&buf, "import \"github.com/modzy/sdk-go/model\"\n\n")
+	for _, decl := range g.decls {
+		buf.WriteString(decl)
+		buf.WriteString("\n")
+	}
+	fmt.Fprintf(&buf, "// Decode%s decodes the %s output of a job result\n", options.TypeName, options.OutputName)
+	fmt.Fprintf(&buf, "func Decode%s(result model.JobResult) (%s, error) {\n", options.TypeName, options.TypeName)
+	fmt.Fprintf(&buf, "\treturn model.DecodeOutput[%s](result, %q)\n}\n", options.TypeName, options.OutputName)
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to format the generated code")
+	}
+	return formatted, nil
+}
+
+type jsonKind int
+
+const (
+	kindNull jsonKind = iota
+	kindBool
+	kindNumber
+	kindString
+	kindObject
+	kindArray
+	kindMixed
+)
+
+// jsonType is the shape of a json value, merged across every element of the arrays it was found in
+type jsonType struct {
+	kind   jsonKind
+	fields map[string]*jsonType
+	elem   *jsonType
+}
+
+func infer(value interface{}) *jsonType {
+	switch v := value.(type) {
+	case bool:
+		return &jsonType{kind: kindBool}
+	case json.Number:
+		return &jsonType{kind: kindNumber}
+	case string:
+		return &jsonType{kind: kindString}
+	case map[string]interface{}:
+		t := &jsonType{kind: kindObject, fields: map[string]*jsonType{}}
+		for k, field := range v {
+			t.fields[k] = infer(field)
+		}
+		return t
+	case []interface{}:
+		t := &jsonType{kind: kindArray, elem: &jsonType{kind: kindNull}}
+		for _, elem := range v {
+			t.elem = merge(t.elem, infer(elem))
+		}
+		return t
+	}
+	return &jsonType{kind: kindNull}
+}
+
+func merge(a *jsonType, b *jsonType) *jsonType {
+	switch {
+	case a.kind == kindNull:
+		return b
+	case b.kind == kindNull:
+		return a
+	case a.kind != b.kind:
+		return &jsonType{kind: kindMixed}
+	case a.kind == kindObject:
+		merged := &jsonType{kind: kindObject, fields: map[string]*jsonType{}}
+		for k, field := range a.fields {
+			merged.fields[k] = field
+		}
+		for k, field := range b.fields {
+			if existing, ok := merged.fields[k]; ok {
+				merged.fields[k] = merge(existing, field)
+			} else {
+				merged.fields[k] = field
+			}
+		}
+		return merged
+	case a.kind == kindArray:
+		return &jsonType{kind: kindArray, elem: merge(a.elem, b.elem)}
+	}
+	return a
+}
+
+type generator struct {
+	decls []string
+	names map[string]bool
+}
+
+// goType returns the Go type for t, declaring a struct named name if t is an object
+func (g *generator) goType(name string, t *jsonType) string {
+	switch t.kind {
+	case kindBool:
+		return "bool"
+	case kindNumber:
+		return "float64"
+	case kindString:
+		return "string"
+	case kindArray:
+		return "[]" + g.goType(name, t.elem)
+	case kindObject:
+		return g.declareStruct(name, t)
+	}
+	return "interface{}"
+}
+
+func (g *generator) declareStruct(name string, t *jsonType) string {
+	name = uniqueName(name, g.names)
+	// reserve the declaration so that nested types are written after their parent
+	index := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	keys := make([]string, 0, len(t.fields))
+	for k := range t.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "type %s struct {\n", name)
+	fieldNames := map[string]bool{}
+	for _, k := range keys {
+		fieldName := uniqueName(exportedName(k), fieldNames)
+		fmt.Fprintf(&buf, "\t%s %s `json:%q`\n", fieldName, g.goType(name+fieldName, t.fields[k]), k)
+	}
+	buf.WriteString("}\n")
+	g.decls[index] = buf.String()
+	return name
+}
+
+func uniqueName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// commonInitialisms are written in upper case, as golint expects
+var commonInitialisms = map[string]bool{
+	"id": true, "url": true, "uri": true, "json": true, "http": true, "api": true, "xml": true, "csv": true,
+}
+
+// exportedName turns a json key such as "bounding_box" or "classPredictions" into an exported Go identifier
+func exportedName(key string) string {
+	parts := strings.FieldsFunc(key, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var name strings.Builder
+	for _, part := range parts {
+		if commonInitialisms[strings.ToLower(part)] {
+			name.WriteString(strings.ToUpper(part))
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		name.WriteString(string(runes))
+	}
+	if name.Len() == 0 {
+		return "Field"
+	}
+	if first := []rune(name.String())[0]; !unicode.IsLetter(first) {
+		return "Field" + name.String()
+	}
+	return name.String()
+}
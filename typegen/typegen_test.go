@@ -0,0 +1,145 @@
+package typegen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	sample := `{
+		"data": {
+			"result": {
+				"classPredictions": [
+					{"class": "positive", "score": 0.9},
+					{"class": "negative", "score": 0, "explanation": null, "bounding_box": {"x": 1}}
+				]
+			}
+		},
+		"model_id": "abc",
+		"tags": [],
+		"ready": true,
+		"3d": "x",
+		"mixed": [1, "a"]
+	}`
+
+	code, err := Generate([]byte(sample), Options{Package: "sentiment", TypeName: "Sentiment", ModelID: "abc", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "// Code generated by modzy-typegen from the sample output of model abc version 1.0.0; DO NOT EDIT.\n" + `
+package sentiment
+
+import "github.com/modzy/sdk-go/model"
+
+type Sentiment struct {
+	Field3d string        ` + "`json:\"3d\"`" + `
+	Data    SentimentData ` + "`json:\"data\"`" + `
+	Mixed   []interface{} ` + "`json:\"mixed\"`" + `
+	ModelID string        ` + "`json:\"model_id\"`" + `
+	Ready   bool          ` + "`json:\"ready\"`" + `
+	Tags    []interface{} ` + "`json:\"tags\"`" + `
+}
+
+type SentimentData struct {
+	Result SentimentDataResult ` + "`json:\"result\"`" + `
+}
+
+type SentimentDataResult struct {
+	ClassPredictions []SentimentDataResultClassPredictions ` + "`json:\"classPredictions\"`" + `
+}
+
+type SentimentDataResultClassPredictions struct {
+	BoundingBox SentimentDataResultClassPredictionsBoundingBox ` + "`json:\"bounding_box\"`" + `
+	Class       string                                         ` + "`json:\"class\"`" + `
+	Explanation interface{}                                    ` + "`json:\"explanation\"`" + `
+	Score       float64                                        ` + "`json:\"score\"`" + `
+}
+
+type SentimentDataResultClassPredictionsBoundingBox struct {
+	X float64 ` + "`json:\"x\"`" + `
+}
+
+// DecodeSentiment decodes the results.json output of a job result
+func DecodeSentiment(result model.JobResult) (Sentiment, error) {
+	return model.DecodeOutput[Sentiment](result, "results.json")
+}
+`
+	if string(code) != expected {
+		t.Errorf("Generated code not expected:\n%s", code)
+	}
+}
+
+func TestGenerateArrayOutput(t *testing.T) {
+	code, err := Generate([]byte(`[{"label": "cat"}]`), Options{Package: "p", TypeName: "Labels", OutputName: "labels.json"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, expected := range []string{
+		"from a sample output; DO NOT EDIT.",
+		"type Labels []LabelsItem",
+		"type LabelsItem struct {",
+		`model.DecodeOutput[Labels](result, "labels.json")`,
+	} {
+		if !strings.Contains(string(code), expected) {
+			t.Errorf("Expected %q in:\n%s", expected, code)
+		}
+	}
+	if strings.Index(string(code), "type Labels ") > strings.Index(string(code), "type LabelsItem ") {
+		t.Errorf("Expected the output type to be declared first:\n%s", code)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	if _, err := Generate([]byte(`{}`), Options{}); err == nil {
+		t.Errorf("Expected an error without a package and type name")
+	}
+	if _, err := Generate([]byte(`{`), Options{Package: "p", TypeName: "T"}); err == nil {
+		t.Errorf("Expected an error for invalid json")
+	}
+}
+
+func TestSelectOutput(t *testing.T) {
+	cases := []struct {
+		sample   string
+		expected string
+	}{
+		{`{"results.json": {"a": 1}, "status": "SUCCESSFUL"}`, `{"a": 1}`},
+		{`{"results": {"b": {"results.json": {"b": 1}}, "a": {"results.json": {"a": 1}}}}`, `{"a": 1}`},
+		{`{"a": 1}`, `{"a": 1}`},
+		{`[1]`, `[1]`},
+	}
+	for _, c := range cases {
+		output, err := SelectOutput([]byte(c.sample), "")
+		if err != nil {
+			t.Errorf("Expected no error for %s, got %v", c.sample, err)
+			continue
+		}
+		if string(output) != c.expected {
+			t.Errorf("Expected %s from %s, got %s", c.expected, c.sample, output)
+		}
+	}
+
+	if _, err := SelectOutput([]byte(`{"other.json": {}}`), "results.json"); err == nil {
+		t.Errorf("Expected an error when only other outputs exist")
+	}
+	if _, err := SelectOutput([]byte(`nope`), "results.json"); err == nil {
+		t.Errorf("Expected an error for invalid json")
+	}
+}
+
+func TestExportedName(t *testing.T) {
+	cases := map[string]string{
+		"bounding_box":     "BoundingBox",
+		"classPredictions": "ClassPredictions",
+		"url":              "URL",
+		"user-id":          "UserID",
+		"3d":               "Field3d",
+		"":                 "Field",
+		"é":                "É",
+	}
+	for key, expected := range cases {
+		if got := exportedName(key); got != expected {
+			t.Errorf("Expected %q for %q, got %q", expected, key, got)
+		}
+	}
+}
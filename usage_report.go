@@ -0,0 +1,306 @@
+package modzy
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultUsageReportProjects = 4
+	bytesPerGigabyte           = 1e9
+	// minimumUsageReportRange is the shortest range the dashboard metrics cover
+	minimumUsageReportRange = 7 * 24 * time.Hour
+)
+
+// UsageUnitPrices are the prices charged for each unit of usage.  Any price left at zero is not charged.
+type UsageUnitPrices struct {
+	PerPrediction float64 `json:"perPrediction"`
+	// PerGigabyte is charged for every 10^9 bytes of data processed
+	PerGigabyte float64 `json:"perGigabyte"`
+	PerJob      float64 `json:"perJob"`
+}
+
+type UsagePricing struct {
+	Default UsageUnitPrices `json:"default"`
+	// Models overrides the default prices for the model identifiers it contains
+	Models map[string]UsageUnitPrices `json:"models,omitempty"`
+	// Currency is only used as a label in the report
+	Currency string `json:"currency,omitempty"`
+}
+
+func (p UsagePricing) pricesFor(modelID string) UsageUnitPrices {
+	if prices, ok := p.Models[modelID]; ok {
+		return prices
+	}
+	return p.Default
+}
+
+type UsageReportInput struct {
+	// Start and End are the dates the usage is reported for.  They must be at least 7 days apart, as the dashboard
+	// metrics never cover less and would otherwise not match the job history.
+	Start time.Time
+	End   time.Time
+	// ProjectIDs limits the report to these projects; all projects are reported if empty
+	ProjectIDs []string
+	Pricing    UsagePricing
+	// Concurrency is the number of projects read at the same time; defaults to 4
+	Concurrency int
+}
+
+type UsageTotals struct {
+	Jobs int `json:"jobs"`
+	// Inputs is the number of inputs submitted in those jobs
+	Inputs         int     `json:"inputs"`
+	Predictions    int64   `json:"predictions"`
+	BytesProcessed int64   `json:"bytesProcessed"`
+	Cost           float64 `json:"cost"`
+}
+
+func (t *UsageTotals) add(other UsageTotals) {
+	t.Jobs += other.Jobs
+	t.Inputs += other.Inputs
+	t.Predictions += other.Predictions
+	t.BytesProcessed += other.BytesProcessed
+	t.Cost += other.Cost
+}
+
+func (t UsageTotals) costAt(prices UsageUnitPrices) float64 {
+	return float64(t.Predictions)*prices.PerPrediction +
+		float64(t.BytesProcessed)/bytesPerGigabyte*prices.PerGigabyte +
+		float64(t.Jobs)*prices.PerJob
+}
+
+type ModelUsage struct {
+	ModelID   string `json:"modelIdentifier"`
+	ModelName string `json:"modelName"`
+	UsageTotals
+}
+
+type ProjectUsage struct {
+	ProjectID   string   `json:"projectIdentifier"`
+	ProjectName string   `json:"projectName"`
+	AccessKeys  []string `json:"accessKeys"`
+	// UsageTotals are the dashboard totals of the project's access keys.  Its cost includes any usage the dashboard
+	// reports beyond what is attributed to models, charged at the default prices.
+	UsageTotals
+	Models []ModelUsage `json:"models"`
+}
+
+// UsageReport is the usage of each project and model over a date range, costed with the provided pricing
+type UsageReport struct {
+	Start    time.Time      `json:"start"`
+	End      time.Time      `json:"end"`
+	Pricing  UsagePricing   `json:"pricing"`
+	Projects []ProjectUsage `json:"projects"`
+	// Models are the totals of each model across all of the projects
+	Models []ModelUsage `json:"models"`
+	Total  UsageTotals  `json:"total"`
+}
+
+// BuildUsageReport joins the access keys of each project with the dashboard's predictions made and data processed, and
+// the job history, to report usage per project and per model.  Jobs are grouped by the model they were submitted to,
+// and the dashboard metrics of each model are read by filtering on both the access key and the model.
+func BuildUsageReport(ctx context.Context, client Client, input *UsageReportInput) (*UsageReport, error) {
+	if input.End.Sub(input.Start) < minimumUsageReportRange {
+		return nil, errors.Errorf("usage can only be reported for at least 7 days, got %s to %s", input.Start.Format(time.RFC3339), input.End.Format(time.RFC3339))
+	}
+
+	projectIDs := input.ProjectIDs
+	if len(projectIDs) == 0 {
+		projects, err := NewListProjectsPager(client.Accounting(), &ListProjectsInput{}).ListAll(ctx, 0)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to list projects")
+		}
+		for _, project := range projects {
+			projectIDs = append(projectIDs, project.Identifier)
+		}
+	}
+
+	concurrency := input.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultUsageReportProjects
+	}
+	projects := make([]ProjectUsage, len(projectIDs))
+	err := forEachConcurrently(ctx, len(projectIDs), concurrency, func(ctx context.Context, i int) error {
+		usage, err := projectUsage(ctx, client, projectIDs[i], input)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to report project %s", projectIDs[i])
+		}
+		projects[i] = usage
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &UsageReport{
+		Start:    input.Start,
+		End:      input.End,
+		Pricing:  input.Pricing,
+		Projects: projects,
+	}
+	models := map[string]*ModelUsage{}
+	for _, project := range projects {
+		report.Total.add(project.UsageTotals)
+		for _, usage := range project.Models {
+			if _, ok := models[usage.ModelID]; !ok {
+				models[usage.ModelID] = &ModelUsage{ModelID: usage.ModelID, ModelName: usage.ModelName}
+			}
+			models[usage.ModelID].add(usage.UsageTotals)
+		}
+	}
+	report.Models = sortedModelUsage(models)
+	return report, nil
+}
+
+func projectUsage(ctx context.Context, client Client, projectID string, input *UsageReportInput) (ProjectUsage, error) {
+	project, err := client.Accounting().GetProjectDetails(ctx, &GetProjectDetailsInput{ProjectID: projectID})
+	if err != nil {
+		return ProjectUsage{}, err
+	}
+	usage := ProjectUsage{
+		ProjectID:   project.Project.Identifier,
+		ProjectName: project.Project.Name,
+		AccessKeys:  []string{},
+	}
+
+	models := map[string]*ModelUsage{}
+	for _, key := range project.Project.AccessKeys {
+		usage.AccessKeys = append(usage.AccessKeys, key.Prefix)
+		predictions, bytes, err := dashboardUsage(ctx, client, input, key.Prefix, "")
+		if err != nil {
+			return ProjectUsage{}, err
+		}
+		usage.Predictions += predictions
+		usage.BytesProcessed += bytes
+
+		jobs, err := NewListJobsHistoryPager(client.Jobs(), (&ListJobsHistoryInput{}).WithAccessKey(key.Prefix).WithDateRange(input.Start, input.End)).ListAll(ctx, 0)
+		if err != nil {
+			return ProjectUsage{}, errors.WithMessagef(err, "failed to read the job history of access key %s", key.Prefix)
+		}
+		keyModels := map[string]*ModelUsage{}
+		for _, job := range jobs {
+			modelUsage, ok := keyModels[job.Model.Identifier]
+			if !ok {
+				modelUsage = &ModelUsage{ModelID: job.Model.Identifier, ModelName: job.Model.Name}
+				keyModels[job.Model.Identifier] = modelUsage
+			}
+			modelUsage.Jobs++
+			modelUsage.Inputs += job.Total
+		}
+		for modelID, keyUsage := range keyModels {
+			keyUsage.Predictions, keyUsage.BytesProcessed, err = dashboardUsage(ctx, client, input, key.Prefix, modelID)
+			if err != nil {
+				return ProjectUsage{}, err
+			}
+			if _, ok := models[modelID]; !ok {
+				models[modelID] = &ModelUsage{ModelID: modelID, ModelName: keyUsage.ModelName}
+			}
+			models[modelID].add(keyUsage.UsageTotals)
+		}
+	}
+
+	// anything the dashboard reports for the project that is not attributed to a model is charged at the default prices
+	var attributed UsageTotals
+	for modelID, modelUsage := range models {
+		modelUsage.Cost = modelUsage.costAt(input.Pricing.pricesFor(modelID))
+		attributed.add(modelUsage.UsageTotals)
+	}
+	usage.Jobs = attributed.Jobs
+	usage.Inputs = attributed.Inputs
+	unattributed := UsageTotals{
+		Predictions:    max(usage.Predictions-attributed.Predictions, 0),
+		BytesProcessed: max(usage.BytesProcessed-attributed.BytesProcessed, 0),
+	}
+	usage.Cost = attributed.Cost + unattributed.costAt(input.Pricing.Default)
+	usage.Models = sortedModelUsage(models)
+	return usage, nil
+}
+
+// dashboardUsage reads the predictions made and bytes processed by an access key, optionally limited to a model
+func dashboardUsage(ctx context.Context, client Client, input *UsageReportInput, accessKey string, modelID string) (int64, int64, error) {
+	predictions, err := client.Dashboard().GetPredictionsMade(ctx, &GetPredictionsMadeInput{
+		BeginDate:       model.ModzyDate{Time: input.Start},
+		EndDate:         model.ModzyDate{Time: input.End},
+		AccessKeyPrefix: accessKey,
+		ModelIdentifier: modelID,
+	})
+	if err != nil {
+		return 0, 0, errors.WithMessagef(err, "failed to read the predictions made by access key %s", accessKey)
+	}
+	processed, err := client.Dashboard().GetDataProcessed(ctx, &GetDataProcessedInput{
+		BeginDate:       model.ModzyDate{Time: input.Start},
+		EndDate:         model.ModzyDate{Time: input.End},
+		AccessKeyPrefix: accessKey,
+		ModelIdentifier: modelID,
+	})
+	if err != nil {
+		return 0, 0, errors.WithMessagef(err, "failed to read the data processed by access key %s", accessKey)
+	}
+	return predictions.Summary.RecentPredictions, processed.Summary.RecentBytes, nil
+}
+
+func sortedModelUsage(models map[string]*ModelUsage) []ModelUsage {
+	out := []ModelUsage{}
+	for _, usage := range models {
+		out = append(out, *usage)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ModelID < out[j].ModelID
+	})
+	return out
+}
+
+// WriteJSON writes the report as indented json
+func (r *UsageReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// usageReportColumns are the columns written by WriteCSV
+var usageReportColumns = []string{"project", "projectName", "model", "modelName", "jobs", "inputs", "predictions", "bytesProcessed", "cost"}
+
+// WriteCSV writes a row for each project followed by a row for each of its models, then a row for each model across
+// all projects with an empty project, and finally the total with both the project and the model empty.
+func (r *UsageReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{usageReportColumns}
+	for _, project := range r.Projects {
+		rows = append(rows, usageRow(project.ProjectID, project.ProjectName, "", "", project.UsageTotals))
+		for _, usage := range project.Models {
+			rows = append(rows, usageRow(project.ProjectID, project.ProjectName, usage.ModelID, usage.ModelName, usage.UsageTotals))
+		}
+	}
+	for _, usage := range r.Models {
+		rows = append(rows, usageRow("", "", usage.ModelID, usage.ModelName, usage.UsageTotals))
+	}
+	rows = append(rows, usageRow("", "", "", "", r.Total))
+
+	if err := writer.WriteAll(rows); err != nil {
+		return errors.WithMessage(err, "failed to write the usage report")
+	}
+	return nil
+}
+
+func usageRow(projectID string, projectName string, modelID string, modelName string, totals UsageTotals) []string {
+	return []string{
+		projectID,
+		projectName,
+		modelID,
+		modelName,
+		strconv.Itoa(totals.Jobs),
+		strconv.Itoa(totals.Inputs),
+		strconv.FormatInt(totals.Predictions, 10),
+		strconv.FormatInt(totals.BytesProcessed, 10),
+		strconv.FormatFloat(totals.Cost, 'f', 2, 64),
+	}
+}
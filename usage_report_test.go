@@ -0,0 +1,325 @@
+package modzy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+)
+
+func TestBuildUsageReport(t *testing.T) {
+	keys := map[string][]string{"p1": {"k1", "k2"}, "p2": {"k3"}}
+	jobs := map[string][]model.JobDetails{
+		"k1": {
+			{Model: model.ModelNamedIdentifier{Identifier: "m1", Name: "Model 1"}, Total: 3},
+			{Model: model.ModelNamedIdentifier{Identifier: "m1", Name: "Model 1"}, Total: 2},
+			{Model: model.ModelNamedIdentifier{Identifier: "m2", Name: "Model 2"}, Total: 5},
+		},
+		"k2": {{Model: model.ModelNamedIdentifier{Identifier: "m1", Name: "Model 1"}, Total: 1}},
+		"k3": {{Model: model.ModelNamedIdentifier{Identifier: "m2", Name: "Model 2"}, Total: 4}},
+	}
+	// keyed by access key and model, where an empty model is the total of the key
+	predictions := map[string]int64{"k1/": 20, "k1/m1": 5, "k1/m2": 10, "k2/": 1, "k2/m1": 1, "k3/": 4, "k3/m2": 4}
+	processed := map[string]int64{"k1/": 3e9, "k1/m1": 1e9, "k1/m2": 1e9, "k3/": 2e9, "k3/m2": 2e9}
+
+	accounting := &AccountingClientFake{
+		ListProjectsFunc: func(ctx context.Context, input *ListProjectsInput) (*ListProjectsOutput, error) {
+			return &ListProjectsOutput{Projects: []model.AccountingProject{{Identifier: "p1"}, {Identifier: "p2"}}}, nil
+		},
+		GetProjectDetailsFunc: func(ctx context.Context, input *GetProjectDetailsInput) (*GetProjectDetailsOutput, error) {
+			project := model.AccountingProject{Identifier: input.ProjectID, Name: "Project " + input.ProjectID}
+			for _, prefix := range keys[input.ProjectID] {
+				project.AccessKeys = append(project.AccessKeys, model.AccessKey{Prefix: prefix})
+			}
+			return &GetProjectDetailsOutput{Project: project}, nil
+		},
+	}
+	jobsClient := &JobsClientFake{
+		ListJobsHistoryFunc: func(ctx context.Context, input *ListJobsHistoryInput) (*ListJobsHistoryOutput, error) {
+			accessKey, dated := "", false
+			for _, filter := range input.Paging.Filters {
+				switch ListJobsHistoryFilterField(filter.Field) {
+				case ListJobsHistoryFilterFieldAccessKey:
+					accessKey = filter.Values[0]
+				case ListJobsHistoryFilterFieldStartDate:
+					dated = true
+				}
+			}
+			if !dated {
+				t.Errorf("Expected the job history to be filtered by date")
+			}
+			return &ListJobsHistoryOutput{Jobs: jobs[accessKey]}, nil
+		},
+	}
+	dashboard := &DashboardClientFake{
+		GetPredictionsMadeFunc: func(ctx context.Context, input *GetPredictionsMadeInput) (*GetPredictionsMadeOutput, error) {
+			if input.BeginDate.Day() != 1 || input.EndDate.Day() != 31 {
+				t.Errorf("Expected the report dates, got %v - %v", input.BeginDate, input.EndDate)
+			}
+			return &GetPredictionsMadeOutput{Summary: model.PredictionsMadeSummary{
+				RecentPredictions: predictions[input.AccessKeyPrefix+"/"+input.ModelIdentifier],
+			}}, nil
+		},
+		GetDataProcessedFunc: func(ctx context.Context, input *GetDataProcessedInput) (*GetDataProcessedOutput, error) {
+			if input.BeginDate.Day() != 1 || input.EndDate.Day() != 31 {
+				t.Errorf("Expected the report dates, got %v - %v", input.BeginDate, input.EndDate)
+			}
+			return &GetDataProcessedOutput{Summary: model.DataProcessedSummary{
+				RecentBytes: processed[input.AccessKeyPrefix+"/"+input.ModelIdentifier],
+			}}, nil
+		},
+	}
+	client := &ClientFake{
+		AccountingFunc: func() AccountingClient { return accounting },
+		JobsFunc:       func() JobsClient { return jobsClient },
+		DashboardFunc:  func() DashboardClient { return dashboard },
+	}
+
+	report, err := BuildUsageReport(context.TODO(), client, &UsageReportInput{
+		Start: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC),
+		Pricing: UsagePricing{
+			Default: UsageUnitPrices{PerPrediction: 0.1, PerGigabyte: 1, PerJob: 0.5},
+			Models:  map[string]UsageUnitPrices{"m2": {PerPrediction: 0.2, PerGigabyte: 1}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(report.Projects) != 2 || report.Projects[0].ProjectID != "p1" || len(report.Projects[0].AccessKeys) != 2 {
+		t.Fatalf("Expected both projects in order, got %+v", report.Projects)
+	}
+	p1 := report.Projects[0]
+	if len(p1.Models) != 2 || p1.Models[0].ModelID != "m1" || p1.Models[0].ModelName != "Model 1" {
+		t.Fatalf("Expected the models of p1, got %+v", p1.Models)
+	}
+	if len(report.Models) != 2 {
+		t.Fatalf("Expected the models across projects, got %+v", report.Models)
+	}
+
+	tests := []struct {
+		name     string
+		actual   UsageTotals
+		expected UsageTotals
+	}{
+		{"p1/m1", p1.Models[0].UsageTotals, UsageTotals{Jobs: 3, Inputs: 6, Predictions: 6, BytesProcessed: 1e9, Cost: 3.1}},
+		{"p1/m2", p1.Models[1].UsageTotals, UsageTotals{Jobs: 1, Inputs: 5, Predictions: 10, BytesProcessed: 1e9, Cost: 3}},
+		// the 5 predictions and 1GB that are not attributed to a model are charged at the default prices
+		{"p1", p1.UsageTotals, UsageTotals{Jobs: 4, Inputs: 11, Predictions: 21, BytesProcessed: 3e9, Cost: 7.6}},
+		{"p2", report.Projects[1].UsageTotals, UsageTotals{Jobs: 1, Inputs: 4, Predictions: 4, BytesProcessed: 2e9, Cost: 2.8}},
+		{"m2", report.Models[1].UsageTotals, UsageTotals{Jobs: 2, Inputs: 9, Predictions: 14, BytesProcessed: 3e9, Cost: 5.8}},
+		{"total", report.Total, UsageTotals{Jobs: 5, Inputs: 15, Predictions: 25, BytesProcessed: 5e9, Cost: 10.4}},
+	}
+	for _, test := range tests {
+		actual, expected := test.actual, test.expected
+		if actual.Jobs != expected.Jobs || actual.Inputs != expected.Inputs || actual.Predictions != expected.Predictions ||
+			actual.BytesProcessed != expected.BytesProcessed || math.Abs(actual.Cost-expected.Cost) > 1e-9 {
+			t.Errorf("%s: expected %+v, got %+v", test.name, expected, actual)
+		}
+	}
+}
+
+func TestBuildUsageReportSelectedProjects(t *testing.T) {
+	client := &ClientFake{
+		AccountingFunc: func() AccountingClient {
+			return &AccountingClientFake{
+				GetProjectDetailsFunc: func(ctx context.Context, input *GetProjectDetailsInput) (*GetProjectDetailsOutput, error) {
+					return &GetProjectDetailsOutput{Project: model.AccountingProject{Identifier: input.ProjectID}}, nil
+				},
+			}
+		},
+	}
+	report, err := BuildUsageReport(context.TODO(), client, &UsageReportInput{
+		Start:      time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:        time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC),
+		ProjectIDs: []string{"p2"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(report.Projects) != 1 || report.Projects[0].ProjectID != "p2" {
+		t.Errorf("Expected only p2, got %+v", report.Projects)
+	}
+}
+
+func TestBuildUsageReportError(t *testing.T) {
+	client := &ClientFake{
+		AccountingFunc: func() AccountingClient {
+			return &AccountingClientFake{
+				GetProjectDetailsFunc: func(ctx context.Context, input *GetProjectDetailsInput) (*GetProjectDetailsOutput, error) {
+					return &GetProjectDetailsOutput{Project: model.AccountingProject{
+						Identifier: input.ProjectID,
+						AccessKeys: []model.AccessKey{{Prefix: "k1"}},
+					}}, nil
+				},
+			}
+		},
+		DashboardFunc: func() DashboardClient {
+			return &DashboardClientFake{
+				GetPredictionsMadeFunc: func(ctx context.Context, input *GetPredictionsMadeInput) (*GetPredictionsMadeOutput, error) {
+					return &GetPredictionsMadeOutput{}, nil
+				},
+				GetDataProcessedFunc: func(ctx context.Context, input *GetDataProcessedInput) (*GetDataProcessedOutput, error) {
+					return nil, fmt.Errorf("nope")
+				},
+			}
+		},
+	}
+	_, err := BuildUsageReport(context.TODO(), client, &UsageReportInput{
+		Start:      time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:        time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC),
+		ProjectIDs: []string{"p1"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Errorf("Expected the dashboard error, got %v", err)
+	}
+}
+
+func TestBuildUsageReportShortRange(t *testing.T) {
+	client := &ClientFake{}
+	_, err := BuildUsageReport(context.TODO(), client, &UsageReportInput{
+		Start: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2022, 1, 7, 0, 0, 0, 0, time.UTC),
+	})
+	if err == nil || !strings.Contains(err.Error(), "at least 7 days") {
+		t.Errorf("Expected a range shorter than the dashboard's to fail, got %v", err)
+	}
+}
+
+func TestBuildUsageReportCanceled(t *testing.T) {
+	client := &ClientFake{
+		AccountingFunc: func() AccountingClient {
+			return &AccountingClientFake{
+				GetProjectDetailsFunc: func(ctx context.Context, input *GetProjectDetailsInput) (*GetProjectDetailsOutput, error) {
+					return &GetProjectDetailsOutput{Project: model.AccountingProject{Identifier: input.ProjectID}}, nil
+				},
+			}
+		},
+	}
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	_, err := BuildUsageReport(ctx, client, &UsageReportInput{
+		Start:      time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:        time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC),
+		ProjectIDs: []string{"p1", "p2"},
+	})
+	if err != context.Canceled {
+		t.Errorf("Expected the context error, got %v", err)
+	}
+}
+
+func TestUsageReportWriteCSV(t *testing.T) {
+	client := &ClientFake{
+		AccountingFunc: func() AccountingClient {
+			return &AccountingClientFake{
+				GetProjectDetailsFunc: func(ctx context.Context, input *GetProjectDetailsInput) (*GetProjectDetailsOutput, error) {
+					return &GetProjectDetailsOutput{Project: model.AccountingProject{
+						Identifier: "p2",
+						Name:       "Project p2",
+						AccessKeys: []model.AccessKey{{Prefix: "k3"}},
+					}}, nil
+				},
+			}
+		},
+		JobsFunc: func() JobsClient {
+			return &JobsClientFake{
+				ListJobsHistoryFunc: func(ctx context.Context, input *ListJobsHistoryInput) (*ListJobsHistoryOutput, error) {
+					return &ListJobsHistoryOutput{Jobs: []model.JobDetails{
+						{Model: model.ModelNamedIdentifier{Identifier: "m2", Name: "Model 2"}, Total: 4},
+					}}, nil
+				},
+			}
+		},
+		DashboardFunc: func() DashboardClient {
+			return &DashboardClientFake{
+				GetPredictionsMadeFunc: func(ctx context.Context, input *GetPredictionsMadeInput) (*GetPredictionsMadeOutput, error) {
+					return &GetPredictionsMadeOutput{Summary: model.PredictionsMadeSummary{RecentPredictions: 4}}, nil
+				},
+				GetDataProcessedFunc: func(ctx context.Context, input *GetDataProcessedInput) (*GetDataProcessedOutput, error) {
+					return &GetDataProcessedOutput{Summary: model.DataProcessedSummary{RecentBytes: 2e9}}, nil
+				},
+			}
+		},
+	}
+	report, _ := BuildUsageReport(context.TODO(), client, &UsageReportInput{
+		Start:      time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:        time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC),
+		ProjectIDs: []string{"p2"},
+		Pricing: UsagePricing{
+			Default: UsageUnitPrices{PerPrediction: 0.1, PerGigabyte: 1, PerJob: 0.5},
+			Models:  map[string]UsageUnitPrices{"m2": {PerPrediction: 0.2, PerGigabyte: 1}},
+		},
+	})
+
+	var out bytes.Buffer
+	if err := report.WriteCSV(&out); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "project,projectName,model,modelName,jobs,inputs,predictions,bytesProcessed,cost\n" +
+		"p2,Project p2,,,1,4,4,2000000000,2.80\n" +
+		"p2,Project p2,m2,Model 2,1,4,4,2000000000,2.80\n" +
+		",,m2,Model 2,1,4,4,2000000000,2.80\n" +
+		",,,,1,4,4,2000000000,2.80\n"
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestUsageReportWriteJSON(t *testing.T) {
+	client := &ClientFake{
+		AccountingFunc: func() AccountingClient {
+			return &AccountingClientFake{
+				GetProjectDetailsFunc: func(ctx context.Context, input *GetProjectDetailsInput) (*GetProjectDetailsOutput, error) {
+					return &GetProjectDetailsOutput{Project: model.AccountingProject{
+						Identifier: "p2",
+						Name:       "Project p2",
+						AccessKeys: []model.AccessKey{{Prefix: "k3"}},
+					}}, nil
+				},
+			}
+		},
+		JobsFunc: func() JobsClient {
+			return &JobsClientFake{
+				ListJobsHistoryFunc: func(ctx context.Context, input *ListJobsHistoryInput) (*ListJobsHistoryOutput, error) {
+					return &ListJobsHistoryOutput{Jobs: []model.JobDetails{
+						{Model: model.ModelNamedIdentifier{Identifier: "m2", Name: "Model 2"}, Total: 4},
+					}}, nil
+				},
+			}
+		},
+		DashboardFunc: func() DashboardClient {
+			return &DashboardClientFake{
+				GetPredictionsMadeFunc: func(ctx context.Context, input *GetPredictionsMadeInput) (*GetPredictionsMadeOutput, error) {
+					return &GetPredictionsMadeOutput{Summary: model.PredictionsMadeSummary{RecentPredictions: 4}}, nil
+				},
+				GetDataProcessedFunc: func(ctx context.Context, input *GetDataProcessedInput) (*GetDataProcessedOutput, error) {
+					return &GetDataProcessedOutput{Summary: model.DataProcessedSummary{RecentBytes: 2e9}}, nil
+				},
+			}
+		},
+	}
+	report, _ := BuildUsageReport(context.TODO(), client, &UsageReportInput{
+		Start:      time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:        time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC),
+		ProjectIDs: []string{"p2"},
+		Pricing: UsagePricing{
+			Default: UsageUnitPrices{PerPrediction: 0.1, PerGigabyte: 1, PerJob: 0.5},
+			Models:  map[string]UsageUnitPrices{"m2": {PerPrediction: 0.2, PerGigabyte: 1}},
+		},
+	})
+
+	var out bytes.Buffer
+	if err := report.WriteJSON(&out); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, expected := range []string{`"projectIdentifier": "p2"`, `"modelIdentifier": "m2"`, `"bytesProcessed": 2000000000`, `"perPrediction": 0.2`} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %s in:\n%s", expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package modzy
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+// VersionResolver turns a model version reference into a concrete version that is active and available.
+//
+// References are a model identifier or name, followed by `@` and either `latest` or a version constraint:
+//
+//	ed542963de@latest
+//	Sentiment Analysis@~1.2
+//	ed542963de@>=0.3 <1.0
+//
+// Constraints may be separated by spaces or commas.  `~1.2` allows patch and minor updates within 1.2 (>=1.2.0 <1.3.0),
+// and any operator understood by hashicorp/go-version is also accepted (=, !=, >, <, >=, <=, ~>).
+// Pre-release versions are skipped by `latest` and only match constraints that name a pre-release.
+//
+// Resolved references are cached for the provided ttl; a ttl of zero disables caching.
+type VersionResolver struct {
+	client ModelsClient
+	ttl    time.Duration
+
+	lock  sync.Mutex
+	cache map[string]resolvedVersion
+}
+
+type resolvedVersion struct {
+	identifier model.ModelIdentifier
+	expiresAt  time.Time
+}
+
+func NewVersionResolver(client ModelsClient, ttl time.Duration) *VersionResolver {
+	return &VersionResolver{
+		client: client,
+		ttl:    ttl,
+		cache:  map[string]resolvedVersion{},
+	}
+}
+
+// Resolve returns the model identifier and the highest active and available version that satisfies the reference.
+// If no version matches the returned error wraps ErrNoMatchingVersion.
+func (r *VersionResolver) Resolve(ctx context.Context, reference string) (*model.ModelIdentifier, error) {
+	if cached, ok := r.cached(reference); ok {
+		return &cached, nil
+	}
+
+	modelRef, spec := reference, "latest"
+	if at := strings.LastIndex(reference, "@"); at >= 0 {
+		modelRef, spec = reference[:at], reference[at+1:]
+	}
+	modelRef = strings.TrimSpace(modelRef)
+	if modelRef == "" {
+		return nil, fmt.Errorf("version reference '%s' does not name a model", reference)
+	}
+	constraints, err := parseVersionConstraints(spec)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "version reference '%s' has an invalid constraint", reference)
+	}
+
+	modelID, err := r.modelID(ctx, modelRef)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := NewListModelVersionsPager(r.client, &ListModelVersionsInput{ModelID: modelID}).ListAll(ctx, 0)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to list the versions of model %s", modelID)
+	}
+	candidates := []*version.Version{}
+	for _, v := range versions {
+		parsed, err := version.NewVersion(v.Version)
+		if err != nil {
+			// versions that are not semantic can not satisfy a constraint
+			continue
+		}
+		if constraints == nil && parsed.Prerelease() != "" {
+			continue
+		}
+		if constraints != nil && !constraints.Check(parsed) {
+			continue
+		}
+		candidates = append(candidates, parsed)
+	}
+	sort.Sort(sort.Reverse(version.Collection(candidates)))
+
+	for _, candidate := range candidates {
+		details, err := r.client.GetModelVersionDetails(ctx, &GetModelVersionDetailsInput{ModelID: modelID, Version: candidate.Original()})
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to read version %s of model %s", candidate.Original(), modelID)
+		}
+		if !details.Details.IsActive || !details.Details.IsAvailable {
+			continue
+		}
+		resolved := model.ModelIdentifier{Identifier: modelID, Version: candidate.Original()}
+		r.store(reference, resolved)
+		return &resolved, nil
+	}
+	return nil, errors.WithMessagef(ErrNoMatchingVersion, "version reference '%s'", reference)
+}
+
+// Forget removes any cached resolution of the reference
+func (r *VersionResolver) Forget(reference string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.cache, reference)
+}
+
+func (r *VersionResolver) cached(reference string) (model.ModelIdentifier, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	cached, has := r.cache[reference]
+	if !has {
+		return model.ModelIdentifier{}, false
+	}
+	if time.Now().After(cached.expiresAt) {
+		delete(r.cache, reference)
+		return model.ModelIdentifier{}, false
+	}
+	return cached.identifier, true
+}
+
+func (r *VersionResolver) store(reference string, identifier model.ModelIdentifier) {
+	if r.ttl <= 0 {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.cache[reference] = resolvedVersion{identifier: identifier, expiresAt: time.Now().Add(r.ttl)}
+}
+
+// modelID looks up the reference as a model identifier, and then as a model name
+func (r *VersionResolver) modelID(ctx context.Context, modelRef string) (string, error) {
+	details, err := r.client.GetModelDetails(ctx, &GetModelDetailsInput{ModelID: modelRef})
+	if err == nil {
+		return details.Details.ModelID, nil
+	}
+	// names are not valid identifiers, which may be answered with either a not found or a bad request
+	if cause := errors.Cause(err); cause != ErrNotFound && cause != ErrBadRequest {
+		return "", errors.WithMessagef(err, "failed to read model %s", modelRef)
+	}
+	details, err = r.client.GetModelDetailsByName(ctx, &GetModelDetailsByNameInput{Name: modelRef})
+	if err != nil {
+		return "", errors.WithMessagef(err, "failed to find a model with the identifier or name %s", modelRef)
+	}
+	return details.Details.ModelID, nil
+}
+
+var versionConstraintRegexp = regexp.MustCompile(`(~>|>=|<=|!=|=|>|<|~)?\s*([^\s,<>=!~]+)`)
+
+// parseVersionConstraints converts the constraint part of a reference into go-version constraints.  Nil is returned for latest.
+func parseVersionConstraints(spec string) (version.Constraints, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || spec == "latest" {
+		return nil, nil
+	}
+
+	parts := []string{}
+	for _, match := range versionConstraintRegexp.FindAllStringSubmatch(spec, -1) {
+		operator, raw := match[1], match[2]
+		if operator != "~" {
+			parts = append(parts, operator+raw)
+			continue
+		}
+		// ~1.2 and ~1.2.3 both allow updates until the next minor version; ~1 allows updates until the next major version
+		v, err := version.NewVersion(raw)
+		if err != nil {
+			return nil, err
+		}
+		segments := v.Segments()
+		upper := fmt.Sprintf("<%d.%d.0", segments[0], segments[1]+1)
+		if strings.Count(raw, ".") == 0 {
+			upper = fmt.Sprintf("<%d.0.0", segments[0]+1)
+		}
+		parts = append(parts, ">="+raw, upper)
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("'%s' is not a version constraint", spec)
+	}
+	return version.NewConstraint(strings.Join(parts, ","))
+}
@@ -0,0 +1,165 @@
+package modzy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/modzy/sdk-go/model"
+	"github.com/pkg/errors"
+)
+
+func TestVersionResolverResolve(t *testing.T) {
+	client := &ModelsClientFake{
+		GetModelDetailsFunc: func(ctx context.Context, input *GetModelDetailsInput) (*GetModelDetailsOutput, error) {
+			if input.ModelID != "modelID" {
+				return nil, &ModzyHTTPError{StatusCode: 404}
+			}
+			return &GetModelDetailsOutput{Details: model.ModelDetails{ModelID: "modelID"}}, nil
+		},
+		GetModelDetailsByNameFunc: func(ctx context.Context, input *GetModelDetailsByNameInput) (*GetModelDetailsOutput, error) {
+			if input.Name != "Model Name" {
+				return nil, ErrNotFound
+			}
+			return &GetModelDetailsOutput{Details: model.ModelDetails{ModelID: "modelID"}}, nil
+		},
+		ListModelVersionsFunc: func(ctx context.Context, input *ListModelVersionsInput) (*ListModelVersionsOutput, error) {
+			out := &ListModelVersionsOutput{}
+			for _, v := range []string{"0.1.0", "0.3.0", "0.9.1", "1.0.0", "1.2.0", "1.2.5", "1.3.0", "2.0.0-beta", "not-semver"} {
+				out.Versions = append(out.Versions, model.ModelVersion{Version: v})
+			}
+			return out, nil
+		},
+		GetModelVersionDetailsFunc: func(ctx context.Context, input *GetModelVersionDetailsInput) (*GetModelVersionDetailsOutput, error) {
+			return &GetModelVersionDetailsOutput{Details: model.ModelVersionDetails{
+				Version:     input.Version,
+				IsActive:    input.Version != "1.3.0",
+				IsAvailable: input.Version != "0.9.1",
+			}}, nil
+		},
+	}
+	resolver := NewVersionResolver(client, 0)
+	cases := map[string]string{
+		"modelID@latest":       "1.2.5",
+		"modelID":              "1.2.5",
+		"Model Name@latest":    "1.2.5",
+		"modelID@~1.2":         "1.2.5",
+		"modelID@~1.2.0":       "1.2.5",
+		"modelID@~1":           "1.2.5",
+		"modelID@~0.3":         "0.3.0",
+		"modelID@>=0.3 <1.0":   "0.3.0",
+		"modelID@>= 0.3, <1.0": "0.3.0",
+		"modelID@1.0.0":        "1.0.0",
+		"modelID@~> 1.0":       "1.2.5",
+		"modelID@2.0.0-beta":   "2.0.0-beta",
+	}
+	for reference, expected := range cases {
+		resolved, err := resolver.Resolve(context.TODO(), reference)
+		if err != nil {
+			t.Errorf("%s: err not nil: %v", reference, err)
+			continue
+		}
+		if resolved.Identifier != "modelID" || resolved.Version != expected {
+			t.Errorf("%s: expected %s, got %+v", reference, expected, resolved)
+		}
+	}
+}
+
+func TestVersionResolverErrors(t *testing.T) {
+	client := &ModelsClientFake{
+		GetModelDetailsFunc: func(ctx context.Context, input *GetModelDetailsInput) (*GetModelDetailsOutput, error) {
+			if input.ModelID != "modelID" {
+				return nil, &ModzyHTTPError{StatusCode: 404}
+			}
+			return &GetModelDetailsOutput{Details: model.ModelDetails{ModelID: "modelID"}}, nil
+		},
+		GetModelDetailsByNameFunc: func(ctx context.Context, input *GetModelDetailsByNameInput) (*GetModelDetailsOutput, error) {
+			if input.Name != "Model Name" {
+				return nil, ErrNotFound
+			}
+			return &GetModelDetailsOutput{Details: model.ModelDetails{ModelID: "modelID"}}, nil
+		},
+		ListModelVersionsFunc: func(ctx context.Context, input *ListModelVersionsInput) (*ListModelVersionsOutput, error) {
+			out := &ListModelVersionsOutput{}
+			for _, v := range []string{"0.1.0", "0.3.0", "0.9.1", "1.0.0", "1.2.0", "1.2.5", "1.3.0", "2.0.0-beta", "not-semver"} {
+				out.Versions = append(out.Versions, model.ModelVersion{Version: v})
+			}
+			return out, nil
+		},
+		GetModelVersionDetailsFunc: func(ctx context.Context, input *GetModelVersionDetailsInput) (*GetModelVersionDetailsOutput, error) {
+			return &GetModelVersionDetailsOutput{Details: model.ModelVersionDetails{
+				Version:     input.Version,
+				IsActive:    true,
+				IsAvailable: input.Version != "0.9.1",
+			}}, nil
+		},
+	}
+	resolver := NewVersionResolver(client, 0)
+
+	if _, err := resolver.Resolve(context.TODO(), "modelID@>3.0"); errors.Cause(err) != ErrNoMatchingVersion {
+		t.Errorf("expected ErrNoMatchingVersion, got %v", err)
+	}
+	if _, err := resolver.Resolve(context.TODO(), "modelID@~0.9"); errors.Cause(err) != ErrNoMatchingVersion {
+		t.Errorf("unavailable version should not match: %v", err)
+	}
+	if _, err := resolver.Resolve(context.TODO(), "unknown@latest"); err == nil {
+		t.Errorf("expected an error for an unknown model")
+	}
+	if _, err := resolver.Resolve(context.TODO(), "@latest"); err == nil {
+		t.Errorf("expected an error for a missing model")
+	}
+	if _, err := resolver.Resolve(context.TODO(), "modelID@>=nope"); err == nil {
+		t.Errorf("expected an error for a bad constraint")
+	}
+	if _, err := resolver.Resolve(context.TODO(), "modelID@,"); err == nil {
+		t.Errorf("expected an error for an empty constraint")
+	}
+
+	failing := &ModelsClientFake{
+		GetModelDetailsFunc: func(ctx context.Context, input *GetModelDetailsInput) (*GetModelDetailsOutput, error) {
+			return nil, fmt.Errorf("nope")
+		},
+	}
+	if _, err := NewVersionResolver(failing, 0).Resolve(context.TODO(), "modelID@latest"); err == nil {
+		t.Errorf("expected an error when the model can not be read")
+	}
+}
+
+func TestVersionResolverCache(t *testing.T) {
+	calls := 0
+	client := &ModelsClientFake{
+		GetModelDetailsFunc: func(ctx context.Context, input *GetModelDetailsInput) (*GetModelDetailsOutput, error) {
+			calls++
+			return &GetModelDetailsOutput{Details: model.ModelDetails{ModelID: "modelID"}}, nil
+		},
+		ListModelVersionsFunc: func(ctx context.Context, input *ListModelVersionsInput) (*ListModelVersionsOutput, error) {
+			return &ListModelVersionsOutput{Versions: []model.ModelVersion{{Version: "1.0.0"}}}, nil
+		},
+		GetModelVersionDetailsFunc: func(ctx context.Context, input *GetModelVersionDetailsInput) (*GetModelVersionDetailsOutput, error) {
+			return &GetModelVersionDetailsOutput{Details: model.ModelVersionDetails{Version: input.Version, IsActive: true, IsAvailable: true}}, nil
+		},
+	}
+	resolver := NewVersionResolver(client, time.Hour)
+	for i := 0; i < 3; i++ {
+		if _, err := resolver.Resolve(context.TODO(), "modelID@latest"); err != nil {
+			t.Fatalf("err not nil: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the resolution to be cached, got %d calls", calls)
+	}
+	resolver.Forget("modelID@latest")
+	resolver.Resolve(context.TODO(), "modelID@latest")
+	if calls != 2 {
+		t.Errorf("expected the resolution to be forgotten, got %d calls", calls)
+	}
+
+	expiring := NewVersionResolver(client, time.Nanosecond)
+	expiring.Resolve(context.TODO(), "modelID@latest")
+	time.Sleep(time.Millisecond)
+	expiring.Resolve(context.TODO(), "modelID@latest")
+	if calls != 4 {
+		t.Errorf("expected the resolution to expire, got %d calls", calls)
+	}
+}